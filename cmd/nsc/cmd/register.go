@@ -65,11 +65,13 @@ func RegisterCommands(root *cobra.Command) {
 	root.AddCommand(cluster.NewBazelCmd())
 	root.AddCommand(cluster.NewGradleCmd())
 	root.AddCommand(cluster.NewPantsCmd())
+	root.AddCommand(cluster.NewGoCmd())
 	cacheCmd := &cobra.Command{Use: "cache", Short: "Build cache related functionality."}
 	cacheCmd.AddCommand(cluster.NewSccacheCmd())
 	cacheCmd.AddCommand(cluster.NewGradleCacheCmd())
 	cacheCmd.AddCommand(cluster.NewBazelCacheCmd())
 	cacheCmd.AddCommand(cluster.NewPantsCacheCmd())
+	cacheCmd.AddCommand(cluster.NewGoCacheCmd())
 	root.AddCommand(cacheCmd)
 	root.AddCommand(cluster.NewArtifactCmd()) // nsc artifact
 
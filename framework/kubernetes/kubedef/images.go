@@ -0,0 +1,92 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package kubedef
+
+import (
+	"encoding/json"
+
+	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/schema"
+)
+
+// ImagesOf returns the images referenced by the resource which the invocation
+// applies or creates: those of its containers, and its configuration image.
+// Returns nil for invocations which don't apply resources.
+func ImagesOf(inv *schema.SerializedInvocation) ([]string, error) {
+	var body string
+
+	switch {
+	case inv.GetImpl().MessageIs(&OpApply{}):
+		op := &OpApply{}
+		if err := inv.Impl.UnmarshalTo(op); err != nil {
+			return nil, err
+		}
+		body = op.BodyJson
+
+	case inv.GetImpl().MessageIs(&OpCreate{}):
+		op := &OpCreate{}
+		if err := inv.Impl.UnmarshalTo(op); err != nil {
+			return nil, err
+		}
+		body = op.BodyJson
+
+	case inv.GetImpl().MessageIs(&OpEnsureDeployment{}):
+		op := &OpEnsureDeployment{}
+		if err := inv.Impl.UnmarshalTo(op); err != nil {
+			return nil, err
+		}
+		body = op.SerializedResource
+
+	default:
+		return nil, nil
+	}
+
+	if body == "" {
+		return nil, nil
+	}
+
+	var resource any
+	if err := json.Unmarshal([]byte(body), &resource); err != nil {
+		return nil, fnerrors.InternalError("%s: failed to parse resource: %w", inv.Description, err)
+	}
+
+	var images []string
+	collectImages(resource, &images)
+	return images, nil
+}
+
+func collectImages(value any, images *[]string) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			switch key {
+			case "containers", "initContainers", "ephemeralContainers":
+				if containers, ok := child.([]any); ok {
+					for _, container := range containers {
+						if c, ok := container.(map[string]any); ok {
+							if image, ok := c["image"].(string); ok && image != "" {
+								*images = append(*images, image)
+							}
+						}
+					}
+				}
+
+			case "annotations":
+				if annotations, ok := child.(map[string]any); ok {
+					if image, ok := annotations[K8sConfigImage].(string); ok && image != "" {
+						*images = append(*images, image)
+					}
+				}
+			}
+
+			collectImages(child, images)
+		}
+
+	case []any:
+		for _, child := range v {
+			collectImages(child, images)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package kubedef
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestImagesOf(t *testing.T) {
+	inv, err := (Apply{
+		Description: "Server Deployment",
+		SerializedResource: `{
+	"kind": "Deployment",
+	"metadata": {"annotations": {"k8s.namespacelabs.dev/config-image": "registry.example.com/config@sha256:cfg"}},
+	"spec": {"template": {"spec": {
+		"initContainers": [{"name": "init", "image": "registry.example.com/init@sha256:init"}],
+		"containers": [
+			{"name": "server", "image": "registry.example.com/server@sha256:srv"},
+			{"name": "sidecar", "image": "registry.example.com/sidecar@sha256:side"}
+		]
+	}}}
+}`,
+	}).ToDefinition()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	images, err := ImagesOf(inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(images)
+
+	if d := cmp.Diff([]string{
+		"registry.example.com/config@sha256:cfg",
+		"registry.example.com/init@sha256:init",
+		"registry.example.com/server@sha256:srv",
+		"registry.example.com/sidecar@sha256:side",
+	}, images); d != "" {
+		t.Errorf("unexpected images (-want +got):\n%s", d)
+	}
+}
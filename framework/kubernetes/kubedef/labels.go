@@ -30,9 +30,14 @@ const (
 	K8sKind               = "k8s.namespacelabs.dev/kind"
 	K8sRuntimeConfig      = "k8s.namespacelabs.dev/runtime-config"
 	K8sPlannerVersion     = "k8s.namespacelabs.dev/planner-version"
-
-	K8sStaticConfigKind  = "static-config"
-	K8sRuntimeConfigKind = "runtime-config"
+	K8sDeployRevision     = "k8s.namespacelabs.dev/deploy-revision"
+	K8sDeployLockHolder   = "k8s.namespacelabs.dev/deploy-lock-holder"
+	K8sDeployLockReason   = "k8s.namespacelabs.dev/deploy-lock-reason"
+	K8sDeployLockManual   = "k8s.namespacelabs.dev/deploy-lock-manual"
+
+	K8sStaticConfigKind   = "static-config"
+	K8sRuntimeConfigKind  = "runtime-config"
+	K8sDeployRevisionKind = "deploy-revision"
 
 	AppKubernetesIoManagedBy = "app.kubernetes.io/managed-by"
 	KubernetesIoArch         = "kubernetes.io/arch"
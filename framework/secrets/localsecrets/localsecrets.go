@@ -92,9 +92,9 @@ func (l *localSecrets) Load(ctx context.Context, modules pkggraph.ModuleResolver
 func (l *localSecrets) MissingError(missing *schema.PackageRef, missingSpec *schema.SecretSpec, missingServer schema.PackageName) error {
 	label := fmt.Sprintf("\n  # Description: %s\n  # Server: %s\n  ns secrets set --secret %s", missingSpec.Description, missingServer, missing.Canonical())
 
-	return fnerrors.UsageError(
+	return fnerrors.WithCode(fnerrors.CodeMissingSecret, fnerrors.UsageError(
 		fmt.Sprintf("Please run:\n%s", label),
-		"There are secrets required which have not been specified")
+		"There are secrets required which have not been specified"))
 }
 
 func (l *localSecrets) loadSecretsFor(ctx context.Context, modules pkggraph.ModuleResolver, moduleName, secretFile string) (*Bundle, error) {
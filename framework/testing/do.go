@@ -14,6 +14,7 @@ import (
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"namespacelabs.dev/foundation/framework/resources"
 	"namespacelabs.dev/foundation/internal/testing/testboot"
 	"namespacelabs.dev/foundation/schema"
 )
@@ -25,6 +26,8 @@ var (
 
 type Test struct {
 	testboot.TestData
+
+	faults *faultChannel
 }
 
 func (t Test) NewClient(endpoint *schema.Endpoint) (*grpc.ClientConn, error) {
@@ -70,10 +73,29 @@ func (t Test) InternalOf(serverOwner string) []*schema.InternalEndpoint {
 	return filtered
 }
 
+// Resources returns the resources which the test declared, e.g. the
+// database instances that the servers under test also use.
+func (t Test) Resources() (*resources.Parsed, error) {
+	return resources.LoadResources()
+}
+
+// MustResource unmarshals the instance of the specified resource (e.g.
+// "namespacelabs.dev/foo/bar:mainDB") into out, e.g. a *postgres.DatabaseInstance.
+func (t Test) MustResource(ref string, out any) {
+	res, err := t.Resources()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := res.Unmarshal(ref, out); err != nil {
+		log.Fatalf("Expected resource %q to be available to the test: %v", ref, err)
+	}
+}
+
 func Do(testFunc func(context.Context, Test) error) {
 	t := testboot.BootstrapTest(*testTimeout, *debug)
 
-	if err := testFunc(context.Background(), Test{t}); err != nil {
+	if err := testFunc(context.Background(), Test{TestData: t, faults: listenForFaults()}); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -0,0 +1,183 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package testing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"namespacelabs.dev/foundation/internal/testing/testboot"
+)
+
+// Faults are injected into servers of the stack under test (identified by
+// their package name) by the test runner, through the runtime; the test driver
+// itself is granted no additional permissions.
+
+// RestartServer gracefully terminates each instance of the server, which is
+// then replaced.
+func (t Test) RestartServer(ctx context.Context, owner string) error {
+	_, err := t.injectFault(ctx, testboot.FaultRequest{Kind: testboot.FaultRestart, Server: owner})
+	return err
+}
+
+// KillServer terminates each instance of the server without a grace period,
+// which is then replaced.
+func (t Test) KillServer(ctx context.Context, owner string) error {
+	_, err := t.injectFault(ctx, testboot.FaultRequest{Kind: testboot.FaultKill, Server: owner})
+	return err
+}
+
+// PauseServer cuts each instance of the server off from all network traffic,
+// in and out, until the returned function is called. Its processes are not
+// suspended: kubelet probes still pass, and established connections may
+// survive. Fails if the cluster doesn't enforce network policies.
+func (t Test) PauseServer(ctx context.Context, owner string) (func(context.Context) error, error) {
+	return t.injectFault(ctx, testboot.FaultRequest{Kind: testboot.FaultPause, Server: owner})
+}
+
+// BlockTraffic drops new connections from one server to another, until the
+// returned function is called. Fails if the cluster doesn't enforce network
+// policies.
+func (t Test) BlockTraffic(ctx context.Context, from, to string) (func(context.Context) error, error) {
+	return t.injectFault(ctx, testboot.FaultRequest{Kind: testboot.FaultBlockTraffic, Server: to, Source: from})
+}
+
+// BeginLameduck begins the lameduck phase of each instance of the server,
+// without terminating it.
+func (t Test) BeginLameduck(ctx context.Context, owner string) error {
+	internal := t.InternalOf(owner)
+	if len(internal) == 0 {
+		return fmt.Errorf("%s: no internal endpoint to begin lameduck through", owner)
+	}
+
+	_, err := t.injectFault(ctx, testboot.FaultRequest{Kind: testboot.FaultLameduck, Server: owner, AdminPort: internal[0].GetPort().GetContainerPort()})
+	return err
+}
+
+func (t Test) injectFault(ctx context.Context, req testboot.FaultRequest) (func(context.Context) error, error) {
+	resp, err := t.faults.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.Revertible {
+		return nil, nil
+	}
+
+	return func(ctx context.Context) error {
+		_, err := t.faults.do(ctx, testboot.FaultRequest{Kind: testboot.FaultRevert, Revert: resp.ID})
+		return err
+	}, nil
+}
+
+// How long to wait for the test runner to connect, when the first fault is
+// requested.
+const runnerConnectTimeout = time.Minute
+
+type faultChannel struct {
+	lis *net.TCPListener
+	err error
+
+	mu     sync.Mutex // Serializes requests.
+	conn   net.Conn
+	dec    *json.Decoder
+	nextID int64
+}
+
+func listenForFaults() *faultChannel {
+	lis, err := net.ListenTCP("tcp", &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: testboot.FaultsPort})
+	return &faultChannel{lis: lis, err: err}
+}
+
+func (fc *faultChannel) do(ctx context.Context, req testboot.FaultRequest) (testboot.FaultResponse, error) {
+	if fc == nil || fc.err != nil {
+		return testboot.FaultResponse{}, fmt.Errorf("faults can't be injected: %w", fc.unavailable())
+	}
+
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	if fc.conn == nil {
+		conn, err := fc.accept(ctx)
+		if err != nil {
+			return testboot.FaultResponse{}, err
+		}
+
+		fc.conn = conn
+		fc.dec = json.NewDecoder(conn)
+	}
+
+	// Unblock reads and writes if the context is canceled.
+	stop := context.AfterFunc(ctx, func() { _ = fc.conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	fc.nextID++
+	req.ID = fc.nextID
+
+	var resp testboot.FaultResponse
+	if err := json.NewEncoder(fc.conn).Encode(req); err != nil {
+		fc.reset()
+		return resp, fmt.Errorf("failed to send fault request: %w", err)
+	}
+
+	if err := fc.dec.Decode(&resp); err != nil {
+		fc.reset()
+		return resp, fmt.Errorf("failed to receive fault response: %w", err)
+	}
+
+	if resp.ID != req.ID {
+		fc.reset()
+		return resp, fmt.Errorf("unexpected fault response: expected id %d, got %d", req.ID, resp.ID)
+	}
+
+	if resp.Error != "" {
+		return resp, fmt.Errorf("%s: %s: %s", req.Server, req.Kind, resp.Error)
+	}
+
+	return resp, nil
+}
+
+func (fc *faultChannel) accept(ctx context.Context) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, runnerConnectTimeout)
+	defer cancel()
+
+	if err := fc.lis.SetDeadline(time.Time{}); err != nil {
+		return nil, err
+	}
+
+	stop := context.AfterFunc(ctx, func() { _ = fc.lis.SetDeadline(time.Now()) })
+	defer stop()
+
+	conn, err := fc.lis.Accept()
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, fmt.Errorf("the test runner did not connect to inject faults: %w", ctxErr)
+		}
+
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// reset drops a connection in an unknown state; the runner then reconnects.
+func (fc *faultChannel) reset() {
+	_ = fc.conn.Close()
+	fc.conn = nil
+	fc.dec = nil
+}
+
+func (fc *faultChannel) unavailable() error {
+	if fc == nil {
+		return errors.New("not running under a test runner")
+	}
+
+	return fc.err
+}
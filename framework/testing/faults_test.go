@@ -0,0 +1,125 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package testing
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"namespacelabs.dev/foundation/internal/testing/testboot"
+)
+
+// fakeRunner connects to the channel, and answers each request with the
+// response which respond returns for it.
+func fakeRunner(t *testing.T, addr net.Addr, respond func(testboot.FaultRequest) testboot.FaultResponse) <-chan testboot.FaultRequest {
+	received := make(chan testboot.FaultRequest, 16)
+
+	go func() {
+		defer close(received)
+
+		conn, err := net.Dial("tcp", addr.String())
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+
+		dec := json.NewDecoder(conn)
+		enc := json.NewEncoder(conn)
+
+		for {
+			var req testboot.FaultRequest
+			if err := dec.Decode(&req); err != nil {
+				return
+			}
+
+			received <- req
+
+			if err := enc.Encode(respond(req)); err != nil {
+				return
+			}
+		}
+	}()
+
+	return received
+}
+
+func TestFaultChannel(t *testing.T) {
+	lis, err := net.ListenTCP("tcp", &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+
+	test := Test{faults: &faultChannel{lis: lis}}
+
+	received := fakeRunner(t, lis.Addr(), func(req testboot.FaultRequest) testboot.FaultResponse {
+		switch req.Kind {
+		case testboot.FaultPause:
+			return testboot.FaultResponse{ID: req.ID, Revertible: true}
+		case testboot.FaultKill:
+			return testboot.FaultResponse{ID: req.ID, Error: "no instances found"}
+		}
+
+		return testboot.FaultResponse{ID: req.ID}
+	})
+
+	ctx := context.Background()
+
+	if err := test.RestartServer(ctx, "example.com/a"); err != nil {
+		t.Fatal(err)
+	}
+
+	resume, err := test.PauseServer(ctx, "example.com/b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resume == nil {
+		t.Fatal("expected the pause to be revertible")
+	}
+
+	if err := resume(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := test.KillServer(ctx, "example.com/c"); err == nil || err.Error() != "example.com/c: kill: no instances found" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	test.faults.reset()
+
+	want := []testboot.FaultRequest{
+		{ID: 1, Kind: testboot.FaultRestart, Server: "example.com/a"},
+		{ID: 2, Kind: testboot.FaultPause, Server: "example.com/b"},
+		{ID: 3, Kind: testboot.FaultRevert, Revert: 2},
+		{ID: 4, Kind: testboot.FaultKill, Server: "example.com/c"},
+	}
+
+	var got []testboot.FaultRequest
+	for req := range received {
+		got = append(got, req)
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d requests, want %d", len(got), len(want))
+	}
+
+	for k, req := range got {
+		if req != want[k] {
+			t.Errorf("request #%d: got %+v, want %+v", k, req, want[k])
+		}
+	}
+}
+
+func TestFaultChannelUnavailable(t *testing.T) {
+	var test Test
+
+	if _, err := test.PauseServer(context.Background(), "example.com/a"); err == nil {
+		t.Error("expected an error outside of a test runner")
+	}
+}
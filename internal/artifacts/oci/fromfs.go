@@ -5,12 +5,14 @@
 package oci
 
 import (
+	"compress/gzip"
 	"context"
 	"fmt"
 	"io"
 	"io/fs"
 	"os"
 
+	"github.com/google/go-containerregistry/pkg/compression"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/tarball"
 	"github.com/google/go-containerregistry/pkg/v1/types"
@@ -22,6 +24,10 @@ import (
 	"namespacelabs.dev/foundation/std/tasks"
 )
 
+// Layers built from a filesystem are always compressed with the same settings,
+// so that their digests only depend on their contents.
+const fsLayerCompressionLevel = gzip.BestSpeed
+
 type HasToLayer interface {
 	AsLayer() (v1.Layer, error)
 }
@@ -52,7 +58,7 @@ func LayerFromFS(ctx context.Context, vfs fs.FS) (Layer, error) {
 
 	return tarball.LayerFromOpener(func() (io.ReadCloser, error) {
 		return os.Open(f.Name())
-	}, tarball.WithCompressedCaching)
+	}, tarball.WithCompressedCaching, tarball.WithCompression(compression.GZip), tarball.WithCompressionLevel(fsLayerCompressionLevel))
 }
 
 func LayerFromFile(description string, vfs fs.FS, path string) NamedLayer {
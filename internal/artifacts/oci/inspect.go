@@ -0,0 +1,400 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package oci
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	p "path"
+	"sort"
+	"strings"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// FileEntry is a file, directory or link within a layer.
+type FileEntry struct {
+	Path     string
+	Typeflag byte
+	Size     int64
+	// The sha256 of the contents of regular files, and the target of links.
+	Contents string
+	// Set for whiteouts, which remove Path from the layers below. Opaque
+	// whiteouts remove the previous contents of the directory at Path.
+	Whiteout bool
+}
+
+// LayerInfo describes the contents of a single layer of an image.
+type LayerInfo struct {
+	Digest v1.Hash
+	// The compressed size of the layer, as stored in a registry.
+	Size int64
+	// The sum of the sizes of the layer's files.
+	UncompressedSize int64
+	// The instruction which produced the layer, if the image's history says.
+	CreatedBy string
+	// Sorted by path.
+	Files []FileEntry
+}
+
+// InspectLayers reads every layer of the image, without flattening it.
+func InspectLayers(img v1.Image) ([]LayerInfo, error) {
+	layers, err := img.Layers()
+	if err != nil {
+		return nil, err
+	}
+
+	createdBy, err := layerHistory(img, len(layers))
+	if err != nil {
+		return nil, err
+	}
+
+	var infos []LayerInfo
+	for k, layer := range layers {
+		info, err := inspectLayer(layer)
+		if err != nil {
+			return nil, err
+		}
+
+		info.CreatedBy = createdBy[k]
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+func inspectLayer(layer v1.Layer) (LayerInfo, error) {
+	var info LayerInfo
+
+	digest, err := layer.Digest()
+	if err != nil {
+		return info, err
+	}
+
+	size, err := layer.Size()
+	if err != nil {
+		return info, err
+	}
+
+	info.Digest = digest
+	info.Size = size
+
+	r, err := layer.Uncompressed()
+	if err != nil {
+		return info, err
+	}
+
+	defer r.Close()
+
+	if err := visitTarFiles(r, func(h *tar.Header, reader io.Reader) error {
+		entry := FileEntry{Path: p.Join("/", h.Name), Typeflag: h.Typeflag}
+
+		dir, base := p.Split(entry.Path)
+		if base == whiteoutOpaque {
+			info.Files = append(info.Files, FileEntry{Path: p.Clean(dir), Whiteout: true})
+			return nil
+		} else if strings.HasPrefix(base, whiteoutPrefix) {
+			info.Files = append(info.Files, FileEntry{Path: p.Join(dir, strings.TrimPrefix(base, whiteoutPrefix)), Whiteout: true})
+			return nil
+		}
+
+		switch h.Typeflag {
+		case tar.TypeReg:
+			hf := sha256.New()
+			n, err := io.Copy(hf, reader)
+			if err != nil {
+				return err
+			}
+			entry.Size = n
+			entry.Contents = hex.EncodeToString(hf.Sum(nil))
+
+		case tar.TypeLink, tar.TypeSymlink:
+			entry.Contents = h.Linkname
+		}
+
+		info.UncompressedSize += entry.Size
+		info.Files = append(info.Files, entry)
+		return nil
+	}); err != nil {
+		return info, err
+	}
+
+	sort.SliceStable(info.Files, func(i, j int) bool {
+		return info.Files[i].Path < info.Files[j].Path
+	})
+
+	return info, nil
+}
+
+const (
+	whiteoutPrefix = ".wh."
+	whiteoutOpaque = whiteoutPrefix + whiteoutPrefix + ".opq"
+)
+
+// layerHistory returns, for each layer, the history entry's CreatedBy. The
+// history also includes entries which didn't produce a layer, and those are
+// skipped. If the history doesn't line up with the layers, it's ignored.
+func layerHistory(img v1.Image, count int) ([]string, error) {
+	config, err := img.ConfigFile()
+	if err != nil {
+		return nil, err
+	}
+
+	createdBy := make([]string, count)
+
+	var nonEmpty []v1.History
+	for _, h := range config.History {
+		if !h.EmptyLayer {
+			nonEmpty = append(nonEmpty, h)
+		}
+	}
+
+	if len(nonEmpty) == count {
+		for k, h := range nonEmpty {
+			createdBy[k] = h.CreatedBy
+		}
+	}
+
+	return createdBy, nil
+}
+
+type ChangeKind string
+
+const (
+	Added   ChangeKind = "added"
+	Removed ChangeKind = "removed"
+	Changed ChangeKind = "changed"
+)
+
+// ConfigChange is a difference in the configuration of two images.
+type ConfigChange struct {
+	Kind   ChangeKind
+	Field  string
+	Before string
+	After  string
+}
+
+// DiffConfigs compares the parts of the image configuration that affect how
+// a container runs: environment, entrypoint, command, working directory,
+// user, exposed ports, and labels.
+func DiffConfigs(a, b v1.Config) []ConfigChange {
+	var changes []ConfigChange
+
+	diffValue := func(field, before, after string) {
+		if before != after {
+			changes = append(changes, ConfigChange{Kind: kindOf(before != "", after != ""), Field: field, Before: before, After: after})
+		}
+	}
+
+	diffMap := func(field string, before, after map[string]string) {
+		keys := maps.Keys(before)
+		for k := range after {
+			if _, ok := before[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		slices.Sort(keys)
+
+		for _, k := range keys {
+			x, hadX := before[k]
+			y, hadY := after[k]
+			if hadX != hadY || x != y {
+				changes = append(changes, ConfigChange{Kind: kindOf(hadX, hadY), Field: fmt.Sprintf("%s %s", field, k), Before: x, After: y})
+			}
+		}
+	}
+
+	diffMap("env", envMap(a.Env), envMap(b.Env))
+	diffValue("entrypoint", quoteAll(a.Entrypoint), quoteAll(b.Entrypoint))
+	diffValue("cmd", quoteAll(a.Cmd), quoteAll(b.Cmd))
+	diffValue("workdir", a.WorkingDir, b.WorkingDir)
+	diffValue("user", a.User, b.User)
+	diffValue("ports", strings.Join(sortedSetKeys(a.ExposedPorts), " "), strings.Join(sortedSetKeys(b.ExposedPorts), " "))
+	diffMap("label", a.Labels, b.Labels)
+
+	return changes
+}
+
+func kindOf(before, after bool) ChangeKind {
+	switch {
+	case !before:
+		return Added
+	case !after:
+		return Removed
+	default:
+		return Changed
+	}
+}
+
+func envMap(env []string) map[string]string {
+	m := map[string]string{}
+	for _, kv := range env {
+		k, v, _ := strings.Cut(kv, "=")
+		m[k] = v
+	}
+	return m
+}
+
+func quoteAll(args []string) string {
+	var quoted []string
+	for _, arg := range args {
+		quoted = append(quoted, fmt.Sprintf("%q", arg))
+	}
+	return strings.Join(quoted, " ")
+}
+
+func sortedSetKeys(m map[string]struct{}) []string {
+	keys := maps.Keys(m)
+	slices.Sort(keys)
+	return keys
+}
+
+// FileChange is a difference in a file between two layers.
+type FileChange struct {
+	Kind   ChangeKind
+	Path   string
+	Before *FileEntry
+	After  *FileEntry
+}
+
+// DiffFiles compares two sorted lists of files. Whiteouts are reported as
+// removals of the paths they remove.
+func DiffFiles(a, b []FileEntry) []FileChange {
+	var changes []FileChange
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case j >= len(b) || (i < len(a) && a[i].Path < b[j].Path):
+			changes = append(changes, fileChange(&a[i], nil))
+			i++
+
+		case i >= len(a) || b[j].Path < a[i].Path:
+			changes = append(changes, fileChange(nil, &b[j]))
+			j++
+
+		default:
+			if a[i] != b[j] {
+				changes = append(changes, fileChange(&a[i], &b[j]))
+			}
+			i++
+			j++
+		}
+	}
+
+	return changes
+}
+
+func fileChange(before, after *FileEntry) FileChange {
+	c := FileChange{Path: pathOf(before, after), Before: before, After: after}
+
+	switch {
+	case after != nil && after.Whiteout:
+		// The path is now removed.
+		c.Kind = Removed
+	case before != nil && before.Whiteout:
+		// The path is no longer removed.
+		c.Kind = Added
+	default:
+		c.Kind = kindOf(before != nil, after != nil)
+	}
+
+	return c
+}
+
+func pathOf(before, after *FileEntry) string {
+	if before != nil {
+		return before.Path
+	}
+	return after.Path
+}
+
+// LayerDiff is a difference between two images' layers. Before or After are
+// nil if a layer only exists in one of the images.
+type LayerDiff struct {
+	// Indexes of the layers in each image; only set if Before or After are.
+	BeforeIndex int
+	AfterIndex  int
+	Before      *LayerInfo
+	After       *LayerInfo
+	Files       []FileChange
+}
+
+// DiffLayers compares the layers of two images. Layers are aligned by digest,
+// along their longest common subsequence, so that inserting or removing a
+// layer doesn't make every layer above it differ. Between aligned layers,
+// the remaining layers are compared position by position. Layers which are
+// identical in both images are not included.
+func DiffLayers(a, b []LayerInfo) []LayerDiff {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i].Digest == b[j].Digest {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var diffs []LayerDiff
+	var unmatchedA, unmatchedB []int
+
+	flush := func() {
+		for k := 0; k < len(unmatchedA) || k < len(unmatchedB); k++ {
+			var d LayerDiff
+			var before, after []FileEntry
+
+			if k < len(unmatchedA) {
+				d.BeforeIndex = unmatchedA[k]
+				d.Before = &a[d.BeforeIndex]
+				before = d.Before.Files
+			}
+
+			if k < len(unmatchedB) {
+				d.AfterIndex = unmatchedB[k]
+				d.After = &b[d.AfterIndex]
+				after = d.After.Files
+			}
+
+			d.Files = DiffFiles(before, after)
+			diffs = append(diffs, d)
+		}
+
+		unmatchedA, unmatchedB = nil, nil
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i].Digest == b[j].Digest:
+			flush()
+			i++
+			j++
+
+		case j >= len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			unmatchedA = append(unmatchedA, i)
+			i++
+
+		default:
+			unmatchedB = append(unmatchedB, j)
+			j++
+		}
+	}
+
+	flush()
+
+	return diffs
+}
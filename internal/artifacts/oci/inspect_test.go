@@ -0,0 +1,158 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package oci
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+)
+
+func TestDiffImages(t *testing.T) {
+	base := makeTestLayer(t, map[string]string{"etc/os-release": "alpine"})
+
+	a := makeTestImage(t, []string{"FOO=1", "BAR=2"}, base, makeTestLayer(t, map[string]string{
+		"app/main":   "v1",
+		"app/unused": "remove me",
+	}))
+	b := makeTestImage(t, []string{"FOO=3", "BAR=2"}, base, makeTestLayer(t, map[string]string{
+		"app/main":  "v2 is larger",
+		"app/asset": "new",
+	}))
+
+	configA, err := a.ConfigFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configB, err := b.ConfigFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d := cmp.Diff([]ConfigChange{{Kind: Changed, Field: "env FOO", Before: "1", After: "3"}}, DiffConfigs(configA.Config, configB.Config)); d != "" {
+		t.Errorf("unexpected config changes (-want +got):\n%s", d)
+	}
+
+	layersA, err := InspectLayers(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	layersB, err := InspectLayers(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diffs := DiffLayers(layersA, layersB)
+	if len(diffs) != 1 || diffs[0].BeforeIndex != 1 || diffs[0].AfterIndex != 1 {
+		t.Fatalf("expected only the second layer to differ, got %d diffs", len(diffs))
+	}
+
+	var got []string
+	for _, f := range diffs[0].Files {
+		got = append(got, string(f.Kind)+" "+f.Path)
+	}
+
+	if d := cmp.Diff([]string{"added /app/asset", "changed /app/main", "removed /app/unused"}, got); d != "" {
+		t.Errorf("unexpected file changes (-want +got):\n%s", d)
+	}
+}
+
+func TestDiffLayersAlignsByDigest(t *testing.T) {
+	base := makeTestLayer(t, map[string]string{"etc/os-release": "alpine"})
+	app := makeTestLayer(t, map[string]string{"app/main": "v1"})
+	config := makeTestLayer(t, map[string]string{"app/config": "x"})
+
+	// A layer is inserted between base and app, and the last layer removes
+	// files from the layers below.
+	a := makeTestImage(t, nil, base, app, config)
+	b := makeTestImage(t, nil, base, makeTestLayer(t, map[string]string{"usr/bin/tool": "tool"}), app,
+		makeTestLayer(t, map[string]string{"app/.wh.config": "", "etc/.wh..wh..opq": ""}))
+
+	layersA, err := InspectLayers(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	layersB, err := InspectLayers(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, d := range DiffLayers(layersA, layersB) {
+		var desc string
+		switch {
+		case d.Before == nil:
+			desc = fmt.Sprintf("added %d:", d.AfterIndex)
+		case d.After == nil:
+			desc = fmt.Sprintf("removed %d:", d.BeforeIndex)
+		default:
+			desc = fmt.Sprintf("changed %d -> %d:", d.BeforeIndex, d.AfterIndex)
+		}
+
+		for _, f := range d.Files {
+			desc += " " + string(f.Kind) + " " + f.Path
+		}
+
+		got = append(got, desc)
+	}
+
+	if d := cmp.Diff([]string{
+		"added 1: added /usr/bin/tool",
+		"changed 2 -> 3: removed /app/config removed /etc",
+	}, got); d != "" {
+		t.Errorf("unexpected layer changes (-want +got):\n%s", d)
+	}
+}
+
+func makeTestLayer(t *testing.T, files map[string]string) v1.Layer {
+	var buf bytes.Buffer
+	w := tar.NewWriter(&buf)
+	for _, name := range sortedKeys(files) {
+		contents := files[name]
+		if err := w.WriteHeader(&tar.Header{Name: name, Size: int64(len(contents)), Typeflag: tar.TypeReg, Mode: 0644}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(contents)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	layer, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(buf.Bytes())), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return layer
+}
+
+func makeTestImage(t *testing.T, env []string, layers ...v1.Layer) v1.Image {
+	img, err := mutate.AppendLayers(empty.Image, layers...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err = mutate.Config(img, v1.Config{Env: env})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return img
+}
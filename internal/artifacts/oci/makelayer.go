@@ -7,8 +7,10 @@ package oci
 import (
 	"context"
 	"io/fs"
+	"os"
 
 	"namespacelabs.dev/foundation/internal/compute"
+	"namespacelabs.dev/foundation/internal/fnfs/maketarfs"
 	"namespacelabs.dev/foundation/std/tasks"
 )
 
@@ -24,7 +26,9 @@ type makeLayer struct {
 }
 
 func (m *makeLayer) Inputs() *compute.In {
-	return compute.Inputs().Computable("vfs", m.vfs)
+	return compute.Inputs().Computable("vfs", m.vfs).
+		// Determines the modification time of every entry.
+		Str("source_date_epoch", os.Getenv(maketarfs.SourceDateEpochEnv))
 }
 
 func (m *makeLayer) Action() *tasks.ActionEvent {
@@ -15,6 +15,7 @@ import (
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"namespacelabs.dev/foundation/internal/compute"
 	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/internal/keys"
 	"namespacelabs.dev/foundation/schema"
 	"namespacelabs.dev/foundation/std/tasks"
 )
@@ -83,14 +84,7 @@ func (pi *publishImage) Compute(ctx context.Context, deps compute.Resolved) (Ima
 	tag := compute.MustGetDepValue(deps, pi.tag, "tag")
 	tasks.Attachments(ctx).AddResult("repository", tag.Repository)
 
-	target := tag.RepositoryWithAccess
-	if tag.Parent != nil {
-		if x, ok := tag.Parent.(TargetRewritter); ok {
-			if newTarget := x.CheckRewriteLocalUse(target); newTarget != nil {
-				target = *newTarget
-			}
-		}
-	}
+	target := PushTarget(tag)
 
 	image := compute.MustGetDepValue(deps, pi.image, "image")
 
@@ -121,11 +115,38 @@ func (pi *publishImage) Compute(ctx context.Context, deps compute.Resolved) (Ima
 			return ImageID{}, err
 		}
 
+		if SignImages {
+			key, err := keys.SigningKey(SigningKeyName)
+			if err != nil {
+				return ImageID{}, err
+			}
+
+			if err := SignImage(ctx, target, tag.Repository, pushedDigest, key); err != nil {
+				return ImageID{}, err
+			}
+		}
+
 		// Use the original name, not the rewritten one, for readability purposes.
 		return ImageID{Repository: tag.Repository, Digest: pushedDigest.String()}, nil
 	})
 }
 
+// PushTarget returns where images allocated with the specified name are pushed
+// to, which differs from the name if the registry rewrites it (e.g. for local
+// use).
+func PushTarget(tag RepositoryWithParent) RepositoryWithAccess {
+	target := tag.RepositoryWithAccess
+	if tag.Parent != nil {
+		if x, ok := tag.Parent.(TargetRewritter); ok {
+			if newTarget := x.CheckRewriteLocalUse(target); newTarget != nil {
+				target = *newTarget
+			}
+		}
+	}
+
+	return target
+}
+
 func maybeAsPermanent(err error) error {
 	var netErr *net.OpError
 	if fnerrors.IsOfKind(err, fnerrors.Kind_INVOCATION) && errors.As(err, &netErr) {
@@ -0,0 +1,79 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package oci
+
+import (
+	"context"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+)
+
+// Artifact is a document (e.g. an SBOM) which is attached to an image.
+type Artifact struct {
+	// The artifact type, e.g. application/spdx+json. Registries list it in
+	// referrers responses, so that artifacts can be filtered by type.
+	ArtifactType string
+	MediaType    string
+	Contents     []byte
+	Annotations  map[string]string
+}
+
+// AttachArtifact pushes an artifact to the target repository, with the
+// specified image as its subject. The artifact is then listed by the
+// registry's referrers API; or, for registries that don't support it yet, in
+// the referrers tag schema fallback (sha256-<digest>).
+func AttachArtifact(ctx context.Context, target RepositoryWithAccess, subject v1.Hash, artifact Artifact) (v1.Hash, error) {
+	remoteOpts, err := RemoteOptsWithAuth(ctx, target.RegistryAccess, true)
+	if err != nil {
+		return v1.Hash{}, err
+	}
+
+	var opts []name.Option
+	if target.InsecureRegistry {
+		opts = append(opts, name.Insecure)
+	}
+
+	subjectRef, err := name.NewDigest(target.Repository+"@"+subject.String(), opts...)
+	if err != nil {
+		return v1.Hash{}, fnerrors.InternalError("failed to parse subject: %w", err)
+	}
+
+	desc, err := remote.Head(subjectRef, remoteOpts...)
+	if err != nil {
+		return v1.Hash{}, fnerrors.InvocationError("registry", "failed to fetch %q: %w", subjectRef, err)
+	}
+
+	// Following the OCI guidelines for artifacts which predate artifactType,
+	// the config's media type is the artifact type.
+	base := mutate.MediaType(mutate.ConfigMediaType(empty.Image, types.MediaType(artifact.ArtifactType)), types.OCIManifestSchema1)
+
+	img, err := mutate.Append(base, mutate.Addendum{
+		Layer:       static.NewLayer(artifact.Contents, types.MediaType(artifact.MediaType)),
+		Annotations: artifact.Annotations,
+	})
+	if err != nil {
+		return v1.Hash{}, err
+	}
+
+	img = mutate.Subject(img, v1.Descriptor{MediaType: desc.MediaType, Size: desc.Size, Digest: desc.Digest}).(v1.Image)
+
+	digest, err := img.Digest()
+	if err != nil {
+		return v1.Hash{}, err
+	}
+
+	if err := remote.Write(subjectRef.Context().Digest(digest.String()), img, remoteOpts...); err != nil {
+		return v1.Hash{}, fnerrors.InvocationError("registry", "failed to push %s: %w", artifact.ArtifactType, err)
+	}
+
+	return digest, nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package oci
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+func TestAttachArtifact(t *testing.T) {
+	srv := httptest.NewServer(registry.New())
+	defer srv.Close()
+
+	repository := strings.TrimPrefix(srv.URL, "http://") + "/app"
+	target := RepositoryWithAccess{RegistryAccess: RegistryAccess{InsecureRegistry: true, PublicImage: true}, Repository: repository}
+
+	subject := pushRandomImage(t, target)
+
+	if _, err := AttachArtifact(context.Background(), target, subject, Artifact{
+		ArtifactType: "application/spdx+json",
+		MediaType:    "application/spdx+json",
+		Contents:     []byte(`{"spdxVersion":"SPDX-2.3"}`),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	ref, err := name.NewDigest(repository+"@"+subject.String(), name.Insecure)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idx, err := remote.Referrers(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := idx.IndexManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(manifest.Manifests) != 1 || manifest.Manifests[0].ArtifactType != "application/spdx+json" {
+		t.Errorf("expected the artifact to be listed as a referrer, got %+v", manifest.Manifests)
+	}
+}
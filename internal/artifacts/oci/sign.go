@@ -0,0 +1,290 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package oci
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/std/tasks"
+)
+
+// Signatures follow cosign's conventions, so that they can be verified with
+// `cosign verify --key`: an image tagged sha256-<digest>.sig in the image's
+// repository, with one layer per signature. Each layer holds a "simple
+// signing" payload which identifies the image, and carries the payload's
+// signature as an annotation.
+const (
+	SimpleSigningMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+	SignatureAnnotation    = "dev.cosignproject.cosign/signature"
+
+	simpleSigningType = "cosign container image signature"
+)
+
+var (
+	// If set, published images are signed with the specified signing key (see
+	// keys.SigningKey).
+	SignImages     = false
+	SigningKeyName = ""
+)
+
+type simpleSigning struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+	Optional map[string]any `json:"optional"`
+}
+
+// SignatureTag returns the tag under which signatures of the specified
+// digest are stored.
+func SignatureTag(digest v1.Hash) string {
+	return digest.Algorithm + "-" + digest.Hex + ".sig"
+}
+
+func makeSignaturePayload(repository string, digest v1.Hash) ([]byte, error) {
+	var payload simpleSigning
+	payload.Critical.Identity.DockerReference = repository
+	payload.Critical.Image.DockerManifestDigest = digest.String()
+	payload.Critical.Type = simpleSigningType
+	return json.Marshal(payload)
+}
+
+// SignImage signs the image with the specified digest, and pushes the
+// signature to the target repository. The signature identifies the image by
+// repository, which may differ from the target's if pushes are rewritten.
+// Signing the same image twice with the same key is a no-op.
+func SignImage(ctx context.Context, target RepositoryWithAccess, repository string, digest v1.Hash, key *ecdsa.PrivateKey) error {
+	return tasks.Action("oci.sign-image").Arg("repository", repository).Arg("digest", digest.String()).Run(ctx, func(ctx context.Context) error {
+		ref, err := signatureRef(target, digest)
+		if err != nil {
+			return err
+		}
+
+		remoteOpts, err := RemoteOptsWithAuth(ctx, target.RegistryAccess, true)
+		if err != nil {
+			return err
+		}
+
+		payload, err := makeSignaturePayload(repository, digest)
+		if err != nil {
+			return err
+		}
+
+		existing, err := fetchSignatures(ref, remoteOpts)
+		if err != nil {
+			return err
+		}
+
+		base := existing
+		if base == nil {
+			base = mutate.MediaType(mutate.ConfigMediaType(empty.Image, types.OCIConfigJSON), types.OCIManifestSchema1)
+		} else {
+			sigs, err := readSignatures(existing)
+			if err != nil {
+				return err
+			}
+
+			for _, sig := range sigs {
+				if sig.verify(&key.PublicKey) == nil && sig.matches(repository, digest) {
+					return nil
+				}
+			}
+		}
+
+		h := sha256.Sum256(payload)
+		sig, err := ecdsa.SignASN1(rand.Reader, key, h[:])
+		if err != nil {
+			return fnerrors.InternalError("failed to sign: %w", err)
+		}
+
+		signed, err := mutate.Append(base, mutate.Addendum{
+			Layer: static.NewLayer(payload, SimpleSigningMediaType),
+			Annotations: map[string]string{
+				SignatureAnnotation: base64.StdEncoding.EncodeToString(sig),
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := remote.Write(ref, signed, remoteOpts...); err != nil {
+			return fnerrors.InvocationError("registry", "failed to push signature %q: %w", ref, err)
+		}
+
+		return nil
+	})
+}
+
+// VerifyImageSignature checks that the image is signed by at least one of
+// the trusted keys. Returns the name of the key which signed the image.
+func VerifyImageSignature(ctx context.Context, image ImageID, access RegistryAccess, trusted map[string]*ecdsa.PublicKey) (string, error) {
+	if image.Digest == "" {
+		return "", fnerrors.BadInputError("%s: can't verify the signature of an image without a digest", image)
+	}
+
+	digest, err := v1.NewHash(image.Digest)
+	if err != nil {
+		return "", fnerrors.BadInputError("%s: %w", image, err)
+	}
+
+	ref, err := signatureRef(RepositoryWithAccess{RegistryAccess: access, Repository: image.Repository}, digest)
+	if err != nil {
+		return "", err
+	}
+
+	remoteOpts, err := RemoteOptsWithAuth(ctx, access, false)
+	if err != nil {
+		return "", err
+	}
+
+	img, err := fetchSignatures(ref, remoteOpts)
+	if err != nil {
+		return "", err
+	}
+
+	if img == nil {
+		return "", fnerrors.Newf("%s: image is not signed", image.RepoAndDigest())
+	}
+
+	sigs, err := readSignatures(img)
+	if err != nil {
+		return "", fnerrors.BadDataError("%s: invalid signatures: %w", image.RepoAndDigest(), err)
+	}
+
+	names := make([]string, 0, len(trusted))
+	for name := range trusted {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, sig := range sigs {
+		if !sig.matches(image.Repository, digest) {
+			continue
+		}
+
+		for _, name := range names {
+			if sig.verify(trusted[name]) == nil {
+				return name, nil
+			}
+		}
+	}
+
+	return "", fnerrors.Newf("%s: image is not signed by a trusted key", image.RepoAndDigest())
+}
+
+func signatureRef(target RepositoryWithAccess, digest v1.Hash) (name.Tag, error) {
+	var opts []name.Option
+	if target.InsecureRegistry {
+		opts = append(opts, name.Insecure)
+	}
+
+	return name.NewTag(target.Repository+":"+SignatureTag(digest), opts...)
+}
+
+// fetchSignatures returns nil if there are no signatures for the image.
+func fetchSignatures(ref name.Tag, remoteOpts []remote.Option) (v1.Image, error) {
+	img, err := remote.Image(ref, remoteOpts...)
+	if err != nil {
+		var terr *transport.Error
+		if errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, fnerrors.InvocationError("registry", "failed to fetch signatures %q: %w", ref, err)
+	}
+
+	return img, nil
+}
+
+type imageSignature struct {
+	payload   []byte
+	signature []byte
+}
+
+func readSignatures(img v1.Image) ([]imageSignature, error) {
+	manifest, err := img.Manifest()
+	if err != nil {
+		return nil, err
+	}
+
+	var sigs []imageSignature
+	for _, desc := range manifest.Layers {
+		if desc.MediaType != SimpleSigningMediaType {
+			continue
+		}
+
+		encoded, ok := desc.Annotations[SignatureAnnotation]
+		if !ok {
+			continue
+		}
+
+		signature, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fnerrors.BadDataError("layer %s: invalid signature: %w", desc.Digest, err)
+		}
+
+		layer, err := img.LayerByDigest(desc.Digest)
+		if err != nil {
+			return nil, err
+		}
+
+		r, err := layer.Compressed()
+		if err != nil {
+			return nil, err
+		}
+
+		payload, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		sigs = append(sigs, imageSignature{payload: payload, signature: signature})
+	}
+
+	return sigs, nil
+}
+
+func (sig imageSignature) verify(pub *ecdsa.PublicKey) error {
+	h := sha256.Sum256(sig.payload)
+	if !ecdsa.VerifyASN1(pub, h[:], sig.signature) {
+		return fnerrors.New("signature mismatch")
+	}
+	return nil
+}
+
+// matches returns true if the signed payload identifies the specified image.
+func (sig imageSignature) matches(repository string, digest v1.Hash) bool {
+	var payload simpleSigning
+	if err := json.Unmarshal(sig.payload, &payload); err != nil {
+		return false
+	}
+
+	return payload.Critical.Type == simpleSigningType &&
+		payload.Critical.Image.DockerManifestDigest == digest.String() &&
+		payload.Critical.Identity.DockerReference == repository
+}
@@ -0,0 +1,112 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package oci
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"namespacelabs.dev/foundation/std/tasks"
+)
+
+func TestSignAndVerifyImage(t *testing.T) {
+	ctx := tasks.WithSink(context.Background(), tasks.NullSink())
+
+	srv := httptest.NewServer(registry.New())
+	defer srv.Close()
+
+	repository := strings.TrimPrefix(srv.URL, "http://") + "/app"
+	target := RepositoryWithAccess{RegistryAccess: RegistryAccess{InsecureRegistry: true, PublicImage: true}, Repository: repository}
+
+	signed := pushRandomImage(t, target)
+	unsigned := pushRandomImage(t, target)
+
+	key := generateKey(t)
+	for k := 0; k < 2; k++ {
+		// Signing is idempotent.
+		if err := SignImage(ctx, target, repository, signed, key); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ref, err := signatureRef(target, signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sigs, err := fetchSignatures(ref, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if layers, _ := sigs.Layers(); len(layers) != 1 {
+		t.Errorf("expected a single signature, got %d", len(layers))
+	}
+
+	trusted := map[string]*ecdsa.PublicKey{"other": &generateKey(t).PublicKey, "ci": &key.PublicKey}
+
+	signer, err := VerifyImageSignature(ctx, ImageID{Repository: repository, Digest: signed.String()}, target.RegistryAccess, trusted)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if signer != "ci" {
+		t.Errorf("expected the image to be signed by %q, got %q", "ci", signer)
+	}
+
+	if _, err := VerifyImageSignature(ctx, ImageID{Repository: repository, Digest: unsigned.String()}, target.RegistryAccess, trusted); err == nil || !strings.Contains(err.Error(), "not signed") {
+		t.Errorf("expected unsigned image to fail verification, got %v", err)
+	}
+
+	if _, err := VerifyImageSignature(ctx, ImageID{Repository: repository, Digest: signed.String()}, target.RegistryAccess, map[string]*ecdsa.PublicKey{"other": trusted["other"]}); err == nil || !strings.Contains(err.Error(), "trusted key") {
+		t.Errorf("expected untrusted signature to fail verification, got %v", err)
+	}
+}
+
+func pushRandomImage(t *testing.T, target RepositoryWithAccess) v1.Hash {
+	t.Helper()
+
+	img, err := random.Image(256, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	digest, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ref, err := name.NewDigest(target.Repository+"@"+digest.String(), name.Insecure)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := remote.Write(ref, img); err != nil {
+		t.Fatal(err)
+	}
+
+	return digest
+}
+
+func generateKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
@@ -0,0 +1,264 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package attest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"namespacelabs.dev/foundation/internal/artifacts/oci"
+	"namespacelabs.dev/foundation/internal/build"
+	"namespacelabs.dev/foundation/internal/cli/version"
+	"namespacelabs.dev/foundation/internal/compute"
+	"namespacelabs.dev/foundation/internal/console"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/internal/fnfs/maketarfs"
+	"namespacelabs.dev/foundation/std/tasks"
+)
+
+// If set, an SBOM and a provenance statement are attached to each image that
+// is built and published through Publish.
+var Enabled = false
+
+// Publish publishes an image built from the specified plan. If attestations
+// are enabled, an SPDX SBOM and a SLSA provenance statement are then
+// attached to the image, as OCI referrers.
+func Publish(tag compute.Computable[oci.RepositoryWithParent], image compute.Computable[oci.ResolvableImage], plan build.Plan) compute.Computable[oci.ImageID] {
+	published := oci.PublishResolvable(tag, image, plan)
+	if !Enabled {
+		return published
+	}
+
+	return &attachAttestations{tag: tag, image: image, published: published, plan: plan}
+}
+
+type attachAttestations struct {
+	tag       compute.Computable[oci.RepositoryWithParent]
+	image     compute.Computable[oci.ResolvableImage]
+	published compute.Computable[oci.ImageID]
+	plan      build.Plan
+
+	compute.LocalScoped[oci.ImageID]
+}
+
+func (at *attachAttestations) Action() *tasks.ActionEvent {
+	return tasks.Action("build.attach-attestations").Scope(at.plan.SourcePackage)
+}
+
+func (at *attachAttestations) Inputs() *compute.In {
+	return compute.Inputs().
+		Computable("tag", at.tag).
+		Computable("image", at.image).
+		Computable("published", at.published).
+		Indigestible("plan", at.plan)
+}
+
+func (at *attachAttestations) Output() compute.Output {
+	return compute.Output{NotCacheable: true}
+}
+
+func (at *attachAttestations) Compute(ctx context.Context, deps compute.Resolved) (oci.ImageID, error) {
+	tag := compute.MustGetDepValue(deps, at.tag, "tag")
+	image := compute.MustGetDepValue(deps, at.image, "image")
+	imageID := compute.MustGetDepValue(deps, at.published, "published")
+
+	digest, err := v1.NewHash(imageID.Digest)
+	if err != nil {
+		return oci.ImageID{}, fnerrors.InternalError("%s: %w", imageID, err)
+	}
+
+	pkgs, err := scanResolvable(image)
+	if err != nil {
+		return oci.ImageID{}, err
+	}
+
+	desc, err := at.describe(ctx)
+	if err != nil {
+		return oci.ImageID{}, err
+	}
+
+	created, err := creationTime(image)
+	if err != nil {
+		return oci.ImageID{}, err
+	}
+
+	sbom, err := json.Marshal(MakeSPDX(imageID.Repository, digest, pkgs, "ns-"+desc.BuilderVersion, created))
+	if err != nil {
+		return oci.ImageID{}, err
+	}
+
+	stmt, err := MakeProvenance(imageID.Repository, digest, desc, created)
+	if err != nil {
+		return oci.ImageID{}, err
+	}
+
+	provenance, err := json.Marshal(stmt)
+	if err != nil {
+		return oci.ImageID{}, err
+	}
+
+	target := oci.PushTarget(tag)
+
+	if _, err := oci.AttachArtifact(ctx, target, digest, oci.Artifact{
+		ArtifactType: SPDXArtifactType,
+		MediaType:    SPDXArtifactType,
+		Contents:     sbom,
+	}); err != nil {
+		return oci.ImageID{}, err
+	}
+
+	if _, err := oci.AttachArtifact(ctx, target, digest, oci.Artifact{
+		ArtifactType: InTotoArtifactType,
+		MediaType:    InTotoArtifactType,
+		Contents:     provenance,
+		Annotations:  map[string]string{PredicateTypeAnnotation: stmt.PredicateType},
+	}); err != nil {
+		return oci.ImageID{}, err
+	}
+
+	return imageID, nil
+}
+
+func (at *attachAttestations) describe(ctx context.Context) (BuildDescription, error) {
+	desc := BuildDescription{
+		Package:    at.plan.SourcePackage,
+		BuildKind:  at.plan.BuildKind.String(),
+		Platforms:  at.plan.Platforms,
+		Definition: at.plan.Definition,
+	}
+
+	if v, err := version.Current(); err == nil {
+		desc.BuilderVersion = v.Version
+	} else {
+		desc.BuilderVersion = version.DevelopmentBuildVersion
+	}
+
+	if mod := at.plan.Module; mod != nil {
+		desc.Module = mod.ModuleName()
+		desc.ModuleVersion = mod.Version()
+
+		vcs, err := mod.VCS(ctx)
+		if err != nil {
+			return desc, err
+		}
+		desc.VCS = vcs
+
+		desc.Dependencies = map[string]string{}
+		for _, dep := range mod.Workspace.Dep {
+			desc.Dependencies[dep.ModuleName] = dep.Version
+		}
+	}
+
+	if at.plan.Workspace != nil {
+		refs, err := build.BaseImagesOf(at.plan.Spec, at.plan.Workspace)
+		if err != nil {
+			return desc, err
+		}
+
+		for _, ref := range refs {
+			resolved, err := compute.GetValue(ctx, oci.ResolveDigest(ref, oci.RegistryAccess{}).ImageID())
+			if err != nil {
+				// Still record the base image, albeit without its digest.
+				fmt.Fprintf(console.Debug(ctx), "attest: failed to resolve %q: %v\n", ref, err)
+				if parsed, err := oci.ParseImageID(ref); err == nil {
+					desc.BaseImages = append(desc.BaseImages, parsed)
+				}
+				continue
+			}
+
+			desc.BaseImages = append(desc.BaseImages, resolved)
+		}
+	}
+
+	return desc, nil
+}
+
+// creationTime returns when the image was created, which is used as the time
+// of its attestations. Attestations are attached on every publish, and must not
+// depend on when that happens: attaching identical artifacts again is a no-op,
+// rather than adding new referrers to an unchanged image.
+func creationTime(image oci.ResolvableImage) (time.Time, error) {
+	var created time.Time
+
+	if idx, err := image.ImageIndex(); err == nil {
+		manifest, err := idx.IndexManifest()
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		for _, m := range manifest.Manifests {
+			img, err := idx.Image(m.Digest)
+			if err != nil {
+				return time.Time{}, err
+			}
+
+			config, err := img.ConfigFile()
+			if err != nil {
+				return time.Time{}, err
+			}
+
+			if config.Created.After(created) {
+				created = config.Created.Time
+			}
+		}
+	} else {
+		img, err := image.Image()
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		config, err := img.ConfigFile()
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		created = config.Created.Time
+	}
+
+	if created.IsZero() || created.Unix() <= 0 {
+		// Images built by ns are normalized, and carry no creation time.
+		return maketarfs.ModTime()
+	}
+
+	return created.UTC(), nil
+}
+
+// scanResolvable lists the packages in an image; or, for image indexes, in
+// each of the index's images.
+func scanResolvable(image oci.ResolvableImage) ([]Package, error) {
+	if idx, err := image.ImageIndex(); err == nil {
+		manifest, err := idx.IndexManifest()
+		if err != nil {
+			return nil, err
+		}
+
+		var pkgs []Package
+		for _, m := range manifest.Manifests {
+			img, err := idx.Image(m.Digest)
+			if err != nil {
+				return nil, err
+			}
+
+			found, err := ScanImage(img)
+			if err != nil {
+				return nil, err
+			}
+
+			pkgs = append(pkgs, found...)
+		}
+
+		return pkgs, nil
+	}
+
+	img, err := image.Image()
+	if err != nil {
+		return nil, err
+	}
+
+	return ScanImage(img)
+}
@@ -0,0 +1,172 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package attest
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"namespacelabs.dev/foundation/internal/artifacts/oci"
+	"namespacelabs.dev/foundation/internal/fnfs/maketarfs"
+	runtimepb "namespacelabs.dev/foundation/schema/runtime"
+)
+
+const apkInstalled = `C:Q1abc=
+P:musl
+V:1.2.4-r2
+A:x86_64
+
+C:Q1def=
+P:busybox
+V:1.36.1-r5
+A:x86_64
+`
+
+func TestScanImage(t *testing.T) {
+	// The test binary is itself a Go binary, with build information.
+	self, err := os.ReadFile(os.Args[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, f := range []struct {
+		name string
+		mode int64
+		data []byte
+	}{
+		{"lib/apk/db/installed", 0644, []byte(apkInstalled)},
+		{"app/server", 0755, self},
+		{"app/README", 0755, []byte("not a binary")},
+	} {
+		if err := tw.WriteHeader(&tar.Header{Name: f.name, Mode: f.mode, Size: int64(len(f.data)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	layer, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(buf.Bytes())), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := mutate.AppendLayers(empty.Image, layer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkgs, err := ScanImage(img)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	purls := map[string]string{}
+	for _, pkg := range pkgs {
+		purls[pkg.PURL] = pkg.Source
+	}
+
+	for _, expected := range []string{"pkg:apk/alpine/musl@1.2.4-r2", "pkg:apk/alpine/busybox@1.36.1-r5"} {
+		if purls[expected] != "/lib/apk/db/installed" {
+			t.Errorf("expected %s in the SBOM, got %v", expected, purls)
+		}
+	}
+
+	var sawStdlib bool
+	for purl, source := range purls {
+		if strings.HasPrefix(purl, "pkg:golang/stdlib@go") && source == "/app/server" {
+			sawStdlib = true
+		}
+	}
+
+	if !sawStdlib {
+		t.Errorf("expected the Go binary to be scanned, got %v", purls)
+	}
+
+	digest, _ := img.Digest()
+	doc := MakeSPDX("registry.example.com/app", digest, pkgs, "ns-dev", time.Unix(0, 0))
+	if got := len(doc.Packages); got != len(dedupPackages(pkgs))+1 {
+		t.Errorf("expected one SPDX package per package plus the image, got %d", got)
+	}
+}
+
+func TestMakeProvenance(t *testing.T) {
+	digest := v1.Hash{Algorithm: "sha256", Hex: strings.Repeat("ab", 32)}
+	baseDigest := "sha256:" + strings.Repeat("cd", 32)
+
+	stmt, err := MakeProvenance("registry.example.com/app", digest, BuildDescription{
+		Package:      "example.com/app/server",
+		BuildKind:    "SERVER",
+		Module:       "example.com/app",
+		VCS:          &runtimepb.BuildVCS{Revision: "1234abcd", Uncommitted: true},
+		Dependencies: map[string]string{"namespacelabs.dev/foundation": "v0.0.1"},
+		BaseImages:   []oci.ImageID{{Repository: "docker.io/library/alpine", Digest: baseDigest}},
+	}, time.Unix(0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stmt.Subject[0].Digest["sha256"] != digest.Hex {
+		t.Errorf("unexpected subject: %v", stmt.Subject)
+	}
+
+	deps := stmt.Predicate.BuildDefinition.ResolvedDependencies
+	if len(deps) != 3 {
+		t.Fatalf("expected 3 resolved dependencies, got %+v", deps)
+	}
+
+	if deps[0].Digest["gitCommit"] != "1234abcd" || deps[0].Annotations["uncommitted"] != true {
+		t.Errorf("unexpected source: %+v", deps[0])
+	}
+
+	if deps[2].URI != "docker://docker.io/library/alpine" || deps[2].Digest["sha256"] != strings.Repeat("cd", 32) {
+		t.Errorf("unexpected base image: %+v", deps[2])
+	}
+}
+
+func TestCreationTime(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "")
+
+	created := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	img, err := mutate.CreatedAt(empty.Image, v1.Time{Time: created})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := creationTime(oci.RawAsResolvable(img))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !got.Equal(created) {
+		t.Errorf("expected %v, got %v", created, got)
+	}
+
+	// Normalized images don't record when they were built.
+	got, err = creationTime(oci.RawAsResolvable(empty.Image))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := maketarfs.FixedPoint.Truncate(time.Second); !got.Equal(want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
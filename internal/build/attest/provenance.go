@@ -0,0 +1,165 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package attest
+
+import (
+	"encoding/json"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"google.golang.org/protobuf/encoding/protojson"
+	"namespacelabs.dev/foundation/internal/artifacts/oci"
+	"namespacelabs.dev/foundation/internal/parsing/platform"
+	"namespacelabs.dev/foundation/schema"
+	runtimepb "namespacelabs.dev/foundation/schema/runtime"
+)
+
+const (
+	InTotoArtifactType = "application/vnd.in-toto+json"
+
+	// Set on attached statements, so that they can be told apart without
+	// being fetched.
+	PredicateTypeAnnotation = "in-toto.io/predicate-type"
+
+	inTotoStatementType = "https://in-toto.io/Statement/v1"
+	slsaProvenanceType  = "https://slsa.dev/provenance/v1"
+	nsBuildType         = "https://namespacelabs.dev/ns/build/v1"
+	nsBuilderID         = "https://namespacelabs.dev/ns"
+)
+
+// Statement is an in-toto attestation statement.
+type Statement struct {
+	Type          string         `json:"_type"`
+	Subject       []Subject      `json:"subject"`
+	PredicateType string         `json:"predicateType"`
+	Predicate     SLSAProvenance `json:"predicate"`
+}
+
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// SLSAProvenance is a SLSA v1 provenance predicate.
+type SLSAProvenance struct {
+	BuildDefinition struct {
+		BuildType            string               `json:"buildType"`
+		ExternalParameters   map[string]any       `json:"externalParameters"`
+		ResolvedDependencies []ResourceDescriptor `json:"resolvedDependencies,omitempty"`
+	} `json:"buildDefinition"`
+	RunDetails struct {
+		Builder struct {
+			ID      string            `json:"id"`
+			Version map[string]string `json:"version,omitempty"`
+		} `json:"builder"`
+		Metadata struct {
+			FinishedOn string `json:"finishedOn,omitempty"`
+		} `json:"metadata"`
+	} `json:"runDetails"`
+}
+
+type ResourceDescriptor struct {
+	URI         string            `json:"uri,omitempty"`
+	Name        string            `json:"name,omitempty"`
+	Digest      map[string]string `json:"digest,omitempty"`
+	Annotations map[string]any    `json:"annotations,omitempty"`
+}
+
+// BuildDescription is what's recorded about a build in its provenance.
+type BuildDescription struct {
+	Package    schema.PackageName
+	BuildKind  string
+	Platforms  []specs.Platform
+	Definition *schema.LayeredImageBuildPlan
+
+	Module        string
+	ModuleVersion string
+	VCS           *runtimepb.BuildVCS
+	// Modules which the build's module depends on, by name.
+	Dependencies map[string]string
+	BaseImages   []oci.ImageID
+
+	BuilderVersion string
+}
+
+// MakeProvenance produces a SLSA provenance statement for the image with the
+// specified name and digest.
+func MakeProvenance(imageName string, digest v1.Hash, desc BuildDescription, finished time.Time) (*Statement, error) {
+	stmt := &Statement{
+		Type:          inTotoStatementType,
+		Subject:       []Subject{{Name: imageName, Digest: map[string]string{digest.Algorithm: digest.Hex}}},
+		PredicateType: slsaProvenanceType,
+	}
+
+	p := &stmt.Predicate
+	p.BuildDefinition.BuildType = nsBuildType
+
+	params := map[string]any{
+		"package":   desc.Package.String(),
+		"buildKind": desc.BuildKind,
+	}
+
+	if len(desc.Platforms) > 0 {
+		params["platforms"] = platform.FormatPlatforms(desc.Platforms)
+	}
+
+	if desc.Definition != nil {
+		serialized, err := protojson.Marshal(desc.Definition)
+		if err != nil {
+			return nil, err
+		}
+		params["definition"] = json.RawMessage(serialized)
+	}
+
+	p.BuildDefinition.ExternalParameters = params
+
+	if desc.Module != "" {
+		source := ResourceDescriptor{URI: "git+https://" + desc.Module, Name: desc.Module}
+		if desc.ModuleVersion != "" {
+			source.Annotations = map[string]any{"version": desc.ModuleVersion}
+		}
+
+		if vcs := desc.VCS; vcs != nil && vcs.Revision != "" {
+			source.Digest = map[string]string{"gitCommit": vcs.Revision}
+			if source.Annotations == nil {
+				source.Annotations = map[string]any{}
+			}
+			source.Annotations["commitTime"] = vcs.CommitTime
+			source.Annotations["uncommitted"] = vcs.Uncommitted
+		}
+
+		p.BuildDefinition.ResolvedDependencies = append(p.BuildDefinition.ResolvedDependencies, source)
+	}
+
+	deps := maps.Keys(desc.Dependencies)
+	slices.Sort(deps)
+
+	for _, name := range deps {
+		p.BuildDefinition.ResolvedDependencies = append(p.BuildDefinition.ResolvedDependencies, ResourceDescriptor{
+			URI:         "git+https://" + name,
+			Name:        name,
+			Annotations: map[string]any{"version": desc.Dependencies[name]},
+		})
+	}
+
+	for _, base := range desc.BaseImages {
+		rd := ResourceDescriptor{URI: "docker://" + base.Repository, Name: base.ImageRef()}
+		if h, err := v1.NewHash(base.Digest); err == nil {
+			rd.Digest = map[string]string{h.Algorithm: h.Hex}
+		}
+		p.BuildDefinition.ResolvedDependencies = append(p.BuildDefinition.ResolvedDependencies, rd)
+	}
+
+	p.RunDetails.Builder.ID = nsBuilderID
+	if desc.BuilderVersion != "" {
+		p.RunDetails.Builder.Version = map[string]string{"ns": desc.BuilderVersion}
+	}
+	p.RunDetails.Metadata.FinishedOn = finished.UTC().Format(time.RFC3339)
+
+	return stmt, nil
+}
@@ -0,0 +1,298 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package attest
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"debug/buildinfo"
+	"fmt"
+	"io"
+	"path"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+)
+
+const (
+	SPDXArtifactType = "application/spdx+json"
+
+	// Executables larger than this are not inspected for Go build information.
+	maxInspectedBinarySize = 512 * 1024 * 1024
+)
+
+// Package is a software component found in an image.
+type Package struct {
+	Name    string
+	Version string
+	PURL    string
+	// Where in the image the package was found.
+	Source string
+}
+
+// ScanImage lists the packages in the image's filesystem: Go modules linked
+// into Go binaries (from their embedded build information), and Alpine and
+// Debian packages.
+func ScanImage(img v1.Image) ([]Package, error) {
+	rc := mutate.Extract(img)
+	defer rc.Close()
+
+	var pkgs []Package
+	tr := tar.NewReader(rc)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fnerrors.BadDataError("failed to read image contents: %w", err)
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Clean("/" + hdr.Name)
+
+		switch {
+		case name == "/lib/apk/db/installed":
+			pkgs = append(pkgs, parseApkInstalled(tr, name)...)
+
+		case name == "/var/lib/dpkg/status":
+			pkgs = append(pkgs, parseDpkgStatus(tr, name)...)
+
+		case hdr.Mode&0111 != 0 && hdr.Size > 4 && hdr.Size < maxInspectedBinarySize:
+			goPkgs, err := scanGoBinary(tr, name)
+			if err != nil {
+				return nil, err
+			}
+			pkgs = append(pkgs, goPkgs...)
+		}
+	}
+
+	return pkgs, nil
+}
+
+func scanGoBinary(r io.Reader, name string) ([]Package, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(4)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(magic, []byte("\x7fELF")) {
+		return nil, nil
+	}
+
+	contents, err := io.ReadAll(br)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := buildinfo.Read(bytes.NewReader(contents))
+	if err != nil {
+		// Not a Go binary.
+		return nil, nil
+	}
+
+	pkgs := []Package{{Name: "stdlib", Version: info.GoVersion, PURL: "pkg:golang/stdlib@" + info.GoVersion, Source: name}}
+
+	if info.Main.Path != "" {
+		pkgs = append(pkgs, goModule(&info.Main, name))
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		pkgs = append(pkgs, goModule(dep, name))
+	}
+
+	return pkgs, nil
+}
+
+func goModule(mod *debug.Module, source string) Package {
+	return Package{Name: mod.Path, Version: mod.Version, PURL: fmt.Sprintf("pkg:golang/%s@%s", mod.Path, mod.Version), Source: source}
+}
+
+// parseApkInstalled parses Alpine's package database, where each package is
+// a block of "K:value" lines.
+func parseApkInstalled(r io.Reader, source string) []Package {
+	var pkgs []Package
+	var name, version string
+
+	flush := func() {
+		if name != "" && version != "" {
+			pkgs = append(pkgs, Package{Name: name, Version: version, PURL: fmt.Sprintf("pkg:apk/alpine/%s@%s", name, version), Source: source})
+		}
+		name, version = "", ""
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			flush()
+		case strings.HasPrefix(line, "P:"):
+			name = line[2:]
+		case strings.HasPrefix(line, "V:"):
+			version = line[2:]
+		}
+	}
+	flush()
+
+	return pkgs
+}
+
+// parseDpkgStatus parses Debian's package database, where each package is a
+// block of "Key: value" lines.
+func parseDpkgStatus(r io.Reader, source string) []Package {
+	var pkgs []Package
+	var name, version string
+	installed := false
+
+	flush := func() {
+		if name != "" && version != "" && installed {
+			pkgs = append(pkgs, Package{Name: name, Version: version, PURL: fmt.Sprintf("pkg:deb/%s@%s", name, version), Source: source})
+		}
+		name, version, installed = "", "", false
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			flush()
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, ":")
+		value = strings.TrimSpace(value)
+		switch key {
+		case "Package":
+			name = value
+		case "Version":
+			version = value
+		case "Status":
+			installed = strings.HasSuffix(value, " installed")
+		}
+	}
+	flush()
+
+	return pkgs
+}
+
+// SPDXDocument is an SPDX 2.3 document, in its JSON form.
+type SPDXDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name             string            `json:"name"`
+	SPDXID           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	SourceInfo       string            `json:"sourceInfo,omitempty"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+	Checksums        []spdxChecksum    `json:"checksums,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// MakeSPDX produces an SPDX 2.3 document describing the image with the
+// specified name and digest, and the packages it contains.
+func MakeSPDX(imageName string, digest v1.Hash, pkgs []Package, creator string, created time.Time) *SPDXDocument {
+	doc := &SPDXDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              imageName,
+		DocumentNamespace: fmt.Sprintf("https://namespacelabs.dev/spdx/%s/%s", imageName, digest.Hex),
+		CreationInfo: spdxCreationInfo{
+			Created:  created.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: " + creator},
+		},
+	}
+
+	doc.Packages = append(doc.Packages, spdxPackage{
+		Name:             imageName,
+		SPDXID:           "SPDXRef-Image",
+		DownloadLocation: "NOASSERTION",
+		Checksums:        []spdxChecksum{{Algorithm: "SHA256", ChecksumValue: digest.Hex}},
+	})
+	doc.Relationships = append(doc.Relationships, spdxRelationship{"SPDXRef-DOCUMENT", "DESCRIBES", "SPDXRef-Image"})
+
+	for k, pkg := range dedupPackages(pkgs) {
+		id := fmt.Sprintf("SPDXRef-Package-%d", k)
+		doc.Packages = append(doc.Packages, spdxPackage{
+			Name:             pkg.Name,
+			SPDXID:           id,
+			VersionInfo:      pkg.Version,
+			DownloadLocation: "NOASSERTION",
+			SourceInfo:       "found in " + pkg.Source,
+			ExternalRefs: []spdxExternalRef{{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  pkg.PURL,
+			}},
+		})
+		doc.Relationships = append(doc.Relationships, spdxRelationship{"SPDXRef-Image", "CONTAINS", id})
+	}
+
+	return doc
+}
+
+func dedupPackages(pkgs []Package) []Package {
+	seen := map[string]bool{}
+	var result []Package
+	for _, pkg := range pkgs {
+		if seen[pkg.PURL] {
+			continue
+		}
+		seen[pkg.PURL] = true
+		result = append(result, pkg)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].PURL < result[j].PURL
+	})
+
+	return result
+}
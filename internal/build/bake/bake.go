@@ -0,0 +1,316 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+// Package bake parses Docker Bake definitions (docker-bake.hcl, or its JSON
+// equivalent), and resolves them into the list of targets to build.
+// See https://docs.docker.com/build/bake/reference/.
+package bake
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/maps"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+)
+
+const DefaultGroup = "default"
+
+// Prefix of context values that refer to another target.
+const targetContextPrefix = "target:"
+
+// Definition is the parsed contents of a bake file.
+type Definition struct {
+	Groups  map[string]*Group
+	Targets map[string]*target
+}
+
+type Group struct {
+	Targets []string `json:"targets"`
+}
+
+// target holds a target as declared; unset fields are nil, so that they can be
+// inherited.
+type target struct {
+	Inherits         []string           `json:"inherits"`
+	Context          *string            `json:"context"`
+	Dockerfile       *string            `json:"dockerfile"`
+	DockerfileInline *string            `json:"dockerfile-inline"`
+	Args             map[string]*string `json:"args"`
+	Contexts         map[string]string  `json:"contexts"`
+	Labels           map[string]*string `json:"labels"`
+	Tags             []string           `json:"tags"`
+	Platforms        []string           `json:"platforms"`
+	Secrets          []string           `json:"secret"`
+	Outputs          []string           `json:"output"`
+	CacheFrom        []string           `json:"cache-from"`
+	CacheTo          []string           `json:"cache-to"`
+	Target           *string            `json:"target"`
+	NoCache          *bool              `json:"no-cache"`
+	Pull             *bool              `json:"pull"`
+}
+
+// Target is a fully resolved target, i.e. with its parents merged in.
+type Target struct {
+	Name             string
+	Context          string
+	Dockerfile       string
+	DockerfileInline string
+	Args             map[string]string
+	// Named contexts, e.g. `base = "docker-image://alpine"`, `src = "./src"`
+	// or `base = "target:base"`.
+	Contexts  map[string]string
+	Labels    map[string]string
+	Tags      []string
+	Platforms []string
+	Secrets   []string
+	Outputs   []string
+	CacheFrom []string
+	CacheTo   []string
+	Target    string
+	NoCache   bool
+	Pull      bool
+	// Targets which this target consumes through its contexts.
+	DependsOn []string
+}
+
+// ParseFile parses a bake file; JSON if the file name ends in .json, HCL
+// otherwise.
+func ParseFile(path string) (*Definition, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fnerrors.Newf("failed to read bake file: %w", err)
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return ParseJSON(path, contents, os.LookupEnv)
+	}
+
+	return ParseHCL(path, contents, os.LookupEnv)
+}
+
+// TargetContext returns the name of the target a context value refers to.
+func TargetContext(value string) (string, bool) {
+	return strings.CutPrefix(value, targetContextPrefix)
+}
+
+// Resolve returns the targets (and groups) with the specified names, along
+// with the targets they depend on, ordered so that dependencies come first.
+// If no names are specified, the default group is resolved.
+func (d *Definition) Resolve(names []string) ([]*Target, error) {
+	if len(names) == 0 {
+		if _, ok := d.Groups[DefaultGroup]; !ok {
+			if _, ok := d.Targets[DefaultGroup]; !ok {
+				return nil, fnerrors.BadInputError("no targets specified, and there's no %q group", DefaultGroup)
+			}
+		}
+		names = []string{DefaultGroup}
+	}
+
+	var requested []string
+	if err := d.expandGroups(names, nil, &requested); err != nil {
+		return nil, err
+	}
+
+	r := resolver{def: d, resolved: map[string]*Target{}, visiting: map[string]bool{}}
+	for _, name := range requested {
+		if err := r.visit(name); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.ordered, nil
+}
+
+func (d *Definition) expandGroups(names []string, stack []string, out *[]string) error {
+	for _, name := range names {
+		if g, ok := d.Groups[name]; ok {
+			for _, s := range stack {
+				if s == name {
+					return fnerrors.BadInputError("group %q includes itself", name)
+				}
+			}
+
+			if err := d.expandGroups(g.Targets, append(stack, name), out); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if _, ok := d.Targets[name]; !ok {
+			return fnerrors.BadInputError("%s: no such target or group", name)
+		}
+
+		*out = append(*out, name)
+	}
+
+	return nil
+}
+
+type resolver struct {
+	def      *Definition
+	resolved map[string]*Target
+	visiting map[string]bool
+	ordered  []*Target
+}
+
+func (r *resolver) visit(name string) error {
+	if _, ok := r.resolved[name]; ok {
+		return nil
+	}
+
+	if r.visiting[name] {
+		return fnerrors.BadInputError("target %q depends on itself", name)
+	}
+
+	r.visiting[name] = true
+	defer delete(r.visiting, name)
+
+	merged, err := r.merge(name, nil)
+	if err != nil {
+		return err
+	}
+
+	t := merged.finalize(name)
+	for _, value := range t.Contexts {
+		dep, ok := TargetContext(value)
+		if !ok {
+			continue
+		}
+
+		if _, ok := r.def.Targets[dep]; !ok {
+			return fnerrors.BadInputError("%s: context refers to unknown target %q", name, dep)
+		}
+
+		if err := r.visit(dep); err != nil {
+			return err
+		}
+
+		t.DependsOn = append(t.DependsOn, dep)
+	}
+
+	sort.Strings(t.DependsOn)
+
+	r.resolved[name] = t
+	r.ordered = append(r.ordered, t)
+	return nil
+}
+
+// merge returns the target with its parents applied, in the order they are listed.
+func (r *resolver) merge(name string, stack []string) (*target, error) {
+	for _, s := range stack {
+		if s == name {
+			return nil, fnerrors.BadInputError("target %q inherits from itself", name)
+		}
+	}
+
+	t, ok := r.def.Targets[name]
+	if !ok {
+		return nil, fnerrors.BadInputError("%s: no such target", name)
+	}
+
+	merged := &target{}
+	for _, parent := range t.Inherits {
+		p, err := r.merge(parent, append(stack, name))
+		if err != nil {
+			return nil, err
+		}
+		merged.apply(p)
+	}
+
+	merged.apply(t)
+	return merged, nil
+}
+
+// apply overrides fields of t with those that are set in other. Maps are merged.
+func (t *target) apply(other *target) {
+	setIf(&t.Context, other.Context)
+	setIf(&t.Dockerfile, other.Dockerfile)
+	setIf(&t.DockerfileInline, other.DockerfileInline)
+	setIf(&t.Target, other.Target)
+	setIf(&t.NoCache, other.NoCache)
+	setIf(&t.Pull, other.Pull)
+
+	t.Args = mergeMaps(t.Args, other.Args)
+	t.Labels = mergeMaps(t.Labels, other.Labels)
+	t.Contexts = mergeMaps(t.Contexts, other.Contexts)
+
+	for _, s := range []struct{ dst, src *[]string }{
+		{&t.Tags, &other.Tags},
+		{&t.Platforms, &other.Platforms},
+		{&t.Secrets, &other.Secrets},
+		{&t.Outputs, &other.Outputs},
+		{&t.CacheFrom, &other.CacheFrom},
+		{&t.CacheTo, &other.CacheTo},
+	} {
+		if *s.src != nil {
+			*s.dst = *s.src
+		}
+	}
+}
+
+func (t *target) finalize(name string) *Target {
+	res := &Target{
+		Name:             name,
+		Context:          deref(t.Context, "."),
+		Dockerfile:       deref(t.Dockerfile, "Dockerfile"),
+		DockerfileInline: deref(t.DockerfileInline, ""),
+		Args:             nonNil(t.Args),
+		Contexts:         maps.Clone(t.Contexts),
+		Labels:           nonNil(t.Labels),
+		Tags:             t.Tags,
+		Platforms:        t.Platforms,
+		Secrets:          t.Secrets,
+		Outputs:          t.Outputs,
+		CacheFrom:        t.CacheFrom,
+		CacheTo:          t.CacheTo,
+		Target:           deref(t.Target, ""),
+		NoCache:          deref(t.NoCache, false),
+		Pull:             deref(t.Pull, false),
+	}
+
+	return res
+}
+
+func setIf[V any](dst **V, src *V) {
+	if src != nil {
+		*dst = src
+	}
+}
+
+func mergeMaps[V any](dst, src map[string]V) map[string]V {
+	if src == nil {
+		return dst
+	}
+
+	if dst == nil {
+		dst = map[string]V{}
+	}
+
+	for k, v := range src {
+		dst[k] = v
+	}
+
+	return dst
+}
+
+func deref[V any](v *V, def V) V {
+	if v == nil {
+		return def
+	}
+	return *v
+}
+
+// nonNil drops unset (null) values.
+func nonNil(m map[string]*string) map[string]string {
+	res := map[string]string{}
+	for k, v := range m {
+		if v != nil {
+			res[k] = *v
+		}
+	}
+	return res
+}
@@ -0,0 +1,102 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package bake
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const testBakeFile = `
+variable "REGISTRY" {
+  default = "example.com"
+}
+
+variable "TAG" {
+  default = "latest"
+}
+
+group "default" {
+  targets = ["app", "tools"]
+}
+
+target "_common" {
+  args = {
+    GO_VERSION = 1.22
+    DEBUG      = null
+  }
+  platforms = ["linux/amd64"]
+}
+
+target "base" {
+  dockerfile = "base.Dockerfile"
+  tags       = ["${REGISTRY}/base:${TAG}"]
+}
+
+target "app" {
+  inherits = ["_common"]
+  contexts = {
+    base = "target:base"
+  }
+  tags = ["${REGISTRY}/app:${TAG}"]
+}
+
+target "tools" {
+  inherits = ["_common"]
+  name     = "tool-${tool}"
+  matrix = {
+    tool = ["lint", "fmt"]
+  }
+  target = tool
+}
+`
+
+func TestResolve(t *testing.T) {
+	def, err := ParseHCL("docker-bake.hcl", []byte(testBakeFile), func(key string) (string, bool) {
+		if key == "TAG" {
+			return "v1", true
+		}
+		return "", false
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targets, err := def.Resolve(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, t := range targets {
+		names = append(names, t.Name)
+	}
+
+	if d := cmp.Diff([]string{"base", "app", "tool-lint", "tool-fmt"}, names); d != "" {
+		t.Errorf("unexpected targets (-want +got):\n%s", d)
+	}
+
+	app := targets[1]
+	want := &Target{
+		Name:       "app",
+		Context:    ".",
+		Dockerfile: "Dockerfile",
+		Args:       map[string]string{"GO_VERSION": "1.22"},
+		Contexts:   map[string]string{"base": "target:base"},
+		Labels:     map[string]string{},
+		Tags:       []string{"example.com/app:v1"},
+		Platforms:  []string{"linux/amd64"},
+		DependsOn:  []string{"base"},
+	}
+
+	if d := cmp.Diff(want, app); d != "" {
+		t.Errorf("unexpected app target (-want +got):\n%s", d)
+	}
+
+	if targets[3].Target != "fmt" {
+		t.Errorf("expected the matrix value to be applied, got %q", targets[3].Target)
+	}
+}
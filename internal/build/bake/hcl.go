@@ -0,0 +1,393 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package bake
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	hcljson "github.com/hashicorp/hcl/v2/json"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+)
+
+var fileSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "variable", LabelNames: []string{"name"}},
+		{Type: "group", LabelNames: []string{"name"}},
+		{Type: "target", LabelNames: []string{"name"}},
+	},
+}
+
+// The type of each supported target attribute; values are converted before
+// being decoded, so e.g. numeric build args are accepted.
+var targetAttrTypes = map[string]cty.Type{
+	"inherits":          cty.List(cty.String),
+	"context":           cty.String,
+	"dockerfile":        cty.String,
+	"dockerfile-inline": cty.String,
+	"args":              cty.Map(cty.String),
+	"contexts":          cty.Map(cty.String),
+	"labels":            cty.Map(cty.String),
+	"tags":              cty.List(cty.String),
+	"platforms":         cty.List(cty.String),
+	"secret":            cty.List(cty.String),
+	"output":            cty.List(cty.String),
+	"cache-from":        cty.List(cty.String),
+	"cache-to":          cty.List(cty.String),
+	"target":            cty.String,
+	"no-cache":          cty.Bool,
+	"pull":              cty.Bool,
+}
+
+var functions = map[string]function.Function{
+	"and":           stdlib.AndFunc,
+	"coalesce":      stdlib.CoalesceFunc,
+	"concat":        stdlib.ConcatFunc,
+	"contains":      stdlib.ContainsFunc,
+	"distinct":      stdlib.DistinctFunc,
+	"equal":         stdlib.EqualFunc,
+	"flatten":       stdlib.FlattenFunc,
+	"format":        stdlib.FormatFunc,
+	"formatdate":    stdlib.FormatDateFunc,
+	"join":          stdlib.JoinFunc,
+	"keys":          stdlib.KeysFunc,
+	"length":        stdlib.LengthFunc,
+	"lookup":        stdlib.LookupFunc,
+	"lower":         stdlib.LowerFunc,
+	"merge":         stdlib.MergeFunc,
+	"not":           stdlib.NotFunc,
+	"notequal":      stdlib.NotEqualFunc,
+	"or":            stdlib.OrFunc,
+	"regex":         stdlib.RegexFunc,
+	"regex_replace": stdlib.RegexReplaceFunc,
+	"replace":       stdlib.ReplaceFunc,
+	"split":         stdlib.SplitFunc,
+	"substr":        stdlib.SubstrFunc,
+	"trimprefix":    stdlib.TrimPrefixFunc,
+	"trimspace":     stdlib.TrimSpaceFunc,
+	"trimsuffix":    stdlib.TrimSuffixFunc,
+	"upper":         stdlib.UpperFunc,
+	"values":        stdlib.ValuesFunc,
+}
+
+// ParseHCL parses an HCL bake file. Variables may be overridden by
+// environment variables of the same name, looked up with lookupEnv.
+func ParseHCL(filename string, contents []byte, lookupEnv func(string) (string, bool)) (*Definition, error) {
+	file, diags := hclsyntax.ParseConfig(contents, filename, hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return nil, fnerrors.BadInputError("%s: %w", filename, diags)
+	}
+
+	return decode(filename, file.Body, lookupEnv)
+}
+
+// ParseJSON parses a JSON bake file. Strings may use the same ${...}
+// interpolations as HCL files.
+func ParseJSON(filename string, contents []byte, lookupEnv func(string) (string, bool)) (*Definition, error) {
+	file, diags := hcljson.Parse(contents, filename)
+	if diags.HasErrors() {
+		return nil, fnerrors.BadInputError("%s: %w", filename, diags)
+	}
+
+	return decode(filename, file.Body, lookupEnv)
+}
+
+func decode(filename string, body hcl.Body, lookupEnv func(string) (string, bool)) (*Definition, error) {
+	if lookupEnv == nil {
+		lookupEnv = func(string) (string, bool) { return "", false }
+	}
+
+	content, rest, diags := body.PartialContent(fileSchema)
+	if diags.HasErrors() {
+		return nil, fnerrors.BadInputError("%s: %w", filename, diags)
+	}
+
+	// Top-level attributes are also variables.
+	var topLevel hcl.Attributes
+	if syntax, ok := rest.(*hclsyntax.Body); ok {
+		topLevel = hcl.Attributes{}
+		for name, attr := range syntax.Attributes {
+			topLevel[name] = attr.AsHCLAttribute()
+		}
+	} else {
+		topLevel, diags = rest.JustAttributes()
+		if diags.HasErrors() {
+			return nil, fnerrors.BadInputError("%s: %w", filename, diags)
+		}
+	}
+
+	ectx := &hcl.EvalContext{Functions: functions}
+
+	vars, err := evalVariables(filename, ectx, content.Blocks, topLevel, lookupEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	ectx.Variables = vars
+
+	def := &Definition{Groups: map[string]*Group{}, Targets: map[string]*target{}}
+	for _, block := range content.Blocks {
+		switch block.Type {
+		case "group":
+			attrs, diags := block.Body.JustAttributes()
+			if diags.HasErrors() {
+				return nil, fnerrors.BadInputError("%s: %w", filename, diags)
+			}
+
+			g := &Group{}
+			if attr, ok := attrs["targets"]; ok {
+				if err := evalInto(ectx, attr, cty.List(cty.String), &g.Targets); err != nil {
+					return nil, fnerrors.BadInputError("%s: group %q: %w", filename, block.Labels[0], err)
+				}
+			}
+
+			def.Groups[block.Labels[0]] = g
+
+		case "target":
+			if err := decodeTarget(ectx, block, def); err != nil {
+				return nil, fnerrors.BadInputError("%s: target %q: %w", filename, block.Labels[0], err)
+			}
+		}
+	}
+
+	return def, nil
+}
+
+func evalVariables(filename string, ectx *hcl.EvalContext, blocks hcl.Blocks, topLevel hcl.Attributes, lookupEnv func(string) (string, bool)) (map[string]cty.Value, error) {
+	pending := map[string]hcl.Expression{}
+	for name, attr := range topLevel {
+		pending[name] = attr.Expr
+	}
+
+	vars := map[string]cty.Value{}
+	for _, block := range blocks {
+		if block.Type != "variable" {
+			continue
+		}
+
+		attrs, diags := block.Body.JustAttributes()
+		if diags.HasErrors() {
+			return nil, fnerrors.BadInputError("%s: %w", filename, diags)
+		}
+
+		name := block.Labels[0]
+		if attr, ok := attrs["default"]; ok {
+			pending[name] = attr.Expr
+		} else {
+			vars[name] = override(name, cty.StringVal(""), lookupEnv)
+		}
+	}
+
+	// Variables may refer to each other, so evaluate them until no further
+	// progress is made.
+	for len(pending) > 0 {
+		progress := false
+
+		for _, name := range sortedKeys(pending) {
+			expr := pending[name]
+
+			ready := true
+			for _, ref := range expr.Variables() {
+				if _, ok := vars[ref.RootName()]; !ok {
+					ready = false
+				}
+			}
+
+			if !ready {
+				continue
+			}
+
+			v, diags := expr.Value(&hcl.EvalContext{Variables: vars, Functions: ectx.Functions})
+			if diags.HasErrors() {
+				return nil, fnerrors.BadInputError("%s: variable %q: %w", filename, name, diags)
+			}
+
+			vars[name] = override(name, v, lookupEnv)
+			delete(pending, name)
+			progress = true
+		}
+
+		if !progress {
+			return nil, fnerrors.BadInputError("%s: variables %v refer to undefined, or each other", filename, sortedKeys(pending))
+		}
+	}
+
+	return vars, nil
+}
+
+// override returns the value of the environment variable with the same name,
+// if set, in place of a variable's default.
+func override(name string, def cty.Value, lookupEnv func(string) (string, bool)) cty.Value {
+	value, ok := lookupEnv(name)
+	if !ok {
+		return def
+	}
+
+	if converted, err := convert.Convert(cty.StringVal(value), def.Type()); err == nil {
+		return converted
+	}
+
+	return cty.StringVal(value)
+}
+
+func decodeTarget(ectx *hcl.EvalContext, block *hcl.Block, def *Definition) error {
+	attrs, diags := block.Body.JustAttributes()
+	if diags.HasErrors() {
+		return diags
+	}
+
+	matrixAttr, hasMatrix := attrs["matrix"]
+	if !hasMatrix {
+		if _, ok := attrs["name"]; ok {
+			return fnerrors.Newf("name can only be set when using a matrix")
+		}
+
+		t, err := evalTarget(ectx, attrs)
+		if err != nil {
+			return err
+		}
+
+		def.Targets[block.Labels[0]] = t
+		return nil
+	}
+
+	nameAttr, ok := attrs["name"]
+	if !ok {
+		return fnerrors.Newf("a name is required when using a matrix")
+	}
+
+	matrix, diags := matrixAttr.Expr.Value(ectx)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	if !matrix.Type().IsObjectType() && !matrix.Type().IsMapType() {
+		return fnerrors.Newf("matrix must be a map of lists")
+	}
+
+	combinations := []map[string]cty.Value{{}}
+	axes := matrix.AsValueMap()
+	for _, axis := range sortedKeys(axes) {
+		values := axes[axis]
+		if !values.CanIterateElements() {
+			return fnerrors.Newf("matrix: %q must be a list", axis)
+		}
+
+		var next []map[string]cty.Value
+		for _, combination := range combinations {
+			for it := values.ElementIterator(); it.Next(); {
+				_, v := it.Element()
+
+				c := map[string]cty.Value{axis: v}
+				for k, existing := range combination {
+					c[k] = existing
+				}
+				next = append(next, c)
+			}
+		}
+		combinations = next
+	}
+
+	// Group under the block's label, so the whole matrix can be built by name.
+	group := &Group{}
+	for _, combination := range combinations {
+		child := ectx.NewChild()
+		child.Variables = combination
+
+		var name string
+		if err := evalInto(child, nameAttr, cty.String, &name); err != nil {
+			return fnerrors.Newf("name: %w", err)
+		}
+
+		if _, ok := def.Targets[name]; ok {
+			return fnerrors.Newf("matrix produces duplicate target %q", name)
+		}
+
+		t, err := evalTarget(child, attrs)
+		if err != nil {
+			return fnerrors.Newf("%s: %w", name, err)
+		}
+
+		def.Targets[name] = t
+		group.Targets = append(group.Targets, name)
+	}
+
+	def.Groups[block.Labels[0]] = group
+	return nil
+}
+
+func evalTarget(ectx *hcl.EvalContext, attrs hcl.Attributes) (*target, error) {
+	values := map[string]cty.Value{}
+	for name, attr := range attrs {
+		if name == "matrix" || name == "name" {
+			continue
+		}
+
+		typ, ok := targetAttrTypes[name]
+		if !ok {
+			return nil, fnerrors.Newf("%s: unsupported attribute", name)
+		}
+
+		v, diags := attr.Expr.Value(ectx)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+
+		converted, err := convert.Convert(v, typ)
+		if err != nil {
+			return nil, fnerrors.Newf("%s: %w", name, err)
+		}
+
+		values[name] = converted
+	}
+
+	obj := cty.ObjectVal(values)
+	serialized, err := ctyjson.Marshal(obj, obj.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	var t target
+	if err := json.Unmarshal(serialized, &t); err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+func evalInto(ectx *hcl.EvalContext, attr *hcl.Attribute, typ cty.Type, out any) error {
+	v, diags := attr.Expr.Value(ectx)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	converted, err := convert.Convert(v, typ)
+	if err != nil {
+		return err
+	}
+
+	serialized, err := ctyjson.Marshal(converted, typ)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(serialized, out)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -16,6 +16,7 @@ import (
 	"namespacelabs.dev/foundation/internal/artifacts/registry"
 	"namespacelabs.dev/foundation/internal/build"
 	"namespacelabs.dev/foundation/internal/build/assets"
+	"namespacelabs.dev/foundation/internal/build/attest"
 	"namespacelabs.dev/foundation/internal/build/multiplatform"
 	"namespacelabs.dev/foundation/internal/compute"
 	"namespacelabs.dev/foundation/internal/console"
@@ -100,6 +101,8 @@ func PlanBinary(ctx context.Context, pl pkggraph.PackageLoader, env cfg.Context,
 		Spec:          spec,
 		Workspace:     loc.Module,
 		Platforms:     platforms,
+		Definition:    binary.BuildPlan,
+		Module:        loc.Module,
 	}
 
 	return &Prepared{
@@ -345,5 +348,5 @@ func EnsureImage(ctx context.Context, env pkggraph.SealedContext, registry regis
 
 	name := registry.AllocateName(prepared.Name, "")
 
-	return compute.GetValue(ctx, oci.PublishResolvable(name, img, prepared.Plan))
+	return compute.GetValue(ctx, attest.Publish(name, img, prepared.Plan))
 }
@@ -31,15 +31,9 @@ type buildAlpine struct {
 }
 
 func (b *buildAlpine) BuildImage(ctx context.Context, env pkggraph.SealedContext, conf build.Configuration) (compute.Computable[oci.Image], error) {
-	var image string
-	if b.plan.Version != "" {
-		image = "docker.io/library/alpine@" + b.plan.Version
-	} else {
-		var err error
-		image, err = pins.CheckDefault("alpine")
-		if err != nil {
-			return nil, err
-		}
+	image, err := b.baseImage()
+	if err != nil {
+		return nil, err
 	}
 
 	if conf.TargetPlatform() == nil {
@@ -60,6 +54,23 @@ func (b *buildAlpine) BuildImage(ctx context.Context, env pkggraph.SealedContext
 	return buildkit.BuildDefinitionToImage(buildkit.DeferClient(env.Configuration(), conf.TargetPlatform()), conf, def), nil
 }
 
+func (b *buildAlpine) baseImage() (string, error) {
+	if b.plan.Version != "" {
+		return "docker.io/library/alpine@" + b.plan.Version, nil
+	}
+
+	return pins.CheckDefault("alpine")
+}
+
+func (b *buildAlpine) BaseImages(build.Workspace) ([]string, error) {
+	image, err := b.baseImage()
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{image}, nil
+}
+
 func (b *buildAlpine) PlatformIndependent() bool { return false }
 
 func (b *buildAlpine) Description() string { return "makeAlpine" }
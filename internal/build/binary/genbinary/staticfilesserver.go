@@ -6,7 +6,6 @@ package genbinary
 
 import (
 	"context"
-	"fmt"
 	"io/fs"
 
 	"namespacelabs.dev/foundation/internal/artifacts/oci"
@@ -14,6 +13,7 @@ import (
 	"namespacelabs.dev/foundation/internal/compute"
 	"namespacelabs.dev/foundation/internal/fnfs/digestfs"
 	"namespacelabs.dev/foundation/internal/fnfs/memfs"
+	"namespacelabs.dev/foundation/internal/staticfiles"
 	"namespacelabs.dev/foundation/schema"
 	"namespacelabs.dev/foundation/std/pkggraph"
 )
@@ -27,28 +27,16 @@ type nginxImage struct {
 }
 
 func (i nginxImage) BuildImage(ctx context.Context, env pkggraph.SealedContext, conf build.Configuration) (compute.Computable[oci.Image], error) {
-	// XXX this is not quite right. We always setup a index.html fallback
-	// regardless of content. And that's probably over-reaching. The user should
-	// let us know which paths require this fallback.
+	parsed, err := staticfiles.Parse(i.config)
+	if err != nil {
+		return nil, err
+	}
+
 	var defaultConf memfs.FS
-	defaultConf.Add("etc/nginx/conf.d/default.conf", []byte(fmt.Sprintf(`server {
-		listen %d;
-		server_name localhost;
-
-		location / {
-			root %s;
-			index index.html;
-			try_files $uri /index.html;
-		}
-
-		error_page 500 502 503 504 /50x.html;
-		location = /50x.html {
-			root /usr/share/nginx/html;
-		}
-}`, i.config.Port, i.config.Dir)))
+	defaultConf.Add("etc/nginx/conf.d/default.conf", []byte(parsed.NginxConf()))
 	config := oci.MakeLayer("conf", compute.Precomputed[fs.FS](&defaultConf, digestfs.Digest))
 
-	nginx := oci.ResolveImage("nginx:1.21.5-alpine", *conf.TargetPlatform())
+	nginx := oci.ResolveImage(parsed.Image, *conf.TargetPlatform())
 
 	// Workaround nscloud related pull-to-push authentication challenges.
 	localNginx := oci.LocalCopy(nginx)
@@ -56,6 +44,15 @@ func (i nginxImage) BuildImage(ctx context.Context, env pkggraph.SealedContext,
 	return oci.MergeImageLayers(localNginx, oci.MakeImageFromScratch("nginx-configuration", config)), nil
 }
 
+func (i nginxImage) BaseImages(build.Workspace) ([]string, error) {
+	parsed, err := staticfiles.Parse(i.config)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{parsed.Image}, nil
+}
+
 func (nginxImage) PlatformIndependent() bool { return false }
 
 func (nginxImage) Description() string { return "staticFileServer" }
@@ -56,6 +56,8 @@ func (m makeExt4Image) BuildImage(ctx context.Context, env pkggraph.SealedContex
 
 func (m makeExt4Image) Description() string { return fmt.Sprintf("makeExt4(%s)", m.spec.Description()) }
 
+// MakeDisk is not reproducible: the ext4 image embeds a random UUID and its
+// creation time, which imageutil doesn't allow overriding yet.
 func MakeDisk(inner compute.Computable[oci.Image], target string, size int64, raw bool) compute.Computable[oci.Image] {
 	return compute.Transform("binary.make-ext4-image", inner, func(ctx context.Context, img oci.Image) (oci.Image, error) {
 		dir, err := os.MkdirTemp("", "ext4")
@@ -18,6 +18,7 @@ import (
 	"namespacelabs.dev/foundation/internal/build"
 	"namespacelabs.dev/foundation/internal/compute"
 	"namespacelabs.dev/foundation/internal/console"
+	"namespacelabs.dev/foundation/internal/fnfs/maketarfs"
 	"namespacelabs.dev/foundation/internal/runtime/rtypes"
 	"namespacelabs.dev/foundation/std/pkggraph"
 	"namespacelabs.dev/foundation/std/tasks/idtypes"
@@ -100,8 +101,15 @@ func ToLocalSquashFS(ctx context.Context, image oci.Image, target string) error
 
 	out := console.TypedOutput(ctx, "tar2sqfs", idtypes.CatOutputTool)
 
+	// Without --keep-time, every inode gets the default modification time; use
+	// the same as layers do, so that the filesystem is reproducible.
+	modTime, err := maketarfs.ModTime()
+	if err != nil {
+		return err
+	}
+
 	if err := runCommandMaybeNixShell(ctx, rtypes.IO{Stdin: r, Stdout: out, Stderr: out},
-		"squashfs-tools-ng", "tar2sqfs", "-f", target); err != nil {
+		"squashfs-tools-ng", "tar2sqfs", "--defaults", fmt.Sprintf("mtime=%d", modTime.Unix()), "-f", target); err != nil {
 		return err
 	}
 
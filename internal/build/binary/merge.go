@@ -39,6 +39,19 @@ func (m MergeSpecs) BuildImage(ctx context.Context, env pkggraph.SealedContext,
 	return oci.MergeImageLayers(images...), nil
 }
 
+func (m MergeSpecs) BaseImages(ws build.Workspace) ([]string, error) {
+	var images []string
+	for _, spec := range m.Specs {
+		base, err := build.BaseImagesOf(spec, ws)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, base...)
+	}
+
+	return images, nil
+}
+
 func (m MergeSpecs) PlatformIndependent() bool { return m.platformIndependent }
 
 func (m MergeSpecs) Description() string {
@@ -40,6 +40,10 @@ func (m StampImage) BuildImage(ctx context.Context, env pkggraph.SealedContext,
 	return oci.AnnotateImage(named, anns), nil
 }
 
+func (m StampImage) BaseImages(ws build.Workspace) ([]string, error) {
+	return build.BaseImagesOf(m.Src, ws)
+}
+
 func (m StampImage) PlatformIndependent() bool { return m.Src.PlatformIndependent() }
 
 func (m StampImage) Description() string { return fmt.Sprintf("Stamp(%s)", m.Src.Description()) }
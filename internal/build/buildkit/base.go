@@ -158,7 +158,7 @@ func (l *baseRequest[V]) solve(ctx context.Context, c *GatewayClient, deps compu
 
 	sid := ids.NewRandomBase62ID(8)
 
-	attachables, err := prepareSession(ctx, keychain, l.secrets, req.Secrets)
+	attachables, err := prepareSession(ctx, keychain, l.secrets, req.Secrets, req.SecretValues)
 	if err != nil {
 		return res, err
 	}
@@ -216,7 +216,19 @@ func (l *baseRequest[V]) solve(ctx context.Context, c *GatewayClient, deps compu
 	solveOpt.OCIStores = map[string]content.Store{}
 	solveOpt.OCIStores["cache"] = &cacheStore{compute.Cache(ctx)}
 
-	fillInCaching(&solveOpt)
+	def := req.Def
+	if isCacheDisabled(ctx) {
+		def = ignoringCache(def)
+		if req.Frontend != "" {
+			attrs := map[string]string{"no-cache": ""}
+			for k, v := range req.FrontendAttrs {
+				attrs[k] = v
+			}
+			solveOpt.FrontendAttrs = attrs
+		}
+	} else {
+		fillInCaching(&solveOpt)
+	}
 
 	ch := make(chan *client.SolveStatus)
 
@@ -225,7 +237,7 @@ func (l *baseRequest[V]) solve(ctx context.Context, c *GatewayClient, deps compu
 	var solveRes *client.SolveResponse
 	eg.Go(func(ctx context.Context) error {
 		var err error
-		solveRes, err = c.Solve(ctx, req.Def, solveOpt, ch)
+		solveRes, err = c.Solve(ctx, def, solveOpt, ch)
 		return err
 	})
 
@@ -46,6 +46,9 @@ func BuildFilesystem(ctx context.Context, makeClient ClientFactory, target build
 type Input struct {
 	State   llb.State
 	Secrets []*schema.PackageRef
+	// Values made available as secrets, by ID. Unlike the rest of the input,
+	// they're not part of the build definition, and thus not of its cache key.
+	SecretValues map[string][]byte
 }
 
 func DeferBuildFilesystem(makeClient ClientFactory, secrets secrets.GroundedSecrets, target build.BuildTarget, state compute.Computable[*Input], localDirs ...LocalContents) compute.Computable[fs.FS] {
@@ -62,7 +65,7 @@ func DeferBuildFilesystem(makeClient ClientFactory, secrets secrets.GroundedSecr
 				return nil, err
 			}
 
-			return &FrontendRequest{Def: serialized, OriginalState: &state.State, Secrets: state.Secrets}, nil
+			return &FrontendRequest{Def: serialized, OriginalState: &state.State, Secrets: state.Secrets, SecretValues: state.SecretValues}, nil
 		}),
 	}
 	return &reqToFS{baseRequest: base}
@@ -5,12 +5,15 @@
 package buildkit
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"strings"
 
 	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/client/llb"
+	"github.com/opencontainers/go-digest"
 	"namespacelabs.dev/foundation/internal/fnerrors"
 )
 
@@ -19,6 +22,8 @@ var (
 	ExportCacheVar = &cacheVar{}
 )
 
+type withoutCacheKey struct{}
+
 type cacheVar struct {
 	cacheType string
 	args      map[string]string
@@ -52,6 +57,35 @@ func (c *cacheVar) Set(v string) error {
 
 func (*cacheVar) Type() string { return "" }
 
+// WithoutCache returns a context where builds don't use buildkit's cache: no
+// existing result is reused, and no cache is imported or exported.
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutCacheKey{}, true)
+}
+
+func isCacheDisabled(ctx context.Context) bool {
+	v, _ := ctx.Value(withoutCacheKey{}).(bool)
+	return v
+}
+
+// ignoringCache returns a copy of def where no op may be satisfied from cache.
+func ignoringCache(def *llb.Definition) *llb.Definition {
+	if def == nil {
+		return nil
+	}
+
+	out := *def
+	out.Metadata = map[digest.Digest]llb.OpMetadata{}
+	for _, dt := range def.Def {
+		d := digest.FromBytes(dt)
+		md := def.Metadata[d]
+		md.IgnoreCache = true
+		out.Metadata[d] = md
+	}
+
+	return &out
+}
+
 func fillInCaching(sopt *client.SolveOpt) {
 	if ImportCacheVar.cacheType != "" {
 		sopt.CacheImports = append(sopt.CacheImports, checkUseGithubCache(client.CacheOptionsEntry{
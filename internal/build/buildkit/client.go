@@ -31,6 +31,7 @@ import (
 	"namespacelabs.dev/foundation/std/cfg"
 	"namespacelabs.dev/foundation/std/cfg/knobs"
 	"namespacelabs.dev/foundation/std/tasks"
+	"namespacelabs.dev/foundation/std/tasks/actiontracing"
 
 	_ "github.com/moby/buildkit/client/connhelper/dockercontainer"
 )
@@ -123,7 +124,7 @@ func (c *clientInstance) Compute(ctx context.Context, _ compute.Resolved) (*Gate
 	if addr := BuildOnExistingBuildkit.Get(c.conf); addr != "" {
 		fmt.Fprintf(console.Debug(ctx), "buildkit: using existing buildkit: %q\n", addr)
 
-		cli, err := client.New(ctx, addr)
+		cli, err := dialClient(ctx, addr)
 		if err != nil {
 			return nil, err
 		}
@@ -132,7 +133,7 @@ func (c *clientInstance) Compute(ctx context.Context, _ compute.Resolved) (*Gate
 	}
 
 	if c.overrides.BuildkitAddr != "" {
-		cli, err := client.New(ctx, c.overrides.BuildkitAddr)
+		cli, err := dialClient(ctx, c.overrides.BuildkitAddr)
 		if err != nil {
 			return nil, err
 		}
@@ -165,7 +166,7 @@ func (c *clientInstance) Compute(ctx context.Context, _ compute.Resolved) (*Gate
 		}
 
 		return waitAndConnect(ctx, func(ctx context.Context) (*client.Client, error) {
-			return client.New(ctx, "buildkitd", client.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return dialClient(ctx, "buildkitd", client.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
 				return k.RawDialServer(ctx, conf.Namespace, conf.MatchingPodLabels, &schema.Endpoint_Port{ContainerPort: conf.TargetPort})
 			}))
 		})
@@ -210,7 +211,7 @@ func (c *clientInstance) Compute(ctx context.Context, _ compute.Resolved) (*Gate
 		return nil, err
 	}
 
-	cli, err := client.New(ctx, localAddr)
+	cli, err := dialClient(ctx, localAddr)
 	if err != nil {
 		return nil, err
 	}
@@ -271,7 +272,7 @@ func useRemoteCluster(ctx context.Context, cluster *api.KubernetesCluster, port
 	}
 
 	return waitAndConnect(ctx, func(ctx context.Context) (*client.Client, error) {
-		return client.New(ctx, "buildkitd", client.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return dialClient(ctx, "buildkitd", client.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
 			// Do the expirations work? We don't re-fetch tokens here yet.
 			return api.DialPortWithToken(ctx, token, cluster, port)
 		}))
@@ -286,7 +287,7 @@ func useRemoteClusterViaEndpoint(ctx context.Context, endpoint string) (*Gateway
 	}
 
 	return waitAndConnect(ctx, func(ctx context.Context) (*client.Client, error) {
-		return client.New(ctx, "buildkitd", client.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return dialClient(ctx, "buildkitd", client.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
 			// Do the expirations work? We don't re-fetch tokens here yet.
 			return api.DialEndpointWithToken(ctx, token, endpoint)
 		}))
@@ -295,7 +296,7 @@ func useRemoteClusterViaEndpoint(ctx context.Context, endpoint string) (*Gateway
 
 func useRemoteClusterViaMtls(ctx context.Context, bc cluster.BuilderConfig) (*GatewayClient, error) {
 	return waitAndConnect(ctx, func(ctx context.Context) (*client.Client, error) {
-		return client.New(ctx, bc.FullBuildkitEndpoint, client.WithCredentials(bc.ClientCertPath, bc.ClientKeyPath), client.WithServerConfig("", bc.ServerCAPath))
+		return dialClient(ctx, bc.FullBuildkitEndpoint, client.WithCredentials(bc.ClientCertPath, bc.ClientKeyPath), client.WithServerConfig("", bc.ServerCAPath))
 
 	})
 }
@@ -361,3 +362,13 @@ func newClient(ctx context.Context, cli *client.Client, docker bool) (*GatewayCl
 
 	return &GatewayClient{Client: cli, buildkitInDocker: docker, clientOpts: opts}, nil
 }
+
+// dialClient wraps client.New so that, when tracing is enabled, solves are
+// attributed to the invocation's trace.
+func dialClient(ctx context.Context, address string, opts ...client.ClientOpt) (*client.Client, error) {
+	if tp := actiontracing.TracerProvider(); tp != nil {
+		opts = append(opts, client.WithTracerProvider(tp))
+	}
+
+	return client.New(ctx, address, opts...)
+}
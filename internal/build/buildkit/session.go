@@ -7,6 +7,7 @@ package buildkit
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
 	"strings"
 
@@ -35,6 +36,7 @@ type FrontendRequest struct {
 	FrontendAttrs  map[string]string
 	FrontendInputs map[string]llb.State
 	Secrets        []*schema.PackageRef
+	SecretValues   map[string][]byte
 }
 
 func MakeLocalExcludes(src LocalContents) []string {
@@ -54,7 +56,7 @@ func MakeLocalState(src LocalContents) llb.State {
 		llb.ExcludePatterns(MakeLocalExcludes(src)))
 }
 
-func prepareSession(ctx context.Context, keychain oci.Keychain, src secrets.GroundedSecrets, secrets []*schema.PackageRef) ([]session.Attachable, error) {
+func prepareSession(ctx context.Context, keychain oci.Keychain, src secrets.GroundedSecrets, secrets []*schema.PackageRef, values map[string][]byte) ([]session.Attachable, error) {
 	var fs []secretsprovider.Source
 
 	for _, def := range strings.Split(BuildkitSecrets, ";") {
@@ -88,7 +90,11 @@ func prepareSession(ctx context.Context, keychain oci.Keychain, src secrets.Grou
 		return nil, err
 	}
 
-	secretValues := map[string][]byte{}
+	secretValues := maps.Clone(values)
+	if secretValues == nil {
+		secretValues = map[string][]byte{}
+	}
+
 	if len(secrets) > 0 {
 		if src == nil {
 			return nil, fnerrors.InternalError("secrets specified, but secret source missing")
@@ -43,6 +43,11 @@ type Plan struct {
 	// optimization purposes. This may be null, and an implementation can always
 	// elect to ignore it.
 	PublishName compute.Computable[oci.RepositoryWithParent]
+
+	// What's being built, and the module it's defined in. Both are optional,
+	// and only used to describe the build, e.g. in provenance attestations.
+	Definition *schema.LayeredImageBuildPlan
+	Module     *pkggraph.Module
 }
 
 func (p Plan) GetSourceLabel() string { return p.SourceLabel }
@@ -50,6 +55,21 @@ func (p Plan) GetSourcePackageRef() *schema.PackageRef {
 	return schema.MakePackageSingleRef(p.SourcePackage)
 }
 
+// BaseImagesSpec is implemented by specs which build on top of existing
+// images, so that their digests can be recorded in provenance attestations.
+type BaseImagesSpec interface {
+	BaseImages(Workspace) ([]string, error)
+}
+
+// BaseImagesOf returns the base images of the spec, if it reports them.
+func BaseImagesOf(spec Spec, ws Workspace) ([]string, error) {
+	if x, ok := spec.(BaseImagesSpec); ok {
+		return x.BaseImages(ws)
+	}
+
+	return nil, nil
+}
+
 type Workspace interface {
 	ModuleName() string
 	Abs() string
@@ -28,9 +28,13 @@ import (
 	"namespacelabs.dev/foundation/std/tasks"
 )
 
+const explainRunTopN = 10
+
 func NewBuildCmd() *cobra.Command {
 	var (
 		explain                = false
+		explainRun             = false
+		verifyReproducible     = false
 		continuously           = false
 		prebuiltBaseRepository string
 		env                    cfg.Context
@@ -47,6 +51,8 @@ func NewBuildCmd() *cobra.Command {
 		}).
 		WithFlags(func(flags *pflag.FlagSet) {
 			flags.BoolVar(&explain, "explain", false, "If set to true, rather than applying the graph, output an explanation of what would be done.")
+			flags.BoolVar(&explainRun, "explain_run", false, "If set to true, after building, output the critical path, the slowest steps, and why each cache miss was not cached. Cache misses are explained against the last build which also set explain_run.")
+			flags.BoolVar(&verifyReproducible, "verify_reproducible", false, "If set to true, builds twice, each time with empty caches, and fails if the resulting images differ.")
 			flags.Var(build.BuildPlatformsVar{}, "build_platforms", "Allows the runtime to be instructed to build for a different set of platforms; by default we only build for the development host.")
 			flags.BoolVarP(&continuously, "continuously", "c", continuously, "If set to true, builds continuously, listening to changes to the workspace.")
 			flags.StringVar(&prebuiltBaseRepository, "base_repository", "", "If set, also uploads the server binary build to the target prebuilt repository.")
@@ -65,11 +71,23 @@ func NewBuildCmd() *cobra.Command {
 				}
 			}
 
+			if explainRun && (explain || continuously) {
+				return fnerrors.BadInputError("explain_run is not compatible with explain or continuously")
+			}
+
 			p, err := planning.NewPlanner(ctx, env)
 			if err != nil {
 				return err
 			}
 
+			if verifyReproducible {
+				if explain || explainRun || continuously || prebuiltBaseRepository != "" {
+					return fnerrors.BadInputError("verify_reproducible is not compatible with explain, explain_run, continuously or base_repository")
+				}
+
+				return checkReproducible(ctx, p, servers.Servers)
+			}
+
 			_, images, err := deploy.ComputeStackAndImages(ctx, p, servers.Servers)
 			if err != nil {
 				return err
@@ -85,7 +103,16 @@ func NewBuildCmd() *cobra.Command {
 				return compute.Continuously(ctx, continuousBuild{allImages: buildAll}, nil)
 			}
 
+			var recorder *compute.RunRecorder
+			if explainRun {
+				recorder = compute.On(ctx).RecordRun()
+			}
+
 			res, err := compute.GetValue(ctx, buildAll)
+			if recorder != nil {
+				// Also useful when the build fails.
+				recorder.Report(console.Stdout(ctx), explainRunTopN)
+			}
 			if err != nil {
 				return err
 			}
@@ -23,6 +23,7 @@ import (
 	"namespacelabs.dev/foundation/internal/artifacts/registry"
 	"namespacelabs.dev/foundation/internal/build"
 	"namespacelabs.dev/foundation/internal/build/assets"
+	"namespacelabs.dev/foundation/internal/build/attest"
 	"namespacelabs.dev/foundation/internal/build/binary"
 	"namespacelabs.dev/foundation/internal/cli/fncobra"
 	"namespacelabs.dev/foundation/internal/compute"
@@ -132,6 +133,7 @@ func buildLocations(ctx context.Context, env cfg.Context, reg registry.Manager,
 	var images []compute.Computable[Binary]
 	for _, pkg := range pkgs {
 		var resolvables []compute.Computable[oci.ResolvableImage]
+		var plans []build.Plan
 
 		// TODO: allow to choose what binary to build within a package.
 		for _, b := range pkg.Binaries {
@@ -146,9 +148,10 @@ func buildLocations(ctx context.Context, env cfg.Context, reg registry.Manager,
 			}
 
 			resolvables = append(resolvables, image)
+			plans = append(plans, bin.Plan)
 		}
 
-		for _, image := range resolvables {
+		for k, image := range resolvables {
 			var repositories []compute.Computable[oci.RepositoryWithParent]
 			for _, bp := range baseRepository {
 				repositories = append(repositories, registry.StaticRepository(nil, filepath.Join(bp, pkg.PackageName().String()), oci.RegistryAccess{}))
@@ -163,7 +166,7 @@ func buildLocations(ctx context.Context, env cfg.Context, reg registry.Manager,
 				if opts.publishToDocker {
 					img = docker.PublishImage(repository, image)
 				} else {
-					img = oci.PublishResolvable(repository, image, nil)
+					img = attest.Publish(repository, image, plans[k])
 				}
 
 				images = append(images, fromImage(pkg.PackageName(), img))
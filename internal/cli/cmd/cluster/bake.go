@@ -0,0 +1,554 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package cluster
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/identity"
+	"github.com/moby/buildkit/util/progress/progresswriter"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"namespacelabs.dev/foundation/internal/artifacts/oci"
+	"namespacelabs.dev/foundation/internal/build/bake"
+	"namespacelabs.dev/foundation/internal/console"
+	"namespacelabs.dev/foundation/internal/executor"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/internal/localexec"
+	"namespacelabs.dev/foundation/internal/parsing/platform"
+	"namespacelabs.dev/foundation/internal/providers/nscloud/api"
+)
+
+type bakeOpts struct {
+	// If set, override the outputs of every target.
+	push, load bool
+	makeClient func(context.Context, specs.Platform) (*client.Client, error)
+}
+
+type bakeOutputKind int
+
+const (
+	bakeOutputCacheOnly bakeOutputKind = iota
+	bakeOutputRegistry
+	bakeOutputDocker
+	bakeOutputLocal
+)
+
+type bakeOutput struct {
+	kind bakeOutputKind
+	// Image names, if set by the output; otherwise the target's tags are used.
+	names []string
+	dest  string
+}
+
+// bakePlan is what's built for each target.
+type bakePlan struct {
+	target    *bake.Target
+	platforms []specs.Platform
+	output    bakeOutput
+	tags      []name.Tag
+	secrets   []buildSecret
+
+	cacheImports []client.CacheOptionsEntry
+	cacheExports []client.CacheOptionsEntry
+
+	// Closed once the target has been built.
+	done chan struct{}
+	// A digest reference to the pushed image (or index), if the target was pushed.
+	ref string
+	// Images to load into docker once all targets are built.
+	loads []string
+}
+
+func runBake(ctx context.Context, file string, names []string, opts bakeOpts) error {
+	def, err := bake.ParseFile(file)
+	if err != nil {
+		return err
+	}
+
+	targets, err := def.Resolve(names)
+	if err != nil {
+		return err
+	}
+
+	plans := map[string]*bakePlan{}
+	var ordered []*bakePlan
+	for _, t := range targets {
+		p, err := planBakeTarget(t, opts)
+		if err != nil {
+			return err
+		}
+
+		for _, value := range t.Contexts {
+			if dep, ok := bake.TargetContext(value); ok && plans[dep].output.kind != bakeOutputRegistry {
+				return fnerrors.BadInputError("%s: uses target %q as a context, so it must be pushed (set its output to type=registry, or use --push)", t.Name, dep)
+			}
+		}
+
+		plans[t.Name] = p
+		ordered = append(ordered, p)
+	}
+
+	// Targets share a client per platform.
+	clients := map[string]*client.Client{}
+	for _, p := range ordered {
+		for _, plat := range p.platforms {
+			clients[platform.FormatPlatform(plat)] = nil
+		}
+	}
+
+	var mu sync.Mutex
+	clientsEg := executor.New(ctx, "clients")
+	for key := range clients {
+		key := key // Close key
+
+		clientsEg.Go(func(ctx context.Context) error {
+			plat, err := platform.ParsePlatform(key)
+			if err != nil {
+				return err
+			}
+
+			cli, err := opts.makeClient(ctx, plat)
+			if err != nil {
+				return err
+			}
+
+			mu.Lock()
+			clients[key] = cli
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	if err := clientsEg.Wait(); err != nil {
+		return err
+	}
+
+	defer func() {
+		for _, p := range ordered {
+			for _, f := range p.loads {
+				os.Remove(f)
+			}
+		}
+	}()
+
+	done := console.EnterInputMode(ctx)
+	defer done()
+
+	// not using shared context to not disrupt display but let is finish reporting errors
+	pw, err := progresswriter.NewPrinter(context.Background(), os.Stderr, "auto")
+	if err != nil {
+		return err
+	}
+
+	mw := progresswriter.NewMultiWriter(pw)
+
+	// The printer finishes when all of its writers are closed; targets start
+	// while others are already done, so hold it open until all are built.
+	keepalive := mw.WithPrefix("", false)
+
+	// Builds share local directory transfers with each other.
+	sharedKey := identity.NewID()
+
+	eg := executor.New(ctx, "nsc/bake")
+
+	eg.Go(func(ctx context.Context) error {
+		defer close(keepalive.Status())
+
+		targetsEg := executor.New(ctx, "nsc/bake/targets")
+		for _, p := range ordered {
+			p := p // Close p
+
+			targetsEg.Go(func(ctx context.Context) error {
+				for _, dep := range p.target.DependsOn {
+					select {
+					case <-plans[dep].done:
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+				}
+
+				if err := buildBakeTarget(ctx, p, plans, clients, mw, sharedKey); err != nil {
+					return fnerrors.Newf("%s: %w", p.target.Name, err)
+				}
+
+				close(p.done)
+				return nil
+			})
+		}
+
+		return targetsEg.Wait()
+	})
+
+	eg.Go(func(ctx context.Context) error {
+		select {
+		case <-pw.Done():
+			return pw.Err()
+
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	out := console.Stdout(ctx)
+	for _, p := range ordered {
+		for _, image := range p.loads {
+			t := time.Now()
+			dockerLoad := exec.CommandContext(ctx, "docker", "load", "-i", image)
+			if err := localexec.RunInteractive(ctx, dockerLoad); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Took %v to upload %s to Docker.\n", time.Since(t), p.target.Name)
+		}
+	}
+
+	var pushed bool
+	for _, p := range ordered {
+		if p.output.kind != bakeOutputRegistry {
+			continue
+		}
+
+		if !pushed {
+			fmt.Fprint(out, "Pushed:\n")
+			pushed = true
+		}
+
+		for _, tag := range p.tags {
+			fmt.Fprintf(out, "  %s: %s\n", p.target.Name, tag.Name())
+		}
+	}
+
+	fmt.Fprintf(out, "\nBuilt %d %s.\n", len(ordered), plural(len(ordered), "target", "targets"))
+	return nil
+}
+
+func planBakeTarget(t *bake.Target, opts bakeOpts) (*bakePlan, error) {
+	output, err := parseBakeOutput(t, opts)
+	if err != nil {
+		return nil, fnerrors.BadInputError("%s: %w", t.Name, err)
+	}
+
+	p := &bakePlan{target: t, output: output, done: make(chan struct{})}
+
+	platforms := t.Platforms
+	if len(platforms) == 0 {
+		if output.kind == bakeOutputDocker {
+			hostPlatform := platform.RuntimePlatform()
+			hostPlatform.OS = "linux"
+			platforms = []string{platform.FormatPlatform(hostPlatform)}
+		} else {
+			platforms = []string{"linux/amd64"}
+		}
+	}
+
+	for _, plat := range platforms {
+		parsed, err := platform.ParsePlatform(plat)
+		if err != nil {
+			return nil, fnerrors.BadInputError("%s: %w", t.Name, err)
+		}
+		p.platforms = append(p.platforms, parsed)
+	}
+
+	if len(p.platforms) > 1 && output.kind == bakeOutputDocker {
+		return nil, fnerrors.BadInputError("%s: multi-platform builds can't be loaded into docker", t.Name)
+	}
+
+	imageNames := output.names
+	if len(imageNames) == 0 {
+		imageNames = t.Tags
+	}
+
+	for _, n := range imageNames {
+		parsed, err := name.NewTag(n)
+		if err != nil {
+			return nil, fnerrors.BadInputError("%s: invalid tag %s: %w", t.Name, n, err)
+		}
+		p.tags = append(p.tags, parsed)
+	}
+
+	if output.kind == bakeOutputRegistry && len(p.tags) == 0 {
+		return nil, fnerrors.BadInputError("%s: pushing requires at least one tag", t.Name)
+	}
+
+	secrets, err := parseSecretSpecs(t.Secrets)
+	if err != nil {
+		return nil, fnerrors.BadInputError("%s: %w", t.Name, err)
+	}
+	p.secrets = secrets
+
+	if p.cacheImports, err = parseCacheSpecs(t.CacheFrom, false); err != nil {
+		return nil, fnerrors.BadInputError("%s: %w", t.Name, err)
+	}
+
+	if p.cacheExports, err = parseCacheSpecs(t.CacheTo, true); err != nil {
+		return nil, fnerrors.BadInputError("%s: %w", t.Name, err)
+	}
+
+	return p, nil
+}
+
+// parseBakeOutput parses the target's output, e.g. "type=registry" or
+// "type=local,dest=out". Only a single output is supported.
+func parseBakeOutput(t *bake.Target, opts bakeOpts) (bakeOutput, error) {
+	switch {
+	case opts.push:
+		return bakeOutput{kind: bakeOutputRegistry}, nil
+
+	case opts.load:
+		return bakeOutput{kind: bakeOutputDocker}, nil
+	}
+
+	switch len(t.Outputs) {
+	case 0:
+		return bakeOutput{kind: bakeOutputCacheOnly}, nil
+
+	case 1:
+
+	default:
+		return bakeOutput{}, fnerrors.Newf("multiple outputs are not supported")
+	}
+
+	spec := t.Outputs[0]
+	if !strings.Contains(spec, "=") {
+		// A plain path is shorthand for a local output.
+		return bakeOutput{kind: bakeOutputLocal, dest: spec}, nil
+	}
+
+	fields, err := csv.NewReader(strings.NewReader(spec)).Read()
+	if err != nil {
+		return bakeOutput{}, fnerrors.Newf("invalid output %q: %w", spec, err)
+	}
+
+	attrs := map[string]string{}
+	for _, field := range fields {
+		k, v, ok := strings.Cut(field, "=")
+		if !ok {
+			return bakeOutput{}, fnerrors.Newf("invalid output %q: expected key=value, got %q", spec, field)
+		}
+		attrs[strings.ToLower(strings.TrimSpace(k))] = v
+	}
+
+	var out bakeOutput
+	if n := attrs["name"]; n != "" {
+		out.names = strings.Split(n, ",")
+	}
+
+	switch attrs["type"] {
+	case "registry":
+		out.kind = bakeOutputRegistry
+
+	case "image":
+		if attrs["push"] != "true" {
+			return bakeOutput{}, fnerrors.Newf("output %q: images are only supported with push=true; use type=docker to load it instead", spec)
+		}
+		out.kind = bakeOutputRegistry
+
+	case "docker":
+		out.kind = bakeOutputDocker
+
+	case "local":
+		if attrs["dest"] == "" {
+			return bakeOutput{}, fnerrors.Newf("output %q: dest is required", spec)
+		}
+		out.kind = bakeOutputLocal
+		out.dest = attrs["dest"]
+
+	case "cacheonly":
+		out.kind = bakeOutputCacheOnly
+
+	default:
+		return bakeOutput{}, fnerrors.Newf("output %q: unsupported type %q", spec, attrs["type"])
+	}
+
+	return out, nil
+}
+
+func buildBakeTarget(ctx context.Context, p *bakePlan, plans map[string]*bakePlan, clients map[string]*client.Client, mw *progresswriter.MultiWriter, sharedKey string) error {
+	t := p.target
+
+	namedContexts := map[string]string{}
+	localDirs := map[string]string{}
+	for k, v := range t.Contexts {
+		switch dep, ok := bake.TargetContext(v); {
+		case ok:
+			namedContexts[k] = "docker-image://" + plans[dep].ref
+
+		case strings.Contains(v, "://"):
+			namedContexts[k] = v
+
+		default:
+			localDirs[k] = v
+			namedContexts[k] = "local:" + k
+		}
+	}
+
+	var imageNames []string
+	for _, tag := range p.tags {
+		imageNames = append(imageNames, tag.Name())
+	}
+
+	fragments := make([]BuildFragment, len(p.platforms))
+	for k, plat := range p.platforms {
+		formatted := platform.FormatPlatform(plat)
+
+		bf := BuildFragment{
+			ContextDir:     t.Context,
+			Dockerfile:     t.Dockerfile,
+			BuildArgs:      t.Args,
+			Platform:       plat,
+			Secrets:        p.secrets,
+			Target:         t.Target,
+			Labels:         t.Labels,
+			NamedContexts:  namedContexts,
+			LocalDirs:      localDirs,
+			NoCache:        t.NoCache,
+			Pull:           t.Pull,
+			SharedKey:      sharedKey,
+			ProgressPrefix: t.Name,
+			CacheImports:   p.cacheImports,
+			CacheExports:   p.cacheExports,
+		}
+
+		if len(p.platforms) > 1 {
+			bf.ProgressPrefix = t.Name + " " + formatted
+			bf.CacheImports = cacheForPlatform(p.cacheImports, formatted)
+			bf.CacheExports = cacheForPlatform(p.cacheExports, formatted)
+		}
+
+		if t.DockerfileInline != "" {
+			bf.Dockerfile = ""
+			bf.DockerfileContents = []byte(t.DockerfileInline)
+		}
+
+		switch p.output.kind {
+		case bakeOutputRegistry:
+			names := imageNames
+			// Multi-platform images are pushed individually, and then an
+			// index pointing to them is pushed to each of the tags.
+			if len(p.platforms) > 1 {
+				names = []string{platformImageName(p.tags[0], formatted)}
+			}
+
+			bf.Exports = append(bf.Exports, client.ExportEntry{
+				Type: "image",
+				Attrs: map[string]string{
+					"push": "true",
+					"name": strings.Join(names, ","),
+				},
+			})
+
+		case bakeOutputDocker:
+			f, err := os.CreateTemp("", "docker-image-nsc")
+			if err != nil {
+				return err
+			}
+
+			p.loads = append(p.loads, f.Name())
+
+			export := client.ExportEntry{
+				Type: "docker",
+				Output: func(map[string]string) (io.WriteCloser, error) {
+					return f, nil
+				},
+				Attrs: map[string]string{},
+			}
+
+			if len(imageNames) > 0 {
+				export.Attrs["name"] = strings.Join(imageNames, ",")
+			}
+
+			bf.Exports = append(bf.Exports, export)
+
+		case bakeOutputLocal:
+			dest := p.output.dest
+			if len(p.platforms) > 1 {
+				dest = filepath.Join(dest, strings.ReplaceAll(formatted, "/", "_"))
+			}
+
+			if err := os.MkdirAll(dest, 0755); err != nil {
+				return err
+			}
+
+			bf.Exports = append(bf.Exports, client.ExportEntry{
+				Type:      "local",
+				OutputDir: dest,
+			})
+		}
+
+		fragments[k] = bf
+	}
+
+	eg := executor.New(ctx, "nsc/bake/"+t.Name)
+
+	results := make([]*client.SolveResponse, len(fragments))
+	for k, bf := range fragments {
+		k := k // Close k
+
+		startSingleBuild(eg, clients[platform.FormatPlatform(bf.Platform)], mw, bf, func(sr *client.SolveResponse) error {
+			results[k] = sr
+			return nil
+		})
+	}
+
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	if p.output.kind != bakeOutputRegistry {
+		return nil
+	}
+
+	repository := p.tags[0].Repository.Name()
+
+	if len(results) == 1 {
+		digest := results[0].ExporterResponse["containerimage.digest"]
+		if digest == "" {
+			return fnerrors.InternalError("expected containerimage.digest in result")
+		}
+
+		p.ref = repository + "@" + digest
+		return nil
+	}
+
+	images, err := fetchPushedImages(ctx, results, fragments)
+	if err != nil {
+		return err
+	}
+
+	index, err := oci.RawMakeIndex(images...)
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range p.tags {
+		digest, err := index.Push(ctx, oci.RepositoryWithAccess{
+			Repository: tag.Name(),
+			RegistryAccess: oci.RegistryAccess{
+				Keychain: api.DefaultKeychainWithFallback,
+			},
+		}, false)
+		if err != nil {
+			return err
+		}
+
+		p.ref = repository + "@" + digest.String()
+	}
+
+	return nil
+}
@@ -59,9 +59,9 @@ var (
 
 func NewBuildCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "build",
+		Use:   "build [context | --bake file [targets...]]",
 		Short: "Build an image in a build instance.",
-		Args:  cobra.MaximumNArgs(1),
+		Args:  cobra.ArbitraryArgs,
 	}
 
 	dockerFile := cmd.Flags().StringP("file", "f", "", "If set, specifies what Dockerfile to build.")
@@ -73,6 +73,9 @@ func NewBuildCmd() *cobra.Command {
 	names := cmd.Flags().StringSliceP("name", "n", nil, "Provide a list of name tags for the image in nscr.io Workspace registry")
 	secrets := cmd.Flags().StringArray("secret", nil, `Secret to expose to the build (format: "id=mysecret[,src=/local/secret]")`)
 	outputLocal := cmd.Flags().String("output-local", "", "If set, outputs the build results to the specified directory.")
+	cacheFrom := cmd.Flags().StringArray("cache-from", nil, `External cache sources (e.g. "user/app:cache", "type=local,src=path/to/dir"). When building multiple platforms, each platform uses its own cache: registry refs get the platform appended to their tag (e.g. "user/app:cache-linux-amd64"), and local directories get a per-platform subdirectory (e.g. "path/to/dir/linux-amd64").`)
+	cacheTo := cmd.Flags().StringArray("cache-to", nil, `Cache export destinations (e.g. "type=registry,ref=user/app:cache,mode=max", "type=local,dest=path/to/dir", "type=inline"). When building multiple platforms, each platform is exported separately, using the same per-platform refs and directories as --cache-from.`)
+	bakeFile := cmd.Flags().String("bake", "", "If set, builds the targets (or groups) specified as arguments from this bake file (HCL or JSON). Defaults to the \"default\" group. Targets with multiple platforms use per-platform caches, as described in --cache-from.")
 
 	useServerSideProxy := cmd.Flags().Bool("use_server_side_proxy", true, "If set, the client is set up to use a transparent mTLS server-side proxy instead of websockets.")
 	_ = cmd.Flags().MarkHidden("use_server_side_proxy")
@@ -82,6 +85,28 @@ func NewBuildCmd() *cobra.Command {
 	_ = cmd.Flags().MarkHidden("builder_experimental")
 
 	cmd.RunE = fncobra.RunE(func(ctx context.Context, specifiedArgs []string) error {
+		if *bakeFile != "" {
+			for _, flag := range []string{"file", "tag", "platform", "build-arg", "name", "secret", "output-local", "cache-from", "cache-to"} {
+				if cmd.Flags().Changed(flag) {
+					return fnerrors.Newf("--%s can't be used with --bake; set it in the bake file instead", flag)
+				}
+			}
+
+			if *push && *dockerLoad {
+				return fnerrors.Newf("only one of --push or --load can be used at a time")
+			}
+
+			return runBake(ctx, *bakeFile, specifiedArgs, bakeOpts{
+				push:       *push,
+				load:       *dockerLoad,
+				makeClient: MakeWireBuilder(*useServerSideProxy, *builderExperimental, *waitUntilReady),
+			})
+		}
+
+		if len(specifiedArgs) > 1 {
+			return fnerrors.Newf("expected at most one context directory, got %d arguments", len(specifiedArgs))
+		}
+
 		if len(*tags) > 0 && len(*names) > 0 {
 			return fnerrors.Newf("usage of both --tag and --name flags is not supported")
 		}
@@ -174,6 +199,16 @@ func NewBuildCmd() *cobra.Command {
 			return err
 		}
 
+		cacheImports, err := parseCacheSpecs(*cacheFrom, false)
+		if err != nil {
+			return err
+		}
+
+		cacheExports, err := parseCacheSpecs(*cacheTo, true)
+		if err != nil {
+			return err
+		}
+
 		var fragments []BuildFragment
 		var localImages []string
 		for _, p := range *platforms {
@@ -189,7 +224,7 @@ func NewBuildCmd() *cobra.Command {
 			// When performing a multi-platform build, we only need a single
 			// remote reference to point an index at.
 			if len(*platforms) > 1 && len(parsedTags) > 0 {
-				imageNames = append(imageNames, fmt.Sprintf("%s:%s-%s", parsedTags[0].Repository.Name(), strings.ReplaceAll(formatted, "/", "-"), ids.NewRandomBase32ID(4)))
+				imageNames = append(imageNames, platformImageName(parsedTags[0], formatted))
 			} else {
 				for _, parsed := range parsedTags {
 					imageNames = append(imageNames, parsed.Name())
@@ -197,10 +232,17 @@ func NewBuildCmd() *cobra.Command {
 			}
 
 			bf := BuildFragment{
-				ContextDir: contextDir,
-				Platform:   platformSpec,
-				BuildArgs:  buildArgs,
-				Secrets:    parsedSecrets,
+				ContextDir:   contextDir,
+				Platform:     platformSpec,
+				BuildArgs:    buildArgs,
+				Secrets:      parsedSecrets,
+				CacheImports: cacheImports,
+				CacheExports: cacheExports,
+			}
+
+			if len(*platforms) > 1 {
+				bf.CacheImports = cacheForPlatform(cacheImports, formatted)
+				bf.CacheExports = cacheForPlatform(cacheExports, formatted)
 			}
 
 			if *dockerFile != "" {
@@ -263,28 +305,9 @@ func NewBuildCmd() *cobra.Command {
 
 		switch {
 		case *push:
-			images := make([]oci.RawImageWithPlatform, len(*platforms))
-
-			for k, r := range results {
-				name := r.ExporterResponse["image.name"]
-				if name == "" {
-					return rpcerrors.Errorf(codes.Internal, "expected image.name in result")
-				}
-
-				ref, remoteOpts, err := oci.ParseRefAndKeychain(ctx, name, oci.RegistryAccess{Keychain: api.DefaultKeychainWithFallback})
-				if err != nil {
-					return err
-				}
-
-				image, err := remote.Image(ref, remoteOpts...)
-				if err != nil {
-					return fnerrors.InvocationError("registry", "failed to fetch image: %w", err)
-				}
-
-				images[k] = oci.RawImageWithPlatform{
-					Image:    image,
-					Platform: fragments[k].Platform,
-				}
+			images, err := fetchPushedImages(ctx, results, fragments)
+			if err != nil {
+				return err
 			}
 
 			if len(images) > 1 {
@@ -338,6 +361,44 @@ func NewBuildCmd() *cobra.Command {
 	return cmd
 }
 
+// platformImageName returns a unique name for a single-platform image, that an
+// index will be pointed at.
+func platformImageName(tag name.Tag, formattedPlatform string) string {
+	return fmt.Sprintf("%s:%s-%s", tag.Repository.Name(), strings.ReplaceAll(formattedPlatform, "/", "-"), ids.NewRandomBase32ID(4))
+}
+
+// fetchPushedImages returns the images that were pushed by each build.
+func fetchPushedImages(ctx context.Context, results []*client.SolveResponse, fragments []BuildFragment) ([]oci.RawImageWithPlatform, error) {
+	images := make([]oci.RawImageWithPlatform, len(results))
+
+	for k, r := range results {
+		name := r.ExporterResponse["image.name"]
+		if name == "" {
+			return nil, rpcerrors.Errorf(codes.Internal, "expected image.name in result")
+		}
+
+		// image.name may list multiple names; they all refer to the same image.
+		name, _, _ = strings.Cut(name, ",")
+
+		ref, remoteOpts, err := oci.ParseRefAndKeychain(ctx, name, oci.RegistryAccess{Keychain: api.DefaultKeychainWithFallback})
+		if err != nil {
+			return nil, err
+		}
+
+		image, err := remote.Image(ref, remoteOpts...)
+		if err != nil {
+			return nil, fnerrors.InvocationError("registry", "failed to fetch image: %w", err)
+		}
+
+		images[k] = oci.RawImageWithPlatform{
+			Image:    image,
+			Platform: fragments[k].Platform,
+		}
+	}
+
+	return images, nil
+}
+
 func plural(n int, singular, plural string) string {
 	if n == 1 {
 		return singular
@@ -354,6 +415,24 @@ type BuildFragment struct {
 	Platform           specs.Platform
 	Exports            []client.ExportEntry
 	Secrets            []buildSecret
+
+	// The stage to build; defaults to the last one.
+	Target string
+	Labels map[string]string
+	// Named contexts, passed as-is to the dockerfile frontend (e.g. "docker-image://alpine").
+	NamedContexts map[string]string
+	// Additional local directories, which named contexts can refer to with "local:<name>".
+	LocalDirs map[string]string
+	NoCache   bool
+	// If set, always pulls base images.
+	Pull bool
+	// Builds with the same key share the local directory transfers of previous builds.
+	SharedKey string
+	// Defaults to the platform.
+	ProgressPrefix string
+
+	CacheImports []client.CacheOptionsEntry
+	CacheExports []client.CacheOptionsEntry
 }
 
 func StartBuilds(ctx context.Context, fragments []BuildFragment, makeClient func(context.Context, specs.Platform) (*client.Client, error)) ([]*client.SolveResponse, error) {
@@ -486,15 +565,49 @@ func startSingleBuild(eg *executor.Executor, c *client.Client, mw *progresswrite
 		if bf.DockerfileContents != nil {
 			solveOpt.FrontendInputs = map[string]llb.State{
 				dockerui.DefaultLocalNameDockerfile: makeDockerfileState(bf.DockerfileContents),
-				dockerui.DefaultLocalNameContext: llb.Scratch().
-					File(llb.Mkfile("/empty", 0644, []byte{})), // Empty context.
+			}
+
+			if bf.ContextDir == "" {
+				solveOpt.FrontendInputs[dockerui.DefaultLocalNameContext] = llb.Scratch().
+					File(llb.Mkfile("/empty", 0644, []byte{})) // Empty context.
 			}
 		}
 
+		for k, v := range bf.LocalDirs {
+			if solveOpt.LocalDirs == nil {
+				solveOpt.LocalDirs = map[string]string{}
+			}
+			solveOpt.LocalDirs[k] = v
+		}
+
 		for k, v := range bf.BuildArgs {
 			solveOpt.FrontendAttrs["build-arg:"+k] = v
 		}
 
+		for k, v := range bf.Labels {
+			solveOpt.FrontendAttrs["label:"+k] = v
+		}
+
+		for k, v := range bf.NamedContexts {
+			solveOpt.FrontendAttrs["context:"+k] = v
+		}
+
+		if bf.Target != "" {
+			solveOpt.FrontendAttrs["target"] = bf.Target
+		}
+
+		if bf.NoCache {
+			solveOpt.FrontendAttrs["no-cache"] = ""
+		}
+
+		if bf.Pull {
+			solveOpt.FrontendAttrs["image-resolve-mode"] = "pull"
+		}
+
+		solveOpt.SharedKey = bf.SharedKey
+		solveOpt.CacheImports = bf.CacheImports
+		solveOpt.CacheExports = bf.CacheExports
+
 		var writers []progresswriter.Writer
 		for _, at := range attachable {
 			if s, ok := at.(interface {
@@ -514,7 +627,12 @@ func startSingleBuild(eg *executor.Executor, c *client.Client, mw *progresswrite
 			}
 		}()
 
-		statusCh := progresswriter.ResetTime(mw.WithPrefix(platform.FormatPlatform(bf.Platform), true)).Status()
+		prefix := bf.ProgressPrefix
+		if prefix == "" {
+			prefix = platform.FormatPlatform(bf.Platform)
+		}
+
+		statusCh := progresswriter.ResetTime(mw.WithPrefix(prefix, true)).Status()
 		resp, err := c.Solve(ctx, nil, solveOpt, statusCh)
 		if err != nil {
 			return err
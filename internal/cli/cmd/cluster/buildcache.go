@@ -0,0 +1,151 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package cluster
+
+import (
+	"encoding/csv"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/moby/buildkit/client"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+)
+
+// parseCacheSpecs parses --cache-from (or --cache-to, if export is set) values,
+// following buildx's syntax:
+//
+//	type=registry,ref=<image>[,mode=min|max]  (a plain image reference is shorthand for this)
+//	type=local,src=<dir> (import) or type=local,dest=<dir> (export), an OCI layout
+//	type=inline (export only); import it with type=registry,ref=<image>
+func parseCacheSpecs(specs []string, export bool) ([]client.CacheOptionsEntry, error) {
+	var entries []client.CacheOptionsEntry
+	for _, spec := range specs {
+		if spec == "" {
+			continue
+		}
+
+		entry, err := parseCacheSpec(spec, export)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func parseCacheSpec(spec string, export bool) (client.CacheOptionsEntry, error) {
+	if !strings.Contains(spec, "=") {
+		spec = "type=registry,ref=" + spec
+	}
+
+	fields, err := csv.NewReader(strings.NewReader(spec)).Read()
+	if err != nil {
+		return client.CacheOptionsEntry{}, fnerrors.BadInputError("invalid cache option %q: %w", spec, err)
+	}
+
+	entry := client.CacheOptionsEntry{Attrs: map[string]string{}}
+	for _, field := range fields {
+		k, v, ok := strings.Cut(field, "=")
+		if !ok {
+			return client.CacheOptionsEntry{}, fnerrors.BadInputError("invalid cache option %q: expected key=value, got %q", spec, field)
+		}
+
+		k = strings.ToLower(strings.TrimSpace(k))
+		if k == "type" {
+			entry.Type = v
+		} else {
+			entry.Attrs[k] = v
+		}
+	}
+
+	if entry.Type == "" {
+		if entry.Attrs["ref"] == "" {
+			return client.CacheOptionsEntry{}, fnerrors.BadInputError("invalid cache option %q: type is required", spec)
+		}
+		entry.Type = "registry"
+	}
+
+	if mode, ok := entry.Attrs["mode"]; ok {
+		if !export {
+			return client.CacheOptionsEntry{}, fnerrors.BadInputError("invalid cache option %q: mode only applies to exports", spec)
+		}
+
+		if mode != "min" && mode != "max" {
+			return client.CacheOptionsEntry{}, fnerrors.BadInputError("invalid cache option %q: mode must be min or max", spec)
+		}
+	}
+
+	switch entry.Type {
+	case "registry":
+		if entry.Attrs["ref"] == "" {
+			return client.CacheOptionsEntry{}, fnerrors.BadInputError("invalid cache option %q: ref is required", spec)
+		}
+
+	case "local":
+		attr := "src"
+		if export {
+			attr = "dest"
+		}
+
+		dir := entry.Attrs[attr]
+		if dir == "" {
+			return client.CacheOptionsEntry{}, fnerrors.BadInputError("invalid cache option %q: %s is required", spec, attr)
+		}
+
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return client.CacheOptionsEntry{}, err
+		}
+		entry.Attrs[attr] = abs
+
+	case "inline":
+		if !export {
+			return client.CacheOptionsEntry{}, fnerrors.BadInputError("invalid cache option %q: inline caches are imported with type=registry,ref=<image>", spec)
+		}
+
+	default:
+		return client.CacheOptionsEntry{}, fnerrors.BadInputError("invalid cache option %q: unsupported type %q (expected registry, local or inline)", spec, entry.Type)
+	}
+
+	return entry, nil
+}
+
+// cacheForPlatform returns the cache entries to use when building a single
+// platform out of a multi-platform build. Each platform is built with a
+// separate solve, so registry and local caches are kept separately per
+// platform, rather than overwriting each other. This is documented in the
+// help of --cache-from and --cache-to; keep them in sync.
+func cacheForPlatform(entries []client.CacheOptionsEntry, formattedPlatform string) []client.CacheOptionsEntry {
+	suffix := strings.ReplaceAll(formattedPlatform, "/", "-")
+
+	res := make([]client.CacheOptionsEntry, len(entries))
+	for k, entry := range entries {
+		attrs := map[string]string{}
+		for key, value := range entry.Attrs {
+			attrs[key] = value
+		}
+
+		switch entry.Type {
+		case "registry":
+			if tag, err := name.NewTag(attrs["ref"]); err == nil {
+				attrs["ref"] = tag.Repository.Tag(tag.TagStr() + "-" + suffix).Name()
+			}
+
+		case "local":
+			for _, attr := range []string{"src", "dest"} {
+				if dir, ok := attrs[attr]; ok {
+					attrs[attr] = filepath.Join(dir, suffix)
+				}
+			}
+		}
+
+		res[k] = client.CacheOptionsEntry{Type: entry.Type, Attrs: attrs}
+	}
+
+	return res
+}
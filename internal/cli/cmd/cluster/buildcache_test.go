@@ -0,0 +1,77 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package cluster
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/moby/buildkit/client"
+)
+
+func TestParseCacheSpec(t *testing.T) {
+	abs, err := filepath.Abs("cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		spec     string
+		export   bool
+		expected client.CacheOptionsEntry
+		wantErr  bool
+	}{
+		{"shorthand", "user/app:cache", false, client.CacheOptionsEntry{Type: "registry", Attrs: map[string]string{"ref": "user/app:cache"}}, false},
+		{"registry max", "type=registry,ref=user/app:cache,mode=max", true, client.CacheOptionsEntry{Type: "registry", Attrs: map[string]string{"ref": "user/app:cache", "mode": "max"}}, false},
+		{"local import", "type=local,src=cache", false, client.CacheOptionsEntry{Type: "local", Attrs: map[string]string{"src": abs}}, false},
+		{"local export", "type=local,dest=cache", true, client.CacheOptionsEntry{Type: "local", Attrs: map[string]string{"dest": abs}}, false},
+		{"inline", "type=inline", true, client.CacheOptionsEntry{Type: "inline", Attrs: map[string]string{}}, false},
+		{"inline import", "type=inline", false, client.CacheOptionsEntry{}, true},
+		{"mode on import", "type=registry,ref=user/app:cache,mode=max", false, client.CacheOptionsEntry{}, true},
+		{"bad mode", "type=registry,ref=user/app:cache,mode=all", true, client.CacheOptionsEntry{}, true},
+		{"missing dest", "type=local,src=cache", true, client.CacheOptionsEntry{}, true},
+		{"unsupported", "type=s3,bucket=foo", true, client.CacheOptionsEntry{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCacheSpec(tt.spec, tt.export)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseCacheSpec(%q) = %v, want error", tt.spec, got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parseCacheSpec(%q) failed: %v", tt.spec, err)
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("parseCacheSpec(%q) = %v, want %v", tt.spec, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCacheForPlatform(t *testing.T) {
+	got := cacheForPlatform([]client.CacheOptionsEntry{
+		{Type: "registry", Attrs: map[string]string{"ref": "user/app:cache", "mode": "max"}},
+		{Type: "local", Attrs: map[string]string{"dest": "/tmp/cache"}},
+		{Type: "inline", Attrs: map[string]string{}},
+	}, "linux/arm64")
+
+	expected := []client.CacheOptionsEntry{
+		{Type: "registry", Attrs: map[string]string{"ref": "index.docker.io/user/app:cache-linux-arm64", "mode": "max"}},
+		{Type: "local", Attrs: map[string]string{"dest": "/tmp/cache/linux-arm64"}},
+		{Type: "inline", Attrs: map[string]string{}},
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("cacheForPlatform() = %v, want %v", got, expected)
+	}
+}
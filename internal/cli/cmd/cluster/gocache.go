@@ -0,0 +1,315 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	storagev1beta "buf.build/gen/go/namespace/cloud/protocolbuffers/go/proto/namespace/cloud/storage/v1beta"
+	"github.com/aws/smithy-go/ptr"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"namespacelabs.dev/foundation/internal/cli/fncobra"
+	"namespacelabs.dev/foundation/internal/console"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/internal/gocacheprog"
+	"namespacelabs.dev/integrations/api/storage"
+	"namespacelabs.dev/integrations/auth"
+)
+
+const (
+	goCacheArtifactNamespace = "gocache"
+	goCacheArtifactTTL       = 7 * 24 * time.Hour
+)
+
+func NewGoCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "go",
+		Short: "Go-related activities.",
+	}
+
+	cache := &cobra.Command{Use: "cache", Short: "Go build cache related functionality."}
+	cache.AddCommand(newSetupGoCacheCmd())
+	cache.AddCommand(newGoCacheProgCmd())
+
+	cmd.AddCommand(cache)
+
+	return cmd
+}
+
+// NewGoCacheCmd returns a "go" command with setup directly underneath, for
+// use under "nsc cache go setup".
+func NewGoCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "go",
+		Short: "Go build cache related functionality.",
+	}
+
+	cmd.AddCommand(newSetupGoCacheCmd())
+
+	return cmd
+}
+
+func newSetupGoCacheCmd() *cobra.Command {
+	var output, dir, key string
+	var remote bool
+
+	return fncobra.Cmd(&cobra.Command{
+		Use:   "setup",
+		Short: "Set up a Go build cache and output the required environment variables.",
+		Long: `Set up a Go build cache and output the required environment variables.
+
+The cache is served by a GOCACHEPROG helper (requires Go 1.24 or later), which
+keeps entries in a local directory; by default, the Namespace cache volume
+if one is attached. With --remote, entries are also shared across machines
+through Namespace artifacts.
+
+The output includes:
+  GOCACHEPROG - The command which cmd/go runs to access the cache`,
+	}).WithFlags(func(flags *pflag.FlagSet) {
+		flags.StringVarP(&output, "output", "o", "plain", "One of plain or json.")
+		flags.StringVar(&dir, "cache_dir", "", "Where to keep cache entries. Defaults to the attached cache volume, if any.")
+		flags.BoolVar(&remote, "remote", false, "If set, also shares cache entries through Namespace artifacts.")
+		flags.StringVar(&key, "cache_name", "default", "A name for the remote cache; caches with different names don't share entries.")
+	}).Do(func(ctx context.Context) error {
+		if dir == "" {
+			d, err := defaultGoCacheDir()
+			if err != nil {
+				return err
+			}
+			dir = d
+		}
+
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return err
+		}
+
+		if err := os.MkdirAll(abs, 0755); err != nil {
+			return fnerrors.Newf("failed to create cache directory: %w", err)
+		}
+
+		self, err := os.Executable()
+		if err != nil {
+			return fnerrors.InternalError("failed to determine the path of nsc: %w", err)
+		}
+
+		if remote {
+			// Fail early, rather than on the first build.
+			if _, err := auth.LoadDefaults(); err != nil {
+				return err
+			}
+		}
+
+		args := []string{self, "go", "cache", "prog", "--cache_dir", abs}
+		if remote {
+			args = append(args, "--remote", "--cache_name", key)
+		}
+
+		prog, err := joinGoQuoted(args)
+		if err != nil {
+			return err
+		}
+
+		out := goCacheSetup{
+			GoCacheProg: prog,
+			CacheDir:    abs,
+		}
+
+		if remote {
+			out.Remote = path.Join(goCacheArtifactNamespace, key)
+		}
+
+		switch output {
+		case "json":
+			d := json.NewEncoder(console.Stdout(ctx))
+			d.SetIndent("", "  ")
+			if err := d.Encode(out); err != nil {
+				return fnerrors.InternalError("failed to encode output as JSON: %w", err)
+			}
+
+		default:
+			if output != "" && output != "plain" {
+				fmt.Fprintf(console.Warnings(ctx), "unsupported output %q, defaulting to plain\n", output)
+			}
+
+			fmt.Fprintf(console.Stdout(ctx), "GOCACHEPROG=%s\n", out.GoCacheProg)
+		}
+
+		return nil
+	})
+}
+
+// newGoCacheProgCmd is not a fncobra command: stdout carries the protocol, so
+// the command skips the usual setup (e.g. version checks and event streams),
+// and only ever writes diagnostics to stderr.
+func newGoCacheProgCmd() *cobra.Command {
+	var dir, key string
+	var remote, stats bool
+
+	cmd := &cobra.Command{
+		Use:    "prog",
+		Short:  "Serves the Go build cache over the GOCACHEPROG protocol, on stdin and stdout.",
+		Hidden: true,
+		Args:   cobra.NoArgs,
+
+		// Replaces the root's setup.
+		PersistentPreRunE: func(*cobra.Command, []string) error { return nil },
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return serveGoCacheProg(cmd.Context(), dir, key, remote, stats)
+		},
+	}
+
+	cmd.SetOut(os.Stderr)
+	cmd.Flags().StringVar(&dir, "cache_dir", "", "Where to keep cache entries.")
+	cmd.Flags().BoolVar(&remote, "remote", false, "If set, also shares cache entries through Namespace artifacts.")
+	cmd.Flags().StringVar(&key, "cache_name", "default", "A name for the remote cache.")
+	cmd.Flags().BoolVar(&stats, "stats", false, "If set, prints cache statistics to stderr on exit.")
+	_ = cmd.MarkFlagRequired("cache_dir")
+
+	return cmd
+}
+
+func serveGoCacheProg(ctx context.Context, dir, key string, remote, stats bool) error {
+	opts := gocacheprog.CacheOpts{Errors: os.Stderr}
+
+	if remote {
+		token, err := auth.LoadDefaults()
+		if err != nil {
+			return err
+		}
+
+		cli, err := storage.NewClient(ctx, token)
+		if err != nil {
+			return fnerrors.InvocationError("namespace api", "failed to connect: %w", err)
+		}
+		defer cli.Close()
+
+		opts.Remote = artifactGoCacheRemote{cli: cli, prefix: key}
+	}
+
+	cache, err := gocacheprog.NewCache(dir, opts)
+	if err != nil {
+		return err
+	}
+
+	if err := gocacheprog.Serve(ctx, cache, os.Stdin, os.Stdout); err != nil {
+		return err
+	}
+
+	if stats {
+		fmt.Fprintf(os.Stderr, "nsc go cache: %s\n", cache.Stats())
+	}
+
+	return nil
+}
+
+// joinGoQuoted joins args into a command line that cmd/go splits back into
+// the same arguments (see cmd/internal/quoted): arguments are quoted with
+// single or double quotes, which don't support escaping.
+func joinGoQuoted(args []string) (string, error) {
+	var quoted []string
+	for _, arg := range args {
+		switch {
+		case arg != "" && !strings.ContainsAny(arg, " \t\n\r'\""):
+			quoted = append(quoted, arg)
+		case !strings.Contains(arg, "'"):
+			quoted = append(quoted, "'"+arg+"'")
+		case !strings.Contains(arg, "\""):
+			quoted = append(quoted, "\""+arg+"\"")
+		default:
+			return "", fnerrors.BadInputError("%q contains both single and double quotes, and can't be passed to GOCACHEPROG", arg)
+		}
+	}
+
+	return strings.Join(quoted, " "), nil
+}
+
+func defaultGoCacheDir() (string, error) {
+	// Set when a cache volume is attached to the instance.
+	if p := os.Getenv("NSC_CACHE_PATH"); p != "" {
+		return filepath.Join(p, "go-build"), nil
+	}
+
+	userCache, err := os.UserCacheDir()
+	if err != nil {
+		return "", fnerrors.Newf("no cache volume attached, and no user cache directory: %w; use --cache_dir", err)
+	}
+
+	return filepath.Join(userCache, "nsc", "go-build"), nil
+}
+
+type goCacheSetup struct {
+	GoCacheProg string `json:"gocacheprog"`
+	CacheDir    string `json:"cache_dir"`
+	Remote      string `json:"remote,omitempty"`
+}
+
+// artifactGoCacheRemote stores cache entries as artifacts.
+type artifactGoCacheRemote struct {
+	cli    storage.Client
+	prefix string
+}
+
+func (r artifactGoCacheRemote) Get(ctx context.Context, key string, w io.Writer) (bool, error) {
+	res, err := r.cli.Artifacts.ResolveArtifact(ctx, &storagev1beta.ResolveArtifactRequest{
+		Path:      path.Join(r.prefix, key),
+		Namespace: goCacheArtifactNamespace,
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if res.GetDescription().GetStatus() == storagev1beta.Artifact_EXPIRED {
+		return false, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, res.SignedDownloadUrl, nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fnerrors.InvocationError("namespace api", "artifact download failed: %s", resp.Status)
+	}
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r artifactGoCacheRemote) Put(ctx context.Context, key string, size int64, body io.ReadSeeker) error {
+	_, err := storage.UploadArtifactWithOpts(ctx, r.cli, goCacheArtifactNamespace, path.Join(r.prefix, key), nopReadSeekCloser{body}, storage.UploadOpts{
+		ExpiresAt: ptr.Time(time.Now().Add(goCacheArtifactTTL)),
+	})
+	return err
+}
+
+type nopReadSeekCloser struct{ io.ReadSeeker }
+
+func (nopReadSeekCloser) Close() error { return nil }
@@ -0,0 +1,32 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package cluster
+
+import (
+	"testing"
+)
+
+func TestJoinGoQuoted(t *testing.T) {
+	for _, test := range []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"/usr/bin/nsc", "go", "cache", "prog"}, "/usr/bin/nsc go cache prog"},
+		{[]string{"/Users/me/My Tools/nsc", "--cache_dir", "/tmp/a\tb"}, "'/Users/me/My Tools/nsc' --cache_dir '/tmp/a\tb'"},
+		{[]string{"--cache_name", "it's", ""}, `--cache_name "it's" ''`},
+		{[]string{`say "hi"`}, `'say "hi"'`},
+	} {
+		got, err := joinGoQuoted(test.args)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.args, err)
+		} else if got != test.expected {
+			t.Errorf("%q: expected %s, got %s", test.args, test.expected, got)
+		}
+	}
+
+	if _, err := joinGoQuoted([]string{`it's "quoted"`}); err == nil {
+		t.Error("expected arguments with both kinds of quotes to be rejected")
+	}
+}
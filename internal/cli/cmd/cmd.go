@@ -24,8 +24,11 @@ import (
 func RegisterCommands(root *cobra.Command) {
 	root.AddCommand(NewBuildCmd())
 	root.AddCommand(NewLsCmd())
+	root.AddCommand(NewGraphCmd())
+	root.AddCommand(NewAffectedCmd())
 	root.AddCommand(NewDeployCmd())
 	root.AddCommand(NewDoctorCmd())
+	root.AddCommand(NewExplainErrorCmd())
 	root.AddCommand(NewFmtCmd())
 	root.AddCommand(NewUnprepareCmd())
 	root.AddCommand(NewDevCmd())
@@ -59,6 +62,8 @@ func RegisterCommands(root *cobra.Command) {
 	root.AddCommand(NewUpdateNSCmd())
 	root.AddCommand(NewGenerateCmd())
 	root.AddCommand(NewConfigCmd())
+	root.AddCommand(NewProtoCmd())
+	root.AddCommand(NewCreateCmd())
 	root.AddCommand(cluster.NewClusterCmd(true))
 }
 
@@ -0,0 +1,199 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+	"namespacelabs.dev/foundation/internal/cli/fncobra"
+	"namespacelabs.dev/foundation/internal/console"
+	"namespacelabs.dev/foundation/internal/console/tui"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/internal/frontend/scaffold"
+	"namespacelabs.dev/foundation/internal/parsing"
+	"namespacelabs.dev/foundation/schema"
+	"namespacelabs.dev/foundation/std/cfg"
+)
+
+func NewCreateCmd() *cobra.Command {
+	var (
+		env          cfg.Context
+		locs         fncobra.Locations
+		templateName string
+		params       []string
+		list         bool
+	)
+
+	return fncobra.Cmd(&cobra.Command{
+		Use:   "create path/to/package --template=<module>/<name>",
+		Short: "Creates a package from a scaffolding template.",
+		Long: `Creates a package from a scaffolding template.
+
+Templates are packages, in any module the workspace depends on, that contain
+a ` + scaffold.TemplateManifestFile + ` (which declares the template's parameters) and a "files"
+directory with the templated files to create. Use --list to see which
+templates a module provides.
+
+Parameters which aren't set with --param are prompted for, when running
+interactively; otherwise their defaults are used.`,
+		Args: cobra.MaximumNArgs(1),
+	}).
+		WithFlags(func(flags *pflag.FlagSet) {
+			flags.StringVar(&templateName, "template", "", "The template to use, as <module>/<name>; or, with --list, the module to list templates of.")
+			flags.StringArrayVar(&params, "param", nil, "Sets a template parameter, as name=value.")
+			flags.BoolVar(&list, "list", false, "If set, lists the templates in the module specified by --template.")
+			_ = cobra.MarkFlagRequired(flags, "template")
+		}).
+		With(
+			fncobra.HardcodeEnv(&env, "dev"),
+			fncobra.ParseLocations(&locs, &env)).
+		Do(func(ctx context.Context) error {
+			pl := parsing.NewPackageLoader(env)
+
+			loc, err := pl.Resolve(ctx, schema.PackageName(templateName))
+			if err != nil {
+				return err
+			}
+
+			fsys := loc.Module.ReadOnlyFS()
+
+			dir := loc.Rel()
+			if dir == "" {
+				dir = "."
+			}
+
+			if list {
+				sub, err := fs.Sub(fsys, dir)
+				if err != nil {
+					return err
+				}
+
+				names, err := scaffold.ListTemplates(sub)
+				if err != nil {
+					return err
+				}
+
+				out := console.Stdout(ctx)
+				if len(names) == 0 {
+					fmt.Fprintf(out, "No templates in %s.\n", templateName)
+				}
+
+				for _, name := range names {
+					full := path.Join(templateName, name)
+					t, err := scaffold.LoadTemplate(sub, name, full)
+					if err != nil {
+						fmt.Fprintf(out, "  %s (invalid: %v)\n", full, err)
+						continue
+					}
+					fmt.Fprintf(out, "  %s\t%s\n", full, t.Description)
+				}
+
+				return nil
+			}
+
+			if len(locs.Locations) != 1 {
+				return fnerrors.Newf("expected the path of the package to create")
+			}
+
+			t, err := scaffold.LoadTemplate(fsys, dir, templateName)
+			if err != nil {
+				return err
+			}
+
+			specified := map[string]string{}
+			for _, p := range params {
+				name, value, ok := strings.Cut(p, "=")
+				if !ok {
+					return fnerrors.BadInputError("invalid --param %q, expected name=value", p)
+				}
+				specified[name] = value
+			}
+
+			var prompt func(scaffold.TemplateParam) (string, error)
+			if term.IsTerminal(int(os.Stdin.Fd())) {
+				prompt = func(p scaffold.TemplateParam) (string, error) {
+					return promptTemplateParam(ctx, p)
+				}
+			}
+
+			values, err := t.ResolveParams(specified, prompt)
+			if err != nil {
+				return err
+			}
+
+			target := locs.Root.RelPackage(locs.Locations[0].RelPath)
+			if err := t.Instantiate(ctx, locs.Root.ReadWriteFS(), target, values); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(console.Stdout(ctx), "\nCreated %s/%s from %s.\n", target.ModuleName, target.RelPath, templateName)
+			return nil
+		})
+}
+
+func promptTemplateParam(ctx context.Context, p scaffold.TemplateParam) (string, error) {
+	def, _ := p.DefaultValue()
+
+	var options []string
+	switch p.Type {
+	case "bool":
+		options = []string{"true", "false"}
+
+	case "enum":
+		options = p.Options
+
+	default:
+		description := p.Description
+		if p.Pattern != "" {
+			description = strings.TrimSpace(fmt.Sprintf("%s\n\nMust match %s.", description, p.Pattern))
+		}
+
+		return tui.Ask(ctx, p.Name, description, def)
+	}
+
+	var items []templateOptionItem
+	for _, opt := range options {
+		item := templateOptionItem{value: opt}
+		if opt == def {
+			item.description = "default"
+			// The first item is selected by default.
+			items = append([]templateOptionItem{item}, items...)
+		} else {
+			items = append(items, item)
+		}
+	}
+
+	title := p.Name
+	if p.Description != "" {
+		title = fmt.Sprintf("%s: %s", p.Name, p.Description)
+	}
+
+	item, err := tui.ListSelect(ctx, title, items)
+	if err != nil {
+		return "", err
+	}
+
+	if item == nil {
+		return "", context.Canceled
+	}
+
+	return item.(templateOptionItem).value, nil
+}
+
+type templateOptionItem struct {
+	value, description string
+}
+
+func (t templateOptionItem) Title() string       { return t.value }
+func (t templateOptionItem) Description() string { return t.description }
+func (t templateOptionItem) FilterValue() string { return t.value }
@@ -11,6 +11,7 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -30,11 +31,13 @@ import (
 	"namespacelabs.dev/foundation/internal/fnfs"
 	"namespacelabs.dev/foundation/internal/fnfs/digestfs"
 	"namespacelabs.dev/foundation/internal/fnfs/memfs"
+	"namespacelabs.dev/foundation/internal/git"
 	"namespacelabs.dev/foundation/internal/planning"
 	"namespacelabs.dev/foundation/internal/planning/deploy"
 	deploystorage "namespacelabs.dev/foundation/internal/planning/deploy/storage"
 	"namespacelabs.dev/foundation/internal/planning/deploy/view"
 	"namespacelabs.dev/foundation/internal/planning/eval"
+	"namespacelabs.dev/foundation/internal/policy"
 	"namespacelabs.dev/foundation/internal/protos"
 	"namespacelabs.dev/foundation/internal/runtime"
 	"namespacelabs.dev/foundation/internal/runtime/kubernetes/kubeops"
@@ -42,6 +45,7 @@ import (
 	"namespacelabs.dev/foundation/internal/uniquestrings"
 	"namespacelabs.dev/foundation/orchestration"
 	"namespacelabs.dev/foundation/schema"
+	runtimepb "namespacelabs.dev/foundation/schema/runtime"
 	"namespacelabs.dev/foundation/std/cfg"
 	"namespacelabs.dev/foundation/std/pkggraph"
 )
@@ -105,6 +109,17 @@ func NewDeployCmd() *cobra.Command {
 				return err
 			}
 
+			targets, err := policy.TargetsFromStack(ctx, stack)
+			if err != nil {
+				return err
+			}
+
+			if err := policy.Enforce(ctx, env, targets); err != nil {
+				return err
+			}
+
+			deployOpts.policyEnforced = true
+
 			// When uploading a plan, any server and container images should be
 			// pushed to the same repository, so they're accessible by the plan.
 
@@ -135,6 +150,8 @@ func NewDeployCmd() *cobra.Command {
 				return err
 			}
 
+			deployOpts.signaturesVerified = true
+
 			deployPlan := deploy.Serialize(env.Workspace().Proto(), env.Environment(), stack.Proto(), computed, servers.Servers.Packages())
 
 			if serializePath != "" {
@@ -165,6 +182,12 @@ type deployOpts struct {
 	alsoWait     bool
 	outputPath   string
 	manualReason string
+	// Set when re-applying a previously recorded revision.
+	rollbackOf *runtime.DeploymentRevision
+	// Set when policies were already enforced against the complete stack.
+	policyEnforced bool
+	// Set when image signatures were already verified while planning.
+	signaturesVerified bool
 }
 
 type Output struct {
@@ -178,7 +201,30 @@ type Ingress struct {
 }
 
 func completeDeployment(ctx context.Context, env cfg.Context, cluster runtime.ClusterNamespace, plan *schema.DeployPlan, opts deployOpts) error {
-	if err := orchestration.Deploy(ctx, env, cluster, plan, deployReason(opts), opts.alsoWait, true); err != nil {
+	if !opts.policyEnforced {
+		if err := policy.EnforcePlan(ctx, env, plan); err != nil {
+			return err
+		}
+	}
+
+	if !opts.signaturesVerified {
+		if err := deploy.VerifyPlanImageSignatures(ctx, env, plan); err != nil {
+			return err
+		}
+	}
+
+	// Revisions are recorded while the deployment lock is held, so they're
+	// numbered in the order deployments were applied.
+	if err := orchestration.DeployExt(ctx, env, cluster, plan, deployReason(opts), opts.alsoWait, true, func(ctx context.Context) error {
+		// The deployment already succeeded, so failing to record it is not fatal.
+		if rev, err := recordRevision(ctx, env, cluster, plan, opts); err != nil {
+			fmt.Fprintf(console.Warnings(ctx), "Failed to record deployment revision: %v\n", err)
+		} else {
+			fmt.Fprintf(console.Debug(ctx), "Recorded deployment revision %d.\n", rev)
+		}
+
+		return nil
+	}); err != nil {
 		return err
 	}
 
@@ -303,6 +349,36 @@ func uploadPlanTo(ctx context.Context, target compute.Computable[oci.RepositoryW
 	return nil
 }
 
+func recordRevision(ctx context.Context, env cfg.Context, cluster runtime.ClusterNamespace, plan *schema.DeployPlan, opts deployOpts) (int64, error) {
+	digest, err := schema.DigestOf(plan)
+	if err != nil {
+		return 0, err
+	}
+
+	rev := runtime.DeploymentRevision{
+		Created:    time.Now(),
+		Author:     orchestration.DeployAuthor(),
+		Reason:     deployReason(opts),
+		PlanDigest: digest.String(),
+		Servers:    plan.FocusServer,
+	}
+
+	if opts.rollbackOf != nil {
+		rev.RollbackOf = opts.rollbackOf.Revision
+		rev.VCS = opts.rollbackOf.VCS
+	} else if status, err := git.FetchStatus(ctx, env.Workspace().LoadedFrom().AbsPath); err != nil {
+		fmt.Fprintf(console.Debug(ctx), "Failed to determine workspace revision: %v\n", err)
+	} else if status.Revision != "" {
+		rev.VCS = &runtimepb.BuildVCS{
+			Revision:    status.Revision,
+			CommitTime:  status.CommitTime.Format(time.RFC3339),
+			Uncommitted: status.Uncommitted,
+		}
+	}
+
+	return cluster.RecordDeploymentRevision(ctx, rev, plan)
+}
+
 func deployReason(opts deployOpts) string {
 	if opts.manualReason != "" {
 		return opts.manualReason
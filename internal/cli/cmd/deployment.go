@@ -6,14 +6,21 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 	"namespacelabs.dev/foundation/internal/cli/fncobra"
 	"namespacelabs.dev/foundation/internal/console"
 	"namespacelabs.dev/foundation/internal/console/tui"
 	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/internal/planning/deploy"
 	"namespacelabs.dev/foundation/internal/runtime"
+	"namespacelabs.dev/foundation/orchestration"
 	"namespacelabs.dev/foundation/std/cfg"
 )
 
@@ -83,6 +90,125 @@ func NewDeploymentCmd() *cobra.Command {
 
 	cmd.AddCommand(remove)
 	cmd.AddCommand(removeAll)
+	cmd.AddCommand(newDeploymentHistoryCmd())
+	cmd.AddCommand(newDeploymentRollbackCmd())
+	cmd.AddCommand(newDeploymentLockCmd())
+	cmd.AddCommand(newDeploymentUnlockCmd())
+
+	return cmd
+}
+
+func newDeploymentHistoryCmd() *cobra.Command {
+	var asJSON bool
+
+	cmd := fncobra.CmdWithEnv(&cobra.Command{
+		Use:   "history --env {dev|staging|prod}",
+		Short: "Lists the deployment revisions recorded in the specified environment.",
+		Args:  cobra.NoArgs,
+	}, func(ctx context.Context, env cfg.Context, args []string) error {
+		cluster, err := runtime.NamespaceFor(ctx, env)
+		if err != nil {
+			return err
+		}
+
+		revisions, err := cluster.ListDeploymentRevisions(ctx)
+		if err != nil {
+			return err
+		}
+
+		stdout := console.Stdout(ctx)
+		if asJSON {
+			enc := json.NewEncoder(stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(revisions)
+		}
+
+		if len(revisions) == 0 {
+			fmt.Fprintln(stdout, "No deployments recorded.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "REVISION\tDEPLOYED\tAUTHOR\tCOMMIT\tREASON")
+		for _, rev := range revisions {
+			commit := "-"
+			if rev.VCS != nil && rev.VCS.Revision != "" {
+				commit = rev.VCS.Revision
+				if len(commit) > 12 {
+					commit = commit[:12]
+				}
+				if rev.VCS.Uncommitted {
+					commit += "+dirty"
+				}
+			}
+
+			reason := rev.Reason
+			if rev.RollbackOf != 0 {
+				reason = strings.TrimSpace(fmt.Sprintf("(rollback to %d) %s", rev.RollbackOf, reason))
+			}
+
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", rev.Revision, rev.Created.Local().Format(time.RFC3339), orDash(rev.Author), commit, orDash(reason))
+		}
+
+		return w.Flush()
+	})
+
+	cmd.Flags().BoolVar(&asJSON, "json", false, "If set to true, outputs the revisions as JSON.")
+
+	return cmd
+}
+
+func newDeploymentRollbackCmd() *cobra.Command {
+	var (
+		opts = deployOpts{alsoWait: true}
+		to   int64
+	)
+
+	cmd := fncobra.CmdWithEnv(&cobra.Command{
+		Use:   "rollback --env {dev|staging|prod} --to <revision>",
+		Short: "Re-applies the deployment plan of a previously recorded revision.",
+		Args:  cobra.NoArgs,
+	}, func(ctx context.Context, env cfg.Context, args []string) error {
+		if deploy.RequireReason(env.Configuration()) && deployReason(opts) == "" {
+			return fnerrors.Newf("--reason is required when deploying to environment %q", env.Environment().Name)
+		}
+
+		cluster, err := runtime.NamespaceFor(ctx, env)
+		if err != nil {
+			return err
+		}
+
+		revisions, err := cluster.ListDeploymentRevisions(ctx)
+		if err != nil {
+			return err
+		}
+
+		idx := slices.IndexFunc(revisions, func(rev runtime.DeploymentRevision) bool { return rev.Revision == to })
+		if idx < 0 {
+			return fnerrors.BadInputError("revision %d not found, see `ns deployment history --env=%s`", to, env.Environment().Name)
+		}
+
+		plan, err := cluster.LoadDeploymentRevision(ctx, to)
+		if err != nil {
+			return err
+		}
+
+		if plan.GetEnvironment().GetName() != env.Environment().Name {
+			return fnerrors.BadDataError("revision %d was recorded for environment %q", to, plan.GetEnvironment().GetName())
+		}
+
+		if opts.manualReason == "" {
+			opts.manualReason = fmt.Sprintf("Rollback to revision %d.", to)
+		}
+
+		opts.rollbackOf = &revisions[idx]
+
+		return completeDeployment(ctx, env, cluster, plan, opts)
+	})
+
+	cmd.Flags().Int64Var(&to, "to", 0, "The revision to roll back to.")
+	cmd.Flags().StringVar(&opts.manualReason, "reason", "", "Why was this rollback triggered.")
+	_ = cmd.MarkFlagRequired("to")
 
 	return cmd
 }
@@ -108,3 +234,79 @@ func checkDelete(ctx context.Context, env string, single bool) error {
 
 	return nil
 }
+
+func newDeploymentLockCmd() *cobra.Command {
+	var (
+		reason   string
+		duration time.Duration
+	)
+
+	cmd := fncobra.CmdWithEnv(&cobra.Command{
+		Use:   "lock --env {dev|staging|prod} --reason <reason>",
+		Short: "Prevents deployments to the specified environment, until it is unlocked.",
+		Args:  cobra.NoArgs,
+	}, func(ctx context.Context, env cfg.Context, args []string) error {
+		if reason == "" {
+			return fnerrors.BadInputError("--reason is required")
+		}
+
+		cluster, err := runtime.NamespaceFor(ctx, env)
+		if err != nil {
+			return err
+		}
+
+		if _, _, err := cluster.LockDeployments(ctx, runtime.DeployLock{
+			Holder: orchestration.DeployAuthor(),
+			Reason: reason,
+			Manual: true,
+		}, duration); err != nil {
+			return err
+		}
+
+		if duration > 0 {
+			fmt.Fprintf(console.Stdout(ctx), "Deployments to %q are locked for %v.\n", env.Environment().Name, duration)
+		} else {
+			fmt.Fprintf(console.Stdout(ctx), "Deployments to %q are locked until `ns deployment unlock --env=%s`.\n", env.Environment().Name, env.Environment().Name)
+		}
+
+		return nil
+	})
+
+	cmd.Flags().StringVar(&reason, "reason", "", "Why are deployments being locked.")
+	cmd.Flags().DurationVar(&duration, "duration", 0, "If set, the lock expires after this long.")
+
+	return cmd
+}
+
+func newDeploymentUnlockCmd() *cobra.Command {
+	return fncobra.CmdWithEnv(&cobra.Command{
+		Use:   "unlock --env {dev|staging|prod}",
+		Short: "Removes the deployment lock of the specified environment, regardless of who holds it.",
+		Args:  cobra.NoArgs,
+	}, func(ctx context.Context, env cfg.Context, args []string) error {
+		cluster, err := runtime.NamespaceFor(ctx, env)
+		if err != nil {
+			return err
+		}
+
+		removed, err := cluster.UnlockDeployments(ctx)
+		if err != nil {
+			return err
+		}
+
+		if removed == nil {
+			fmt.Fprintf(console.Stdout(ctx), "Deployments to %q were not locked.\n", env.Environment().Name)
+		} else {
+			fmt.Fprintf(console.Stdout(ctx), "Removed lock: %v.\n", runtime.ErrDeploymentsLocked{Lock: *removed})
+		}
+
+		return nil
+	})
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,43 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"namespacelabs.dev/foundation/internal/cli/fncobra"
+	"namespacelabs.dev/foundation/internal/console"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/internal/fnerrors/catalog"
+)
+
+func NewExplainErrorCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "explain-error [code]",
+		Short: "Explains how to address an error with a stable code (e.g. NS3001). Lists all known codes if none is specified.",
+		Args:  cobra.MaximumNArgs(1),
+
+		RunE: fncobra.RunE(func(ctx context.Context, args []string) error {
+			stdout := console.Stdout(ctx)
+
+			if len(args) == 0 {
+				for _, entry := range catalog.All() {
+					fmt.Fprintf(stdout, "%s  %s\n", entry.Code, entry.Title)
+				}
+				return nil
+			}
+
+			entry, ok := catalog.Lookup(args[0])
+			if !ok {
+				return fnerrors.UsageError("Run `ns explain-error` to list the known error codes.", "%q is not a known error code", args[0])
+			}
+
+			fmt.Fprintf(stdout, "%s: %s\n\n%s\n", entry.Code, entry.Title, entry.Guide)
+			return nil
+		}),
+	}
+}
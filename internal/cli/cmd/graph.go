@@ -0,0 +1,169 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"namespacelabs.dev/foundation/internal/cli/fncobra"
+	"namespacelabs.dev/foundation/internal/console"
+	"namespacelabs.dev/foundation/internal/depgraph"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/internal/git"
+	"namespacelabs.dev/foundation/internal/parsing"
+	"namespacelabs.dev/foundation/schema"
+	"namespacelabs.dev/foundation/std/cfg"
+)
+
+func NewGraphCmd() *cobra.Command {
+	var (
+		env    cfg.Context
+		locs   fncobra.Locations
+		output string
+	)
+
+	return fncobra.Cmd(
+		&cobra.Command{
+			Use:   "graph [path/to/package | module/path/...]",
+			Short: "Exports the dependency graph between packages, servers, tests, binaries and resources.",
+		}).
+		WithFlags(func(flags *pflag.FlagSet) {
+			flags.StringVarP(&output, "output", "o", "dot", "One of dot or json.")
+		}).
+		With(
+			fncobra.HardcodeEnv(&env, "dev"),
+			fncobra.ParseLocations(&locs, &env, fncobra.ParseLocationsOpts{ReturnAllIfNoneSpecified: true})).
+		Do(func(ctx context.Context) error {
+			if !slices.Contains([]string{"dot", "json"}, output) {
+				return fnerrors.BadInputError("unsupported output %q, expected dot or json", output)
+			}
+
+			g, err := depgraph.Compute(ctx, parsing.NewPackageLoader(env), packageNames(locs))
+			if err != nil {
+				return err
+			}
+
+			stdout := console.Stdout(ctx)
+			if output == "json" {
+				enc := json.NewEncoder(stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(g)
+			}
+
+			return g.WriteDOT(stdout)
+		})
+}
+
+// Files that, when changed, may affect the whole workspace.
+var workspaceWideFiles = []string{"go.mod", "go.sum", "package.json", "pnpm-lock.yaml", "yarn.lock"}
+
+func NewAffectedCmd() *cobra.Command {
+	var (
+		env    cfg.Context
+		locs   fncobra.Locations
+		since  string
+		kind   string
+		asJSON bool
+
+		ignoreUnowned bool
+	)
+
+	return fncobra.Cmd(
+		&cobra.Command{
+			Use:   "affected --since=<git-ref> [path/to/package | module/path/...]",
+			Short: "Lists the servers and tests that are affected by the changes since the specified git revision.",
+		}).
+		WithFlags(func(flags *pflag.FlagSet) {
+			flags.StringVar(&since, "since", "", "The git revision to compare the working tree against, e.g. origin/main.")
+			flags.StringVarP(&kind, "kind", "k", "", "If set, only lists servers or tests.")
+			flags.BoolVar(&asJSON, "json", false, "If set to true, outputs the changed packages, and affected nodes as JSON.")
+			flags.BoolVar(&ignoreUnowned, "ignore_unowned", false, "By default, any changed file which doesn't belong to a package marks every server and test as affected. If set to true, only workspace-wide files (e.g. the workspace definition, or go.mod) do.")
+			_ = cobra.MarkFlagRequired(flags, "since")
+		}).
+		With(
+			fncobra.HardcodeEnv(&env, "dev"),
+			fncobra.ParseLocations(&locs, &env, fncobra.ParseLocationsOpts{ReturnAllIfNoneSpecified: true})).
+		Do(func(ctx context.Context) error {
+			kinds := map[string][]depgraph.Kind{
+				"":        {depgraph.KindServer, depgraph.KindTest},
+				"server":  {depgraph.KindServer},
+				"servers": {depgraph.KindServer},
+				"test":    {depgraph.KindTest},
+				"tests":   {depgraph.KindTest},
+			}
+
+			filter, ok := kinds[kind]
+			if !ok {
+				return fnerrors.BadInputError("bad kind %q", kind)
+			}
+
+			root := env.Workspace().LoadedFrom()
+
+			changed, err := git.ChangedFiles(ctx, root.AbsPath, since)
+			if err != nil {
+				return err
+			}
+
+			g, err := depgraph.Compute(ctx, parsing.NewPackageLoader(env), packageNames(locs))
+			if err != nil {
+				return err
+			}
+
+			impact := g.AffectedBy(changed, ignoreUnowned)
+
+			// Changes to the workspace definition, or to the module-wide
+			// dependency manifests, impact anything even if unowned files are
+			// otherwise ignored.
+			workspaceFiles := append(slices.Clone(root.DefinitionFiles), workspaceWideFiles...)
+			for _, file := range impact.Unowned {
+				if rel, err := filepath.Rel(root.AbsPath, file); err == nil && slices.Contains(workspaceFiles, rel) {
+					impact.Affected = g.Nodes
+					break
+				}
+			}
+
+			var selected []*depgraph.Node
+			for _, n := range impact.Affected {
+				if slices.Contains(filter, n.Kind) {
+					selected = append(selected, n)
+				}
+			}
+
+			stdout := console.Stdout(ctx)
+			if asJSON {
+				enc := json.NewEncoder(stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(map[string]any{
+					"changed":  impact.Changed,
+					"affected": selected,
+					"unowned":  impact.Unowned,
+				})
+			}
+
+			for _, n := range selected {
+				if kind == "" {
+					fmt.Fprintf(stdout, "%s %s\n", n.Kind, n.Ref)
+				} else {
+					fmt.Fprintf(stdout, "%s\n", n.Ref)
+				}
+			}
+
+			return nil
+		})
+}
+
+func packageNames(locs fncobra.Locations) []schema.PackageName {
+	var names []schema.PackageName
+	for _, l := range locs.Locations {
+		names = append(names, l.AsPackageName())
+	}
+	return names
+}
@@ -6,6 +6,7 @@ package cmd
 
 import (
 	"archive/tar"
+	"cmp"
 	"context"
 	"fmt"
 	"io"
@@ -24,6 +25,7 @@ import (
 	"k8s.io/utils/ptr"
 	"namespacelabs.dev/foundation/internal/artifacts"
 	"namespacelabs.dev/foundation/internal/artifacts/oci"
+	"namespacelabs.dev/foundation/internal/artifacts/registry"
 	"namespacelabs.dev/foundation/internal/build"
 	"namespacelabs.dev/foundation/internal/build/binary"
 	"namespacelabs.dev/foundation/internal/cli/fncobra"
@@ -49,6 +51,8 @@ func NewImagesCmd() *cobra.Command {
 	cmd.AddCommand(unpack())
 	cmd.AddCommand(flatten())
 	cmd.AddCommand(makeFSImage())
+	cmd.AddCommand(inspectImage())
+	cmd.AddCommand(diffImages())
 
 	return cmd
 }
@@ -213,6 +217,139 @@ func makeFSImage() *cobra.Command {
 	})
 }
 
+func inspectImage() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inspect <image-ref>",
+		Short: "Show the size of each of the image's layers, and their largest files.",
+	}
+
+	image := imageFromArgs(cmd)
+	largest := cmd.Flags().Int("largest_files", 5, "How many of the largest files to show for each layer.")
+
+	return fncobra.With(cmd, func(ctx context.Context) error {
+		layers, err := oci.InspectLayers(*image)
+		if err != nil {
+			return err
+		}
+
+		out := console.Stdout(ctx)
+
+		var total, uncompressed int64
+		for k, layer := range layers {
+			total += layer.Size
+			uncompressed += layer.UncompressedSize
+
+			fmt.Fprintf(out, "Layer %d: %s\n", k, layer.Digest)
+			fmt.Fprintf(out, "  %s compressed, %s uncompressed, %d entries\n", humanize.Bytes(uint64(layer.Size)), humanize.Bytes(uint64(layer.UncompressedSize)), len(layer.Files))
+			if layer.CreatedBy != "" {
+				fmt.Fprintf(out, "  Created by: %s\n", layer.CreatedBy)
+			}
+
+			files := slices.Clone(layer.Files)
+			slices.SortStableFunc(files, func(a, b oci.FileEntry) int {
+				return cmp.Compare(b.Size, a.Size)
+			})
+
+			for _, f := range files[:min(*largest, len(files))] {
+				if f.Size == 0 {
+					break
+				}
+				fmt.Fprintf(out, "    %10s  %s\n", humanize.Bytes(uint64(f.Size)), f.Path)
+			}
+		}
+
+		fmt.Fprintf(out, "\nTotal: %d layers, %s compressed, %s uncompressed.\n", len(layers), humanize.Bytes(uint64(total)), humanize.Bytes(uint64(uncompressed)))
+		return nil
+	})
+}
+
+func diffImages() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <image-ref-a> <image-ref-b>",
+		Short: "Compare the configuration and the per-layer contents of two images.",
+	}
+
+	var a, b oci.Image
+	imagesFromArgs(cmd, &a, &b)
+
+	return fncobra.With(cmd, func(ctx context.Context) error {
+		configA, err := a.ConfigFile()
+		if err != nil {
+			return err
+		}
+
+		configB, err := b.ConfigFile()
+		if err != nil {
+			return err
+		}
+
+		layersA, err := oci.InspectLayers(a)
+		if err != nil {
+			return err
+		}
+
+		layersB, err := oci.InspectLayers(b)
+		if err != nil {
+			return err
+		}
+
+		out := console.Stdout(ctx)
+
+		configChanges := oci.DiffConfigs(configA.Config, configB.Config)
+		layerDiffs := oci.DiffLayers(layersA, layersB)
+
+		if len(configChanges) == 0 && len(layerDiffs) == 0 {
+			fmt.Fprintln(out, "Images are identical.")
+			return nil
+		}
+
+		if len(configChanges) > 0 {
+			fmt.Fprintln(out, "Config:")
+			for _, c := range configChanges {
+				switch c.Kind {
+				case oci.Added:
+					fmt.Fprintf(out, "  + %s: %s\n", c.Field, c.After)
+				case oci.Removed:
+					fmt.Fprintf(out, "  - %s: %s\n", c.Field, c.Before)
+				default:
+					fmt.Fprintf(out, "  ~ %s: %s -> %s\n", c.Field, c.Before, c.After)
+				}
+			}
+			fmt.Fprintln(out)
+		}
+
+		for _, d := range layerDiffs {
+			switch {
+			case d.Before == nil:
+				fmt.Fprintf(out, "Layer %d: added %s (%s)\n", d.AfterIndex, d.After.Digest, humanize.Bytes(uint64(d.After.UncompressedSize)))
+			case d.After == nil:
+				fmt.Fprintf(out, "Layer %d: removed %s (%s)\n", d.BeforeIndex, d.Before.Digest, humanize.Bytes(uint64(d.Before.UncompressedSize)))
+			default:
+				fmt.Fprintf(out, "Layer %d -> %d: %s (%s) -> %s (%s)\n", d.BeforeIndex, d.AfterIndex,
+					d.Before.Digest, humanize.Bytes(uint64(d.Before.UncompressedSize)),
+					d.After.Digest, humanize.Bytes(uint64(d.After.UncompressedSize)))
+			}
+
+			for _, f := range d.Files {
+				switch {
+				case f.Kind == oci.Added && f.After == nil:
+					fmt.Fprintf(out, "  + %s (no longer removed)\n", f.Path)
+				case f.Kind == oci.Added:
+					fmt.Fprintf(out, "  + %s (%s)\n", f.Path, humanize.Bytes(uint64(f.After.Size)))
+				case f.Kind == oci.Removed && f.After != nil:
+					fmt.Fprintf(out, "  - %s (whiteout)\n", f.Path)
+				case f.Kind == oci.Removed:
+					fmt.Fprintf(out, "  - %s (%s)\n", f.Path, humanize.Bytes(uint64(f.Before.Size)))
+				default:
+					fmt.Fprintf(out, "  ~ %s (%s -> %s)\n", f.Path, humanize.Bytes(uint64(f.Before.Size)), humanize.Bytes(uint64(f.After.Size)))
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
 func resolveImage(ctx context.Context, image string, env cfg.Context, pl *parsing.PackageLoader) (oci.Image, error) {
 	if strings.HasPrefix(image, "tar:") {
 		return tarball.ImageFromPath(strings.TrimPrefix(image, "tar:"), nil)
@@ -252,6 +389,25 @@ func resolveImage(ctx context.Context, image string, env cfg.Context, pl *parsin
 		return resolvable.Image()
 	}
 
+	if strings.HasPrefix(image, "built:") {
+		// Images produced by `ns build` are fetched with the credentials of
+		// the environment's registry.
+		imageID, err := oci.ParseImageID(strings.TrimPrefix(image, "built:"))
+		if err != nil {
+			return nil, err
+		}
+
+		reg, err := registry.GetRegistry(ctx, env)
+		if err != nil {
+			return nil, err
+		}
+
+		hostPlatform := platform.RuntimePlatform()
+		hostPlatform.OS = "linux"
+
+		return compute.GetValue(ctx, oci.ImageP(imageID.ImageRef(), &hostPlatform, reg.Access()))
+	}
+
 	insecure := false
 	if strings.HasPrefix(image, "insecure:") {
 		insecure = true
@@ -273,24 +429,36 @@ func matchAny(files []string, path string) bool {
 }
 
 func imageFromArgs(cmd *cobra.Command) *oci.Image {
+	targetImage := new(oci.Image)
+	imagesFromArgs(cmd, targetImage)
+	return targetImage
+}
+
+// imagesFromArgs resolves each of the command's arguments, which are image
+// references, into the respective target.
+func imagesFromArgs(cmd *cobra.Command, targets ...*oci.Image) {
 	env := fncobra.EnvFromValue(cmd, ptr.To("dev"))
 
-	targetImage := new(oci.Image)
 	cmd.Flags().Var(build.BuildPlatformsVar{}, "build_platforms", "Which platforms to build the binary for.")
 
 	fncobra.PushParse(cmd, func(ctx context.Context, args []string) error {
-		if len(args) != 1 {
-			return fnerrors.Newf("expected a single argument, with an image reference")
+		if len(args) != len(targets) {
+			if len(targets) == 1 {
+				return fnerrors.Newf("expected a single argument, with an image reference")
+			}
+			return fnerrors.Newf("expected %d arguments, with image references", len(targets))
 		}
 
-		image, err := resolveImage(ctx, args[0], *env, parsing.NewPackageLoader(*env))
-		if err != nil {
-			return err
+		pl := parsing.NewPackageLoader(*env)
+		for k, arg := range args {
+			image, err := resolveImage(ctx, arg, *env, pl)
+			if err != nil {
+				return err
+			}
+
+			*targets[k] = image
 		}
 
-		*targetImage = image
 		return nil
 	})
-
-	return targetImage
 }
@@ -8,10 +8,13 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"os"
+	"sort"
 	"strings"
 
 	"filippo.io/age"
 	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
 	"namespacelabs.dev/foundation/internal/cli/fncobra"
 	"namespacelabs.dev/foundation/internal/console"
 	"namespacelabs.dev/foundation/internal/console/tui"
@@ -91,6 +94,88 @@ func NewKeysCmd() *cobra.Command {
 	cmd.AddCommand(list)
 	cmd.AddCommand(generate)
 	cmd.AddCommand(importCmd)
+	cmd.AddCommand(newSigningKeysCmd())
+
+	return cmd
+}
+
+func newSigningKeysCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "signing",
+		Short: "Manage keys used to sign images, and to verify image signatures.",
+	}
+
+	generate := &cobra.Command{
+		Use:   "generate [name]",
+		Short: "Generate a new image signing key.",
+		Args:  cobra.MaximumNArgs(1),
+
+		RunE: fncobra.RunE(func(ctx context.Context, args []string) error {
+			name := "default"
+			if len(args) > 0 {
+				name = args[0]
+			}
+
+			pk, err := keys.GenerateSigningKey(ctx, name)
+			if err != nil {
+				return err
+			}
+
+			pub, err := keys.MarshalSigningPublicKey(&pk.PublicKey)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(console.Stdout(ctx), "Generated signing key %q. Its public key (e.g. for `cosign verify --key`) is:\n\n%s", name, pub)
+			return nil
+		}),
+	}
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "Lists the public keys that image signatures are verified against.",
+		Args:  cobra.NoArgs,
+
+		RunE: fncobra.RunE(func(ctx context.Context, args []string) error {
+			trusted, err := keys.TrustedSigningKeys(ctx)
+			if err != nil {
+				return err
+			}
+
+			names := maps.Keys(trusted)
+			sort.Strings(names)
+
+			for _, name := range names {
+				fmt.Fprintf(console.Stdout(ctx), "%s\n", name)
+			}
+
+			return nil
+		}),
+	}
+
+	trust := &cobra.Command{
+		Use:   "trust <name> <path/to/key.pub>",
+		Short: "Adds a public key (e.g. of a CI signer) to the set of keys that image signatures are verified against.",
+		Args:  cobra.ExactArgs(2),
+
+		RunE: fncobra.RunE(func(ctx context.Context, args []string) error {
+			contents, err := os.ReadFile(args[1])
+			if err != nil {
+				return err
+			}
+
+			if err := keys.TrustSigningKey(ctx, args[0], contents); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(console.Stdout(ctx), "Now trusting signing key %q.\n", args[0])
+			return nil
+		}),
+	}
+
+	cmd.AddCommand(generate)
+	cmd.AddCommand(list)
+	cmd.AddCommand(trust)
 
 	return cmd
 }
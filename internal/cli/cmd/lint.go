@@ -9,38 +9,71 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"namespacelabs.dev/foundation/internal/cli/fncobra"
 	"namespacelabs.dev/foundation/internal/console"
 	"namespacelabs.dev/foundation/internal/console/colors"
 	"namespacelabs.dev/foundation/internal/fnerrors/format"
 	"namespacelabs.dev/foundation/internal/parsing"
+	"namespacelabs.dev/foundation/internal/policy"
 	"namespacelabs.dev/foundation/std/cfg"
+	"namespacelabs.dev/foundation/std/pkggraph"
 )
 
 func NewLintCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "lint [path/to/package]...",
-		Short: "Verify if package definitions are correct.",
+		Short: "Verify if package definitions are correct, and comply with the environment's policies.",
 	}
 
 	var (
-		env  cfg.Context
-		locs fncobra.Locations
+		env           cfg.Context
+		locs          fncobra.Locations
+		protosAgainst string
 	)
 
 	return fncobra.Cmd(cmd).
+		WithFlags(func(flags *pflag.FlagSet) {
+			flags.StringVar(&protosAgainst, "check_breaking_against", "", "If set, also checks exported gRPC services for breaking changes against this git revision; see `ns proto check-breaking`.")
+		}).
 		With(
 			fncobra.ParseEnv(&env),
 			fncobra.ParseLocations(&locs, &env, fncobra.ParseLocationsOpts{ReturnAllIfNoneSpecified: true})).
 		Do(func(ctx context.Context) error {
+			pl := parsing.NewPackageLoader(env)
+
+			var targets []policy.Target
+			var pkgs []*pkggraph.Package
 			for _, loc := range locs.Locations {
 				fmt.Fprintln(console.Stderr(ctx), "Checking", loc.AsPackageName())
-				if _, err := parsing.LoadPackageByName(ctx, env, loc.AsPackageName()); err != nil {
+				pkg, err := pl.LoadByName(ctx, loc.AsPackageName())
+				if err != nil {
 					fmt.Fprintln(console.Stderr(ctx))
 					format.Format(console.Stderr(ctx), err, format.WithStyle(colors.WithColors))
 					fmt.Fprintln(console.Stderr(ctx))
+					continue
 				}
+
+				if pkg.Server != nil {
+					target, err := policy.LoadTarget(ctx, pl, pkg.Location, pkg.Server, pkg.Server.GetSelf())
+					if err != nil {
+						return err
+					}
+
+					targets = append(targets, target)
+				}
+
+				pkgs = append(pkgs, pkg)
 			}
-			return nil
+
+			if protosAgainst != "" {
+				if err := checkBreakingProtos(ctx, pl, pkgs, protosAgainst); err != nil {
+					return err
+				}
+			}
+
+			// Servers are checked in isolation, i.e. without the configuration
+			// their dependencies contribute. `ns deploy` checks the complete stack.
+			return policy.Enforce(ctx, env, targets)
 		})
 }
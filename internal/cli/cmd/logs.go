@@ -6,6 +6,7 @@ package cmd
 
 import (
 	"context"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -16,6 +17,8 @@ import (
 	"namespacelabs.dev/foundation/internal/fnerrors"
 	"namespacelabs.dev/foundation/internal/logs/logtail"
 	"namespacelabs.dev/foundation/internal/observers"
+	"namespacelabs.dev/foundation/internal/planning"
+	"namespacelabs.dev/foundation/internal/planning/eval"
 	"namespacelabs.dev/foundation/internal/runtime"
 	"namespacelabs.dev/foundation/internal/runtime/kubernetes"
 	"namespacelabs.dev/foundation/schema"
@@ -28,43 +31,93 @@ func NewLogsCmd() *cobra.Command {
 		locs    fncobra.Locations
 		servers planningargs.Servers
 
-		dump bool
+		dump             bool
+		stack            bool
+		since, until     string
+		include, exclude []string
+		observe          runtime.ObserveOpts
 	)
 
 	return fncobra.
 		Cmd(&cobra.Command{
-			Use:   "logs <path/to/server>",
-			Short: "Stream logs of the specified server.",
+			Use:   "logs <path/to/server>...",
+			Short: "Stream logs of the specified servers.",
+			Long: "Stream logs of the specified servers.\n\n" +
+				"When more than one server is specified, their logs are interleaved by timestamp, and\n" +
+				"each line is prefixed with the name of the server it originates from.",
 		}).
 		WithFlags(func(flags *pflag.FlagSet) {
 			flags.BoolVar(&kubernetes.ObserveInitContainerLogs, "observe_init_containers", kubernetes.ObserveInitContainerLogs, "Kubernetes-specific flag to also fetch logs from init containers.")
-			flags.BoolVar(&dump, "dump", dump, "If set, dumps all available logs, rather than tailing the specified server.")
+			flags.BoolVar(&dump, "dump", dump, "If set, dumps all available logs, rather than tailing the specified servers.")
+			flags.BoolVar(&stack, "stack", stack, "If set, also includes logs of every server in the stack of the specified servers.")
+			flags.StringVar(&since, "since", since, "Only show logs newer than this; either a duration (e.g. 10m), or an RFC3339 timestamp.")
+			flags.StringVar(&until, "until", until, "Only show logs older than this; either a duration (e.g. 10m), or an RFC3339 timestamp.")
+			flags.StringArrayVar(&include, "include", include, "Only show lines which match one of these regular expressions.")
+			flags.StringArrayVar(&exclude, "exclude", exclude, "Don't show lines which match any of these regular expressions.")
+			flags.BoolVar(&observe.Sidecars, "sidecars", observe.Sidecars, "If set, also shows logs of each server's sidecars.")
+			flags.StringSliceVar(&observe.ContainerNames, "container", observe.ContainerNames, "If set, only shows logs of the containers with these names.")
 		}).
 		With(
 			fncobra.ParseEnv(&env),
-			fncobra.ParseLocations(&locs, &env, fncobra.ParseLocationsOpts{RequireSingle: true}),
+			fncobra.ParseLocations(&locs, &env),
 			planningargs.ParseServers(&servers, &env, &locs)).
 		Do(func(ctx context.Context) error {
-			server := servers.Servers[0]
+			if len(servers.Servers) == 0 {
+				return fnerrors.Newf("at least one server is required")
+			}
+
+			now := time.Now()
+			fetch := runtime.FetchLogsOpts{Follow: !dump}
+
+			var err error
+			if fetch.Since, err = runtime.ParseLogTime(since, now); err != nil {
+				return err
+			}
+
+			if fetch.Until, err = runtime.ParseLogTime(until, now); err != nil {
+				return err
+			}
+
+			if fetch.Include, err = runtime.CompileLogPatterns(include); err != nil {
+				return err
+			}
+
+			if fetch.Exclude, err = runtime.CompileLogPatterns(exclude); err != nil {
+				return err
+			}
 
-			if dump {
-				rt, err := runtime.NamespaceFor(ctx, env)
+			selected := servers.Servers
+			if stack {
+				p, err := planning.NewPlanner(ctx, env)
 				if err != nil {
 					return err
 				}
 
-				containers, err := rt.ResolveContainers(ctx, server.Proto())
+				computed, err := planning.ComputeStack(ctx, servers.Servers, planning.ProvisionOpts{
+					Planner:   p.Runtime,
+					PortRange: eval.DefaultPortRange(),
+				})
 				if err != nil {
 					return err
 				}
 
-				if len(containers) != 1 {
-					return fnerrors.Newf("expected a single container, got %d", len(containers))
+				selected = nil
+				for _, srv := range computed.Servers {
+					selected = append(selected, srv.Server)
 				}
+			}
+
+			observe.InitContainers = kubernetes.ObserveInitContainerLogs
 
-				return rt.Cluster().FetchLogsTo(ctx, containers[0], runtime.FetchLogsOpts{}, runtime.WriteToWriter(console.Stdout(ctx)))
+			aggregate := len(selected) > 1 || !fetch.Since.IsZero() || !fetch.Until.IsZero() ||
+				len(fetch.Include) > 0 || len(fetch.Exclude) > 0 ||
+				observe.Sidecars || len(observe.ContainerNames) > 0
+
+			if dump || aggregate {
+				return fetchLogs(ctx, env, selected, runtime.ServerLogsOpts{Observe: observe, Fetch: fetch})
 			}
 
+			server := selected[0]
 			event := &observers.StackUpdateEvent{
 				Env: env.Environment(),
 				Stack: &schema.Stack{
@@ -86,3 +139,22 @@ func NewLogsCmd() *cobra.Command {
 			})
 		})
 }
+
+func fetchLogs(ctx context.Context, env cfg.Context, servers planning.Servers, opts runtime.ServerLogsOpts) error {
+	if opts.Fetch.Follow && !opts.Fetch.Until.IsZero() && time.Now().After(opts.Fetch.Until) {
+		return fnerrors.BadInputError("--until is in the past; use --dump to fetch past logs")
+	}
+
+	rt, err := runtime.NamespaceFor(ctx, env)
+	if err != nil {
+		return err
+	}
+
+	var deployables []runtime.Deployable
+	for _, server := range servers {
+		deployables = append(deployables, server.Proto())
+	}
+
+	out := logtail.NewPrefixedWriter(ctx, console.Stdout(ctx))
+	return rt.FetchServerLogs(ctx, deployables, opts, out.Write)
+}
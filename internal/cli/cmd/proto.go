@@ -0,0 +1,193 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"namespacelabs.dev/foundation/internal/cli/fncobra"
+	"namespacelabs.dev/foundation/internal/codegen/protos"
+	"namespacelabs.dev/foundation/internal/console"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/internal/fnfs/memfs"
+	"namespacelabs.dev/foundation/internal/fnfs/tarfs"
+	"namespacelabs.dev/foundation/internal/git"
+	"namespacelabs.dev/foundation/internal/parsing"
+	"namespacelabs.dev/foundation/std/cfg"
+	"namespacelabs.dev/foundation/std/pkggraph"
+)
+
+func NewProtoCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "proto",
+		Short: "Protobuf-related activities.",
+	}
+
+	cmd.AddCommand(newProtoCheckBreakingCmd())
+
+	return cmd
+}
+
+func newProtoCheckBreakingCmd() *cobra.Command {
+	var (
+		env     cfg.Context
+		locs    fncobra.Locations
+		against string
+	)
+
+	return fncobra.Cmd(&cobra.Command{
+		Use:   "check-breaking [path/to/package]...",
+		Short: "Checks exported gRPC services for wire-incompatible changes, against a git revision.",
+		Long: `Checks exported gRPC services for wire-incompatible changes, against a git revision.
+
+Removed methods and fields, incompatible type or cardinality changes, reused
+reserved numbers, and renamed packages are reported. To accept a breaking
+change, add a "` + protos.AllowBreakingAnnotation + `" comment to the changed element, or to
+the service, method or message that contains it.`,
+	}).
+		WithFlags(func(flags *pflag.FlagSet) {
+			flags.StringVar(&against, "against", "", "The git revision (e.g. origin/main) to compare with.")
+			_ = cobra.MarkFlagRequired(flags, "against")
+		}).
+		With(
+			fncobra.ParseEnv(&env),
+			fncobra.ParseLocations(&locs, &env, fncobra.ParseLocationsOpts{ReturnAllIfNoneSpecified: true})).
+		Do(func(ctx context.Context) error {
+			pl := parsing.NewPackageLoader(env)
+
+			var pkgs []*pkggraph.Package
+			for _, loc := range locs.Locations {
+				pkg, err := pl.LoadByName(ctx, loc.AsPackageName())
+				if err != nil {
+					return err
+				}
+				pkgs = append(pkgs, pkg)
+			}
+
+			return checkBreakingProtos(ctx, pl, pkgs, against)
+		})
+}
+
+// checkBreakingProtos compares the services exported by each package with
+// their definitions at the specified git revision. Services which didn't
+// exist then are skipped.
+func checkBreakingProtos(ctx context.Context, pl *parsing.PackageLoader, pkgs []*pkggraph.Package, against string) error {
+	if against == "" || strings.HasPrefix(against, "-") {
+		return fnerrors.BadInputError("%q: invalid revision", against)
+	}
+
+	baseline := protoBaseline{ref: against, modules: map[string]fs.FS{}}
+
+	var count int
+	for _, pkg := range pkgs {
+		for _, export := range pkg.Node().GetExportService() {
+			current, ok := pkg.Services[export.ProtoTypename]
+			if !ok {
+				continue
+			}
+
+			previous, err := baseline.parse(ctx, pl, pkg.Location, export.Proto)
+			if err != nil {
+				return fnerrors.AttachLocation(pkg.Location, err)
+			}
+
+			if previous == nil {
+				fmt.Fprintf(console.Debug(ctx), "proto: %s: not present at %s, skipping\n", export.ProtoTypename, against)
+				continue
+			}
+
+			changes := protos.CheckBreaking(previous, current, export.ProtoTypename)
+			if len(changes) == 0 {
+				continue
+			}
+
+			fmt.Fprintf(console.Stderr(ctx), "%s: %s has breaking changes (against %s):\n", pkg.Location.ErrorLocation(), export.ProtoTypename, against)
+			for _, c := range changes {
+				fmt.Fprintf(console.Stderr(ctx), "  %s\n", c)
+			}
+
+			count += len(changes)
+		}
+	}
+
+	if count > 0 {
+		return fnerrors.BadInputError("found %d breaking proto %s; annotate them with %q if they're intended",
+			count, plural(count, "change", "changes"), protos.AllowBreakingAnnotation)
+	}
+
+	return nil
+}
+
+type protoBaseline struct {
+	ref     string
+	modules map[string]fs.FS // Key: module's absolute path.
+}
+
+// parse parses the specified sources of a package, as of the baseline
+// revision. Returns nil if any of the sources doesn't exist at that revision.
+func (b protoBaseline) parse(ctx context.Context, pl *parsing.PackageLoader, loc pkggraph.Location, sources []string) (*protos.FileDescriptorSetAndDeps, error) {
+	fsys, err := b.moduleFS(ctx, loc.Module)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, src := range sources {
+		if _, err := fs.Stat(fsys, loc.Rel(src)); err != nil {
+			return nil, nil
+		}
+	}
+
+	parseOpts, err := parsing.MakeProtoParseOpts(ctx, pl, loc.Module.Workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := parseOpts.ParseAtLocation(fsys, loc, sources)
+	if err != nil {
+		return nil, fnerrors.Newf("failed to parse protos at %s: %w", b.ref, err)
+	}
+
+	return parsed, nil
+}
+
+// moduleFS returns the protos of the module at the baseline revision.
+func (b protoBaseline) moduleFS(ctx context.Context, mod *pkggraph.Module) (fs.FS, error) {
+	if fsys, ok := b.modules[mod.Abs()]; ok {
+		return fsys, nil
+	}
+
+	prefix, _, err := git.RunGit(ctx, mod.Abs(), "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, fnerrors.InvocationError("git", "%s: not in a git repository: %w", mod.ModuleName(), err)
+	}
+
+	tarball, stderr, err := git.RunGit(ctx, mod.Abs(), "archive", "--format=tar", "--end-of-options", b.ref+":"+strings.TrimSpace(string(prefix)))
+	if err != nil {
+		return nil, fnerrors.InvocationError("git", "%s: failed to read %s: %s", mod.ModuleName(), b.ref, bytes.TrimSpace(stderr))
+	}
+
+	var inmem memfs.FS
+	if _, err := tarfs.ReadFilesInto(&inmem, func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(tarball)), nil
+	}, protoSources{}); err != nil {
+		return nil, err
+	}
+
+	b.modules[mod.Abs()] = &inmem
+	return &inmem, nil
+}
+
+type protoSources struct{}
+
+func (protoSources) Match(p string) (string, bool) {
+	return p, strings.HasSuffix(p, ".proto") || p == "buf.work.yaml"
+}
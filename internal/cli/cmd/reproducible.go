@@ -0,0 +1,225 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"namespacelabs.dev/foundation/internal/artifacts/oci"
+	"namespacelabs.dev/foundation/internal/build/buildkit"
+	"namespacelabs.dev/foundation/internal/compute"
+	"namespacelabs.dev/foundation/internal/compute/cache"
+	"namespacelabs.dev/foundation/internal/console"
+	"namespacelabs.dev/foundation/internal/console/colors"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/internal/planning"
+	"namespacelabs.dev/foundation/internal/planning/deploy"
+	"namespacelabs.dev/foundation/internal/workspace/dirs"
+	"namespacelabs.dev/foundation/std/tasks"
+)
+
+type builtImage struct {
+	Digest string
+	// Layer digests, by platform.
+	Layers map[string][]v1.Hash
+}
+
+// checkReproducible builds the servers twice, each time with empty caches,
+// and checks that both builds produced the same images.
+func checkReproducible(ctx context.Context, p planning.Planner, servers planning.Servers) error {
+	first, err := buildIsolated(ctx, p, servers, 1)
+	if err != nil {
+		return err
+	}
+
+	second, err := buildIsolated(ctx, p, servers, 2)
+	if err != nil {
+		return err
+	}
+
+	out := console.TypedOutput(ctx, "build", console.CatOutputUs)
+	style := colors.Ctx(ctx)
+
+	keys := maps.Keys(first)
+	slices.Sort(keys)
+
+	var mismatches int
+	for _, key := range keys {
+		a, b := first[key], second[key]
+
+		if a.Digest == b.Digest {
+			fmt.Fprintf(out, "  %s %s\n", key, style.LessRelevant.Apply("reproducible "+a.Digest))
+			continue
+		}
+
+		mismatches++
+		fmt.Fprintf(out, "  %s %s\n", key, style.Header.Apply("NOT reproducible"))
+		fmt.Fprintf(out, "    first:  %s\n    second: %s\n", a.Digest, b.Digest)
+
+		for _, diff := range diffLayers(a.Layers, b.Layers) {
+			fmt.Fprintf(out, "    %s\n", diff)
+		}
+	}
+
+	if mismatches > 0 {
+		return fnerrors.Newf("%d of %d images were not reproducible", mismatches, len(keys))
+	}
+
+	fmt.Fprintf(out, "\nAll %d images were reproducible.\n", len(keys))
+	return nil
+}
+
+func buildIsolated(ctx context.Context, p planning.Planner, servers planning.Servers, run int) (map[string]builtImage, error) {
+	dir, err := dirs.CreateUserTempDir("build", "reproducible")
+	if err != nil {
+		return nil, err
+	}
+
+	defer os.RemoveAll(dir)
+
+	c, err := cache.LocalAt(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	built := map[string]builtImage{}
+	if err := tasks.Action("build.verify-reproducible").Arg("run", run).Run(ctx, func(ctx context.Context) error {
+		return compute.DoWithCache(buildkit.WithoutCache(ctx), c, func(ctx context.Context) error {
+			_, images, err := deploy.ComputeStackAndImages(ctx, p, servers)
+			if err != nil {
+				return err
+			}
+
+			res, err := compute.GetValue(ctx, compute.Collect(tasks.Action("build.all-images"), images...))
+			if err != nil {
+				return err
+			}
+
+			for _, r := range res {
+				resolved := r.Value
+				key := resolved.PackageRef.Canonical()
+
+				if resolved.BinaryImage != nil && !resolved.PrebuiltBinary {
+					image, err := compute.GetValue(ctx, resolved.BinaryImage)
+					if err != nil {
+						return err
+					}
+
+					layers, err := layersByPlatform(image)
+					if err != nil {
+						return err
+					}
+
+					built[key] = builtImage{Digest: resolved.Binary.Digest, Layers: layers}
+				}
+
+				if resolved.Config != nil {
+					built[key+" (config)"] = builtImage{Digest: resolved.Config.Digest}
+				}
+			}
+
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	return built, nil
+}
+
+func layersByPlatform(image oci.ResolvableImage) (map[string][]v1.Hash, error) {
+	layers := map[string][]v1.Hash{}
+
+	if idx, err := image.ImageIndex(); err == nil {
+		manifest, err := idx.IndexManifest()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range manifest.Manifests {
+			img, err := idx.Image(m.Digest)
+			if err != nil {
+				return nil, err
+			}
+
+			key := m.Digest.String()
+			if m.Platform != nil {
+				key = m.Platform.String()
+			}
+
+			if layers[key], err = layerDigests(img); err != nil {
+				return nil, err
+			}
+		}
+
+		return layers, nil
+	}
+
+	img, err := image.Image()
+	if err != nil {
+		return nil, err
+	}
+
+	if layers[""], err = layerDigests(img); err != nil {
+		return nil, err
+	}
+
+	return layers, nil
+}
+
+func layerDigests(img oci.Image) ([]v1.Hash, error) {
+	layers, err := img.Layers()
+	if err != nil {
+		return nil, err
+	}
+
+	var digests []v1.Hash
+	for _, layer := range layers {
+		d, err := layer.Digest()
+		if err != nil {
+			return nil, err
+		}
+		digests = append(digests, d)
+	}
+
+	return digests, nil
+}
+
+func diffLayers(a, b map[string][]v1.Hash) []string {
+	platforms := maps.Keys(a)
+	for p := range b {
+		if _, ok := a[p]; !ok {
+			platforms = append(platforms, p)
+		}
+	}
+	slices.Sort(platforms)
+
+	var diffs []string
+	for _, p := range platforms {
+		prefix := ""
+		if p != "" {
+			prefix = p + ": "
+		}
+
+		la, lb := a[p], b[p]
+		if len(la) != len(lb) {
+			diffs = append(diffs, fmt.Sprintf("%s%d layers vs %d layers", prefix, len(la), len(lb)))
+			continue
+		}
+
+		for k := range la {
+			if la[k] != lb[k] {
+				diffs = append(diffs, fmt.Sprintf("%slayer %d differs: %s vs %s", prefix, k, la[k], lb[k]))
+			}
+		}
+	}
+
+	return diffs
+}
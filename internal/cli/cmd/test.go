@@ -24,6 +24,7 @@ import (
 	"namespacelabs.dev/foundation/internal/compute"
 	"namespacelabs.dev/foundation/internal/console"
 	"namespacelabs.dev/foundation/internal/console/colors"
+	"namespacelabs.dev/foundation/internal/eventstream"
 	"namespacelabs.dev/foundation/internal/executor"
 	"namespacelabs.dev/foundation/internal/fnerrors"
 	enverr "namespacelabs.dev/foundation/internal/fnerrors/env"
@@ -194,6 +195,8 @@ func NewTestCmd() *cobra.Command {
 						completed = append(completed, run)
 						mu.Unlock()
 
+						eventstream.TestResult(ctx, run.TestSummary)
+
 						printResult(out, style, testRef, run, false)
 					}
 
@@ -223,6 +226,8 @@ func NewTestCmd() *cobra.Command {
 					TestSummary: res.Value.TestBundleSummary,
 					TestResults: res.Value.Bundle,
 				})
+
+				eventstream.TestResult(ctx, res.Value.TestBundleSummary)
 			}
 		}
 
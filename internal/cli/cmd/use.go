@@ -13,15 +13,21 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"namespacelabs.dev/foundation/framework/resources"
 	"namespacelabs.dev/foundation/internal/artifacts/oci"
 	"namespacelabs.dev/foundation/internal/cli/fncobra"
+	"namespacelabs.dev/foundation/internal/cli/fncobra/planningargs"
 	"namespacelabs.dev/foundation/internal/compute"
 	"namespacelabs.dev/foundation/internal/console"
 	"namespacelabs.dev/foundation/internal/console/tui"
 	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/internal/planning"
+	"namespacelabs.dev/foundation/internal/planning/eval"
 	"namespacelabs.dev/foundation/internal/runtime"
 	"namespacelabs.dev/foundation/internal/uniquestrings"
+	mysqlclass "namespacelabs.dev/foundation/library/database/mysql"
 	"namespacelabs.dev/foundation/schema"
+	"namespacelabs.dev/foundation/std/cfg"
 	"namespacelabs.dev/foundation/universe/db/postgres"
 	"namespacelabs.dev/go-ids"
 )
@@ -35,6 +41,7 @@ func NewUseCmd() *cobra.Command {
 	cmd.AddCommand(newPsql())
 	cmd.AddCommand(newPgdump())
 	cmd.AddCommand(newPgrestore())
+	cmd.AddCommand(newMysql())
 
 	return cmd
 }
@@ -180,6 +187,187 @@ func newPgrestore() *cobra.Command {
 		})
 }
 
+// TODO: this, and other commands, should be dynamically discovered. See #414.
+func newMysql() *cobra.Command {
+	var (
+		env      cfg.Context
+		locs     fncobra.Locations
+		servers  planningargs.Servers
+		database string
+	)
+
+	return fncobra.
+		Cmd(&cobra.Command{
+			Use:   "mysql [--database <database-name>]",
+			Short: "Start a MySQL shell for the specified server.",
+		}).
+		WithFlags(func(flags *pflag.FlagSet) {
+			flags.StringVar(&database, "database", "", "Connect to the specified database.")
+		}).
+		With(
+			fncobra.ParseEnv(&env),
+			fncobra.ParseLocations(&locs, &env, fncobra.ParseLocationsOpts{RequireSingle: true}),
+			planningargs.ParseServers(&servers, &env, &locs)).
+		Do(func(ctx context.Context) error {
+			srv := servers.Servers[0]
+
+			planner, err := runtime.PlannerFor(ctx, env)
+			if err != nil {
+				return err
+			}
+
+			db, err := selectMysqlDatabase(ctx, env, planner, srv, database)
+			if err != nil {
+				return err
+			}
+
+			mysqlImage, err := compute.GetValue(ctx,
+				oci.ResolveDigest(mysqlClientImage, oci.RegistryAccess{PublicImage: true}).ImageID())
+			if err != nil {
+				return err
+			}
+
+			password := mysqlPassword{
+				ref:   schema.MakePackageRef(srv.PackageName(), "mysql-password"),
+				value: []byte(db.Password),
+			}
+
+			return runtime.RunAttachedStdio(ctx, env, planner, runtime.DeployableSpec{
+				PackageRef: srv.PackageRef(),
+				Attachable: runtime.AttachableKind_WITH_TTY,
+				Class:      schema.DeployableClass_ONESHOT,
+				Id:         ids.NewRandomBase32ID(8),
+				Name:       "mysql",
+				MainContainer: runtime.ContainerRunOpts{
+					WorkingDir: "/",
+					Image:      mysqlImage,
+					Command:    []string{"mysql"},
+					Args: []string{
+						"-h", db.ClusterHost,
+						"-P", db.ClusterPort,
+						"-u", db.User,
+						db.Name,
+					},
+					// The password is stored in a secret, rather than in the pod spec.
+					Env: []*schema.BinaryConfig_EnvEntry{
+						{Name: "MYSQL_PWD", Value: &schema.Resolvable{FromSecretRef: password.ref}},
+					},
+					ReadOnlyFilesystem: true,
+				},
+				Secrets: password,
+			})
+		})
+}
+
+// Same image as the colocated MySQL server, which ships with the client.
+const mysqlClientImage = "mysql:8.0.36"
+
+var mysqlDatabaseClass = schema.MakePackageRef("namespacelabs.dev/foundation/library/database/mysql", "Database")
+
+// selectMysqlDatabase loads the instances of the MySQL databases that the
+// server was last deployed with.
+func selectMysqlDatabase(ctx context.Context, env cfg.Context, planner runtime.Planner, srv planning.Server, database string) (*mysqlclass.DatabaseInstance, error) {
+	stack, err := planning.ComputeStack(ctx, planning.Servers{srv}, planning.ProvisionOpts{Planner: planner, PortRange: eval.DefaultPortRange()})
+	if err != nil {
+		return nil, err
+	}
+
+	planned, ok := stack.Get(srv.PackageName())
+	if !ok {
+		return nil, fnerrors.InternalError("%s: missing from the computed stack", srv.PackageName())
+	}
+
+	var refs []*schema.PackageRef
+	for _, res := range planned.Resources {
+		if res.Spec.Class.Ref.Equals(mysqlDatabaseClass) {
+			refs = append(refs, res.ResourceRef)
+		}
+	}
+
+	if len(refs) == 0 {
+		return nil, fnerrors.Newf("%s: server has no MySQL databases", srv.PackageName())
+	}
+
+	cluster, err := runtime.NamespaceFor(ctx, env)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := cluster.DeployedResourceConfig(ctx, srv.Proto())
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := resources.ParseResourceData(data)
+	if err != nil {
+		return nil, fnerrors.InternalError("failed to parse deployed resources: %w", err)
+	}
+
+	dbIndex := map[string]*mysqlclass.DatabaseInstance{}
+	names := uniquestrings.List{}
+	for _, ref := range refs {
+		db := &mysqlclass.DatabaseInstance{}
+		if err := parsed.Unmarshal(ref.Canonical(), db); err != nil {
+			return nil, fnerrors.BadInputError("%s: was not deployed with %s; try re-deploying: %w", srv.PackageName(), ref.Canonical(), err)
+		}
+
+		dbIndex[db.Name] = db
+		names.Add(db.Name)
+	}
+
+	if database == "" {
+		if names.Len() == 1 {
+			database = names.Strings()[0]
+		} else {
+			var items []mysqlDatabaseItem
+			for _, name := range names.Strings() {
+				items = append(items, mysqlDatabaseItem{dbIndex[name]})
+			}
+
+			item, err := tui.ListSelect(ctx, "Which database to connect to?", items)
+			if err != nil {
+				return nil, err
+			}
+
+			if item == nil {
+				return nil, context.Canceled
+			}
+
+			database = item.(mysqlDatabaseItem).db.Name
+		}
+	}
+
+	db, ok := dbIndex[database]
+	if !ok {
+		return nil, fnerrors.UsageError(fmt.Sprintf("Try one of the following databases: %v", names.Strings()), "Specified database does not exist.")
+	}
+
+	return db, nil
+}
+
+// mysqlPassword grounds a database password as the only secret of the client
+// deployable. Resource instances are not backed by secrets (yet).
+type mysqlPassword struct {
+	ref   *schema.PackageRef
+	value []byte
+}
+
+func (p mysqlPassword) Get(ctx context.Context, ref *schema.PackageRef) (*schema.SecretResult, error) {
+	if !ref.Equals(p.ref) {
+		return nil, fnerrors.InternalError("%s: no such secret", ref.Canonical())
+	}
+
+	return &schema.SecretResult{Ref: p.ref, Value: p.value}, nil
+}
+
+type mysqlDatabaseItem struct {
+	db *mysqlclass.DatabaseInstance
+}
+
+func (d mysqlDatabaseItem) Title() string       { return d.db.Name }
+func (d mysqlDatabaseItem) Description() string { return d.db.ClusterAddress }
+func (d mysqlDatabaseItem) FilterValue() string { return d.db.Name }
+
 type databaseBind struct {
 	PackageName string
 	Database    *postgres.Database
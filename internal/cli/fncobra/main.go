@@ -31,6 +31,7 @@ import (
 	"namespacelabs.dev/foundation/internal/console"
 	"namespacelabs.dev/foundation/internal/console/colors"
 	"namespacelabs.dev/foundation/internal/console/consolesink"
+	"namespacelabs.dev/foundation/internal/eventstream"
 	"namespacelabs.dev/foundation/internal/fnapi"
 	"namespacelabs.dev/foundation/internal/fnerrors"
 	"namespacelabs.dev/foundation/internal/fnerrors/format"
@@ -41,6 +42,7 @@ import (
 	"namespacelabs.dev/foundation/std/cfg/knobs"
 	"namespacelabs.dev/foundation/std/tasks"
 	"namespacelabs.dev/foundation/std/tasks/actiontracing"
+	"namespacelabs.dev/foundation/std/tasks/chrometrace"
 	"namespacelabs.dev/foundation/std/tasks/idtypes"
 	"namespacelabs.dev/foundation/std/tasks/simplelog"
 	"namespacelabs.dev/foundation/universe/vault"
@@ -49,6 +51,7 @@ import (
 var (
 	enableErrorTracing   = false
 	disableCommandBundle = false
+	traceOutPath         = ""
 )
 
 type MainOpts struct {
@@ -113,9 +116,23 @@ func doMain(opts MainOpts) (colors.Style, error) {
 		maybeRunLatestFromCache(rootCtx, style, flushLogs, opts.Name)
 	}
 
+	// Multiple exporters may be configured; each of them is flushed on exit,
+	// most recently set up first.
 	var cleanupTracer func()
+	addTracerCleanup := func(cleanup func()) {
+		previous := cleanupTracer
+		cleanupTracer = func() {
+			cleanup()
+			if previous != nil {
+				previous()
+			}
+		}
+	}
+
 	if tracerEndpoint := viper.GetString("jaeger_endpoint"); tracerEndpoint != "" && viper.GetBool("enable_tracing") {
-		rootCtx, cleanupTracer = actiontracing.SetupJaegerTracing(rootCtx, tracerEndpoint)
+		var cleanup func()
+		rootCtx, cleanup = actiontracing.SetupJaegerTracing(rootCtx, tracerEndpoint)
+		addTracerCleanup(cleanup)
 	}
 
 	if key := os.Getenv("FOUNDATION_TRACING_HONEYCOMB_TEAM"); key != "" {
@@ -124,7 +141,9 @@ func doMain(opts MainOpts) (colors.Style, error) {
 			return style, err
 		}
 
-		rootCtx, cleanupTracer = actiontracing.SetupTracing(rootCtx, actiontracing.CreateTracerForExporter(exp))
+		var cleanup func()
+		rootCtx, cleanup = actiontracing.SetupTracing(rootCtx, actiontracing.CreateTracerForExporter(exp))
+		addTracerCleanup(cleanup)
 	}
 
 	// Some of our builds can go fairly wide on parallelism, requiring opening
@@ -136,12 +155,24 @@ func doMain(opts MainOpts) (colors.Style, error) {
 	}
 
 	var run *storedrun.Run
+	var traceSink *chrometrace.Sink
+	var eventSink *eventstream.Sink
+	var closeEvents func() error
 
 	rootCmd := newRoot(opts.Name, func(cmd *cobra.Command, args []string) error {
 		if err := console.Prepare(); err != nil {
 			return err
 		}
 
+		// OTLP tracing is configured with flags, so it's only set up once
+		// they're parsed.
+		if tracingCtx, cleanup, err := setupOTLPTracing(cmd.Context()); err != nil {
+			return err
+		} else if cleanup != nil {
+			cmd.SetContext(tracingCtx)
+			addTracerCleanup(cleanup)
+		}
+
 		ctx := cmd.Context()
 
 		// Abort early for commands that are not yet supported on the current
@@ -163,6 +194,30 @@ func doMain(opts MainOpts) (colors.Style, error) {
 
 		run = storedrun.New()
 
+		if traceOutPath != "" {
+			traceSink = chrometrace.NewSink(tasks.SinkFrom(ctx))
+			cmd.SetContext(tasks.WithSink(ctx, traceSink))
+		}
+
+		if err := eventstream.ValidateFormat(); err != nil {
+			return err
+		}
+
+		if eventstream.OutputFormat != "" {
+			var out io.Writer = os.Stdout
+			if eventstream.OutputPath != "" {
+				f, err := os.Create(eventstream.OutputPath)
+				if err != nil {
+					return fnerrors.Newf("failed to create events file: %w", err)
+				}
+				out = f
+				closeEvents = f.Close
+			}
+
+			eventSink = eventstream.NewSink(tasks.SinkFrom(cmd.Context()), out)
+			cmd.SetContext(tasks.WithSink(cmd.Context(), eventSink))
+		}
+
 		// Setting up container registry logging, which is unfortunately global.
 		crlogs.Warn = log.New(console.TypedOutput(cmd.Context(), "cr-warn", idtypes.CatOutputTool), "", log.LstdFlags|log.Lmicroseconds)
 
@@ -197,8 +252,13 @@ func doMain(opts MainOpts) (colors.Style, error) {
 		"If set to true, prints a trace of foundation errors leading to the root cause with source info.")
 	rootCmd.PersistentFlags().StringVar(&dirs.CacheDir, "cache_dir", dirs.CacheDir,
 		"Where to place cache contents.")
+	rootCmd.PersistentFlags().StringVar(&traceOutPath, "trace_out", traceOutPath,
+		"If set, writes the invocation's action timeline to the specified file, in Chrome's trace event format (which can be opened with Perfetto).")
+
+	setupTracingFlags(rootCmd.PersistentFlags())
 
 	storedrun.SetupFlags(rootCmd.PersistentFlags())
+	eventstream.SetupFlags(rootCmd.PersistentFlags())
 
 	knobs.SetupFlags(rootCmd.PersistentFlags())
 
@@ -211,6 +271,12 @@ func doMain(opts MainOpts) (colors.Style, error) {
 		"debug_api_response",
 		"relaxed_response_parsing",
 		"cache_dir",
+		"otlp_protocol",
+		"otlp_headers",
+		"otlp_insecure",
+		"otlp_ca_cert",
+		"otlp_client_cert",
+		"otlp_client_key",
 	} {
 		_ = rootCmd.PersistentFlags().MarkHidden(noisy)
 	}
@@ -235,6 +301,22 @@ func doMain(opts MainOpts) (colors.Style, error) {
 		}
 	}
 
+	if traceSink != nil {
+		if traceErr := traceSink.WriteFile(traceOutPath); traceErr != nil && err == nil {
+			err = traceErr
+		}
+	}
+
+	if eventSink != nil {
+		eventSink.Close(err)
+	}
+
+	if closeEvents != nil {
+		if closeErr := closeEvents(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+
 	if flushLogs != nil {
 		flushLogs()
 	}
@@ -341,6 +423,30 @@ func SetupViper() {
 	viper.SetDefault("jaeger_endpoint", "")
 	_ = viper.BindEnv("jaeger_endpoint")
 
+	viper.SetDefault("otlp_endpoint", "")
+	_ = viper.BindEnv("otlp_endpoint")
+
+	viper.SetDefault("otlp_protocol", "grpc")
+	_ = viper.BindEnv("otlp_protocol")
+
+	viper.SetDefault("otlp_headers", "")
+	_ = viper.BindEnv("otlp_headers")
+
+	viper.SetDefault("otlp_insecure", false)
+	_ = viper.BindEnv("otlp_insecure")
+
+	viper.SetDefault("otlp_ca_cert", "")
+	_ = viper.BindEnv("otlp_ca_cert")
+
+	viper.SetDefault("otlp_client_cert", "")
+	_ = viper.BindEnv("otlp_client_cert")
+
+	viper.SetDefault("otlp_client_key", "")
+	_ = viper.BindEnv("otlp_client_key")
+
+	viper.SetDefault("tracing_sampling_ratio", 1.0)
+	_ = viper.BindEnv("tracing_sampling_ratio")
+
 	viper.SetDefault("console_no_colors", false)
 	_ = viper.BindEnv("console_no_colors")
 
@@ -10,6 +10,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"namespacelabs.dev/foundation/internal/artifacts/oci"
+	"namespacelabs.dev/foundation/internal/build/attest"
 	"namespacelabs.dev/foundation/internal/build/binary"
 	"namespacelabs.dev/foundation/internal/build/binary/genbinary"
 	"namespacelabs.dev/foundation/internal/build/buildkit"
@@ -170,6 +171,14 @@ func DoMain(name string, autoUpdate bool, registerCommands func(*cobra.Command))
 				"If set to true, prebuilts are uploaded to the target registry.")
 			rootCmd.PersistentFlags().BoolVar(&oci.ConvertImagesToEstargz, "oci_convert_images_to_estargz", oci.ConvertImagesToEstargz,
 				"If set to true, images are converted to estargz before being uploaded to a registry.")
+			rootCmd.PersistentFlags().BoolVar(&oci.SignImages, "oci_sign_images", oci.SignImages,
+				"If set to true, published images are signed (see `ns keys signing`).")
+			rootCmd.PersistentFlags().StringVar(&oci.SigningKeyName, "oci_signing_key", oci.SigningKeyName,
+				"The name of the key to sign images with. Required if there's more than one signing key.")
+			rootCmd.PersistentFlags().BoolVar(&attest.Enabled, "build_attestations", attest.Enabled,
+				"If set to true, an SBOM and a provenance statement are attached to each image that ns builds and publishes.")
+			rootCmd.PersistentFlags().BoolVar(&deploy.VerifyImageSignatures, "verify_image_signatures", deploy.VerifyImageSignatures,
+				"If set to true, deployments to production environments require every image to be signed by a trusted key.")
 			rootCmd.PersistentFlags().BoolVar(&tool.InvocationDebug, "invocation_debug", tool.InvocationDebug,
 				"If set to true, pass --debug to invocations.")
 			rootCmd.PersistentFlags().BoolVar(&kubernetes.UseNodePlatformsForProduction, "kubernetes_use_node_platforms_in_production_builds",
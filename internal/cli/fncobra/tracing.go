@@ -0,0 +1,57 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package fncobra
+
+import (
+	"context"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/std/tasks/actiontracing"
+)
+
+func setupTracingFlags(flags *pflag.FlagSet) {
+	flags.String("otlp_endpoint", "", "If set, exports traces to the specified OTLP collector (host:port, or a URL).")
+	flags.String("otlp_protocol", actiontracing.ProtocolGRPC, "The protocol used to talk to the OTLP collector: grpc or http/protobuf.")
+	flags.String("otlp_headers", "", "Additional headers to send to the OTLP collector, as a comma-separated list of key=value pairs.")
+	flags.Bool("otlp_insecure", false, "If set to true, disables TLS when talking to the OTLP collector.")
+	flags.String("otlp_ca_cert", "", "Path to a PEM-encoded CA certificate used to verify the OTLP collector.")
+	flags.String("otlp_client_cert", "", "Path to a PEM-encoded client certificate used to authenticate with the OTLP collector.")
+	flags.String("otlp_client_key", "", "Path to the PEM-encoded key of the client certificate.")
+	flags.Float64("tracing_sampling_ratio", 1, "The fraction of invocations that are traced, between 0 and 1.")
+
+	for _, name := range []string{"otlp_endpoint", "otlp_protocol", "otlp_headers", "otlp_insecure", "otlp_ca_cert", "otlp_client_cert", "otlp_client_key", "tracing_sampling_ratio"} {
+		_ = viper.BindPFlag(name, flags.Lookup(name))
+	}
+}
+
+func setupOTLPTracing(ctx context.Context) (context.Context, func(), error) {
+	endpoint := viper.GetString("otlp_endpoint")
+	if endpoint == "" {
+		return ctx, nil, nil
+	}
+
+	headers, err := actiontracing.ParseHeaders(viper.GetString("otlp_headers"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ratio := viper.GetFloat64("tracing_sampling_ratio")
+	if ratio < 0 || ratio > 1 {
+		return nil, nil, fnerrors.BadInputError("tracing_sampling_ratio must be between 0 and 1, got %v", ratio)
+	}
+
+	return actiontracing.SetupOTLPTracing(ctx, actiontracing.OTLPConfig{
+		Endpoint:       endpoint,
+		Protocol:       viper.GetString("otlp_protocol"),
+		Headers:        headers,
+		Insecure:       viper.GetBool("otlp_insecure"),
+		CACertFile:     viper.GetString("otlp_ca_cert"),
+		ClientCertFile: viper.GetString("otlp_client_cert"),
+		ClientKeyFile:  viper.GetString("otlp_client_key"),
+		SamplingRatio:  ratio,
+	})
+}
@@ -0,0 +1,425 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package protos
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/exp/maps"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// AllowBreakingAnnotation, when present in the comments of a service, method,
+// message, field or enum, allows breaking changes to it (and anything nested
+// within, or reached through it).
+const AllowBreakingAnnotation = "ns:allow-breaking"
+
+// BreakingChange is a wire-incompatible change to an exported service.
+type BreakingChange struct {
+	Service string
+	// The element that changed, e.g. "foo.v1.Request.name".
+	Element string
+	Message string
+}
+
+func (c BreakingChange) String() string {
+	return fmt.Sprintf("%s: %s", c.Element, c.Message)
+}
+
+// CheckBreaking compares a service against its baseline definition, and
+// returns the changes which would break existing clients or servers on the
+// wire: removed methods or fields (unless their numbers are reserved),
+// incompatible type or cardinality changes, reused reserved numbers, and
+// renamed services or packages. Returns nil if the service doesn't exist in
+// the baseline, and wasn't renamed from a service that did.
+func CheckBreaking(baseline, current *FileDescriptorSetAndDeps, service string) []BreakingChange {
+	c := &checker{
+		baseline: indexDescriptors(baseline),
+		current:  indexDescriptors(current),
+		service:  service,
+		visited:  map[string]bool{},
+	}
+
+	cur, ok := c.current.services[service]
+	if !ok {
+		return nil
+	}
+
+	base, ok := c.baseline.services[service]
+	if !ok {
+		base = c.findRenamed(cur)
+	}
+
+	if base == nil {
+		return nil
+	}
+
+	c.compareService(base, cur)
+	return c.changes
+}
+
+// findRenamed returns the baseline service which the specified service was
+// renamed from, if any: a service which no longer exists, with either the
+// same name in a different package, or a different name in the same package.
+func (c *checker) findRenamed(cur *serviceEntry) *serviceEntry {
+	names := maps.Keys(c.baseline.services)
+	slices.Sort(names)
+
+	simpleName := c.service[strings.LastIndex(c.service, ".")+1:]
+	for _, name := range names {
+		if _, exists := c.current.services[name]; !exists && name[strings.LastIndex(name, ".")+1:] == simpleName {
+			c.report(cur.allowed, c.service, "package renamed from %q to %q; the service's path changes", packageOf(name), packageOf(c.service))
+			return c.baseline.services[name]
+		}
+	}
+
+	for _, name := range names {
+		if _, exists := c.current.services[name]; !exists && packageOf(name) == packageOf(c.service) {
+			c.report(cur.allowed, c.service, "service renamed from %q; the service's path changes", name)
+			return c.baseline.services[name]
+		}
+	}
+
+	return nil
+}
+
+type checker struct {
+	baseline, current *descIndex
+	service           string
+	changes           []BreakingChange
+	visited           map[string]bool
+}
+
+func (c *checker) report(allowed bool, element, format string, args ...any) {
+	if allowed {
+		return
+	}
+
+	c.changes = append(c.changes, BreakingChange{
+		Service: c.service,
+		Element: element,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *checker) compareService(base, cur *serviceEntry) {
+	methods := map[string]*descriptorpb.MethodDescriptorProto{}
+	allowedMethods := map[string]bool{}
+	for k, m := range cur.desc.Method {
+		methods[m.GetName()] = m
+		allowedMethods[m.GetName()] = cur.allowed || cur.methodsAllowed[k]
+	}
+
+	for _, bm := range base.desc.Method {
+		element := c.service + "." + bm.GetName()
+
+		cm, ok := methods[bm.GetName()]
+		if !ok {
+			c.report(cur.allowed, element, "method removed")
+			continue
+		}
+
+		allowed := allowedMethods[bm.GetName()]
+
+		if bm.GetClientStreaming() != cm.GetClientStreaming() {
+			c.report(allowed, element, "client streaming changed from %v to %v", bm.GetClientStreaming(), cm.GetClientStreaming())
+		}
+
+		if bm.GetServerStreaming() != cm.GetServerStreaming() {
+			c.report(allowed, element, "server streaming changed from %v to %v", bm.GetServerStreaming(), cm.GetServerStreaming())
+		}
+
+		// Messages are compared structurally, as names are not part of the wire format.
+		c.compareMessage(typeName(bm.GetInputType()), typeName(cm.GetInputType()), allowed)
+		c.compareMessage(typeName(bm.GetOutputType()), typeName(cm.GetOutputType()), allowed)
+	}
+}
+
+func (c *checker) compareMessage(baseName, curName string, allowed bool) {
+	key := fmt.Sprintf("m:%s:%s:%v", baseName, curName, allowed)
+	if c.visited[key] {
+		return
+	}
+	c.visited[key] = true
+
+	base, ok := c.baseline.messages[baseName]
+	if !ok {
+		return
+	}
+
+	cur, ok := c.current.messages[curName]
+	if !ok {
+		return
+	}
+
+	allowed = allowed || cur.allowed
+
+	baseFields := map[int32]*descriptorpb.FieldDescriptorProto{}
+	for _, f := range base.desc.Field {
+		baseFields[f.GetNumber()] = f
+	}
+
+	curFields := map[int32]int{}
+	for k, f := range cur.desc.Field {
+		curFields[f.GetNumber()] = k
+	}
+
+	for _, bf := range base.desc.Field {
+		k, ok := curFields[bf.GetNumber()]
+		if !ok {
+			if !isReserved(cur.desc.ReservedRange, bf.GetNumber()) {
+				c.report(allowed, curName+"."+bf.GetName(), "field %d removed without reserving its number", bf.GetNumber())
+			}
+			continue
+		}
+
+		c.compareField(curName, bf, cur.desc.Field[k], allowed || cur.fieldsAllowed[k])
+	}
+
+	for k, cf := range cur.desc.Field {
+		if _, ok := baseFields[cf.GetNumber()]; !ok && isReserved(base.desc.ReservedRange, cf.GetNumber()) {
+			c.report(allowed || cur.fieldsAllowed[k], curName+"."+cf.GetName(), "reuses field number %d, which was reserved", cf.GetNumber())
+		}
+	}
+}
+
+func (c *checker) compareField(message string, base, cur *descriptorpb.FieldDescriptorProto, allowed bool) {
+	element := message + "." + cur.GetName()
+
+	if (base.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED) != (cur.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED) {
+		c.report(allowed, element, "cardinality changed from %s to %s", labelName(base), labelName(cur))
+		return
+	}
+
+	if wireClass(base.GetType()) != wireClass(cur.GetType()) {
+		c.report(allowed, element, "type changed from %s to %s", fieldTypeName(base), fieldTypeName(cur))
+		return
+	}
+
+	switch {
+	case base.GetType() == descriptorpb.FieldDescriptorProto_TYPE_MESSAGE || base.GetType() == descriptorpb.FieldDescriptorProto_TYPE_GROUP:
+		c.compareMessage(typeName(base.GetTypeName()), typeName(cur.GetTypeName()), allowed)
+
+	case base.GetType() == descriptorpb.FieldDescriptorProto_TYPE_ENUM && cur.GetType() == descriptorpb.FieldDescriptorProto_TYPE_ENUM:
+		c.compareEnum(typeName(base.GetTypeName()), typeName(cur.GetTypeName()), allowed)
+	}
+}
+
+func (c *checker) compareEnum(baseName, curName string, allowed bool) {
+	key := fmt.Sprintf("e:%s:%s:%v", baseName, curName, allowed)
+	if c.visited[key] {
+		return
+	}
+	c.visited[key] = true
+
+	base, ok := c.baseline.enums[baseName]
+	if !ok {
+		return
+	}
+
+	cur, ok := c.current.enums[curName]
+	if !ok {
+		return
+	}
+
+	allowed = allowed || cur.allowed
+
+	numbers := map[int32]bool{}
+	for _, v := range cur.desc.Value {
+		numbers[v.GetNumber()] = true
+	}
+
+	reserved := func(n int32) bool {
+		for _, r := range cur.desc.ReservedRange {
+			// Enum reserved ranges are inclusive.
+			if n >= r.GetStart() && n <= r.GetEnd() {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, bv := range base.desc.Value {
+		if !numbers[bv.GetNumber()] && !reserved(bv.GetNumber()) {
+			c.report(allowed, curName+"."+bv.GetName(), "enum value %d removed without reserving its number", bv.GetNumber())
+		}
+	}
+}
+
+func isReserved(ranges []*descriptorpb.DescriptorProto_ReservedRange, n int32) bool {
+	for _, r := range ranges {
+		// Message reserved ranges are exclusive of the end.
+		if n >= r.GetStart() && n < r.GetEnd() {
+			return true
+		}
+	}
+	return false
+}
+
+// wireClass groups types whose encodings are interchangeable on the wire.
+func wireClass(t descriptorpb.FieldDescriptorProto_Type) string {
+	switch t {
+	case descriptorpb.FieldDescriptorProto_TYPE_INT32, descriptorpb.FieldDescriptorProto_TYPE_INT64,
+		descriptorpb.FieldDescriptorProto_TYPE_UINT32, descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+		descriptorpb.FieldDescriptorProto_TYPE_BOOL, descriptorpb.FieldDescriptorProto_TYPE_ENUM:
+		return "varint"
+
+	case descriptorpb.FieldDescriptorProto_TYPE_SINT32, descriptorpb.FieldDescriptorProto_TYPE_SINT64:
+		return "zigzag"
+
+	case descriptorpb.FieldDescriptorProto_TYPE_FIXED32, descriptorpb.FieldDescriptorProto_TYPE_SFIXED32:
+		return "fixed32"
+
+	case descriptorpb.FieldDescriptorProto_TYPE_FIXED64, descriptorpb.FieldDescriptorProto_TYPE_SFIXED64:
+		return "fixed64"
+
+	case descriptorpb.FieldDescriptorProto_TYPE_STRING, descriptorpb.FieldDescriptorProto_TYPE_BYTES:
+		return "bytes"
+
+	default:
+		return t.String()
+	}
+}
+
+func fieldTypeName(f *descriptorpb.FieldDescriptorProto) string {
+	if f.GetTypeName() != "" {
+		return typeName(f.GetTypeName())
+	}
+	return strings.ToLower(strings.TrimPrefix(f.GetType().String(), "TYPE_"))
+}
+
+func labelName(f *descriptorpb.FieldDescriptorProto) string {
+	if f.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED {
+		return "repeated"
+	}
+	return "singular"
+}
+
+func typeName(name string) string { return strings.TrimPrefix(name, ".") }
+
+func packageOf(fullName string) string {
+	if i := strings.LastIndex(fullName, "."); i >= 0 {
+		return fullName[:i]
+	}
+	return ""
+}
+
+type descIndex struct {
+	messages map[string]*messageEntry
+	enums    map[string]*enumEntry
+	services map[string]*serviceEntry
+}
+
+type messageEntry struct {
+	desc          *descriptorpb.DescriptorProto
+	allowed       bool
+	fieldsAllowed []bool
+}
+
+type enumEntry struct {
+	desc    *descriptorpb.EnumDescriptorProto
+	allowed bool
+}
+
+type serviceEntry struct {
+	desc           *descriptorpb.ServiceDescriptorProto
+	allowed        bool
+	methodsAllowed []bool
+}
+
+// Field numbers used in source code info paths; see descriptor.proto.
+const (
+	fileMessageTypeTag   = 4
+	fileEnumTypeTag      = 5
+	fileServiceTag       = 6
+	messageFieldTag      = 2
+	messageNestedTypeTag = 3
+	messageEnumTypeTag   = 4
+	serviceMethodTag     = 2
+)
+
+func indexDescriptors(set *FileDescriptorSetAndDeps) *descIndex {
+	idx := &descIndex{
+		messages: map[string]*messageEntry{},
+		enums:    map[string]*enumEntry{},
+		services: map[string]*serviceEntry{},
+	}
+
+	for _, files := range [][]*descriptorpb.FileDescriptorProto{set.Dependency, set.File} {
+		for _, file := range files {
+			annotated := annotatedPaths(file)
+
+			prefix := file.GetPackage()
+			if prefix != "" {
+				prefix += "."
+			}
+
+			for k, msg := range file.MessageType {
+				idx.addMessage(annotated, prefix, msg, []int32{fileMessageTypeTag, int32(k)}, false)
+			}
+
+			for k, enum := range file.EnumType {
+				idx.enums[prefix+enum.GetName()] = &enumEntry{desc: enum, allowed: annotated[pathKey(fileEnumTypeTag, int32(k))]}
+			}
+
+			for k, svc := range file.Service {
+				path := []int32{fileServiceTag, int32(k)}
+				entry := &serviceEntry{desc: svc, allowed: annotated[pathKey(path...)]}
+				for j := range svc.Method {
+					entry.methodsAllowed = append(entry.methodsAllowed, annotated[pathKey(appendPath(path, serviceMethodTag, int32(j))...)])
+				}
+				idx.services[prefix+svc.GetName()] = entry
+			}
+		}
+	}
+
+	return idx
+}
+
+func (idx *descIndex) addMessage(annotated map[string]bool, prefix string, msg *descriptorpb.DescriptorProto, path []int32, parentAllowed bool) {
+	name := prefix + msg.GetName()
+
+	entry := &messageEntry{desc: msg, allowed: parentAllowed || annotated[pathKey(path...)]}
+	for k := range msg.Field {
+		entry.fieldsAllowed = append(entry.fieldsAllowed, annotated[pathKey(appendPath(path, messageFieldTag, int32(k))...)])
+	}
+	idx.messages[name] = entry
+
+	for k, nested := range msg.NestedType {
+		idx.addMessage(annotated, name+".", nested, appendPath(path, messageNestedTypeTag, int32(k)), entry.allowed)
+	}
+
+	for k, enum := range msg.EnumType {
+		idx.enums[name+"."+enum.GetName()] = &enumEntry{
+			desc:    enum,
+			allowed: entry.allowed || annotated[pathKey(appendPath(path, messageEnumTypeTag, int32(k))...)],
+		}
+	}
+}
+
+// annotatedPaths returns the source paths of the elements whose comments
+// include AllowBreakingAnnotation.
+func annotatedPaths(file *descriptorpb.FileDescriptorProto) map[string]bool {
+	annotated := map[string]bool{}
+	for _, loc := range file.GetSourceCodeInfo().GetLocation() {
+		comments := append([]string{loc.GetLeadingComments(), loc.GetTrailingComments()}, loc.GetLeadingDetachedComments()...)
+		for _, comment := range comments {
+			if strings.Contains(comment, AllowBreakingAnnotation) {
+				annotated[pathKey(loc.Path...)] = true
+			}
+		}
+	}
+	return annotated
+}
+
+func appendPath(path []int32, elems ...int32) []int32 {
+	return append(append([]int32{}, path...), elems...)
+}
+
+func pathKey(path ...int32) string {
+	return fmt.Sprint(path)
+}
@@ -0,0 +1,168 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package protos
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jhump/protoreflect/desc/protoparse"
+)
+
+const baselineProto = `
+syntax = "proto3";
+
+package demo.v1;
+
+service Greeter {
+	rpc Greet(GreetRequest) returns (GreetResponse);
+	rpc Wave(GreetRequest) returns (GreetResponse);
+	rpc Leave(GreetRequest) returns (GreetResponse);
+}
+
+message GreetRequest {
+	reserved 9;
+
+	string name = 1;
+	int32 count = 2;
+	repeated string tags = 3;
+	Mood mood = 4;
+	string removed = 5;
+	string retired = 6;
+}
+
+message GreetResponse {
+	string message = 1;
+	string debug = 2;
+}
+
+enum Mood {
+	MOOD_UNKNOWN = 0;
+	MOOD_HAPPY = 1;
+	MOOD_SAD = 2;
+}
+`
+
+const currentProto = `
+syntax = "proto3";
+
+package demo.v1;
+
+service Greeter {
+	rpc Greet(GreetRequest) returns (GreetResponse);
+	rpc Wave(GreetRequest) returns (stream GreetResponse);
+	// Going away; ns:allow-breaking
+	rpc Leave(GreetRequest) returns (stream GreetResponse);
+}
+
+message GreetRequest {
+	reserved 6;
+
+	string name = 1;
+	int64 count = 2;
+	string tags = 3;
+	Mood mood = 4;
+	bytes new_field = 9;
+}
+
+message GreetResponse {
+	bytes message = 1;
+	// ns:allow-breaking
+	int64 debug = 2;
+}
+
+enum Mood {
+	MOOD_UNKNOWN = 0;
+	MOOD_HAPPY = 1;
+}
+`
+
+func TestCheckBreaking(t *testing.T) {
+	got := CheckBreaking(parseForTest(t, baselineProto), parseForTest(t, currentProto), "demo.v1.Greeter")
+
+	var messages []string
+	for _, c := range got {
+		messages = append(messages, c.String())
+	}
+
+	expected := []string{
+		"demo.v1.GreetRequest.tags: cardinality changed from repeated to singular",
+		"demo.v1.Mood.MOOD_SAD: enum value 2 removed without reserving its number",
+		"demo.v1.GreetRequest.removed: field 5 removed without reserving its number",
+		"demo.v1.GreetRequest.new_field: reuses field number 9, which was reserved",
+		"demo.v1.Greeter.Wave: server streaming changed from false to true",
+	}
+
+	if d := cmp.Diff(expected, messages); d != "" {
+		t.Errorf("mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestCheckBreakingPackageRename(t *testing.T) {
+	renamed := strings.Replace(baselineProto, "package demo.v1;", "package demo.v2;", 1)
+
+	got := CheckBreaking(parseForTest(t, baselineProto), parseForTest(t, renamed), "demo.v2.Greeter")
+	if len(got) != 1 || !strings.Contains(got[0].Message, "package renamed") {
+		t.Errorf("expected a package rename, got %v", got)
+	}
+}
+
+func TestCheckBreakingServiceRename(t *testing.T) {
+	renamed := strings.Replace(baselineProto, "service Greeter {", "service Welcomer {", 1)
+
+	got := CheckBreaking(parseForTest(t, baselineProto), parseForTest(t, renamed), "demo.v1.Welcomer")
+	if len(got) != 1 || got[0].String() != `demo.v1.Welcomer: service renamed from "demo.v1.Greeter"; the service's path changes` {
+		t.Errorf("expected a service rename, got %v", got)
+	}
+}
+
+func TestCheckBreakingNewPackageVersion(t *testing.T) {
+	v2 := strings.Replace(baselineProto, "package demo.v1;", "package demo.v2;", 1)
+
+	// demo.v1.Greeter is still served, so demo.v2.Greeter is a new service.
+	got := CheckBreaking(parseForTest(t, baselineProto), parseForTest(t, baselineProto, v2), "demo.v2.Greeter")
+	if len(got) != 0 {
+		t.Errorf("expected no breaking changes, got %v", got)
+	}
+}
+
+func parseForTest(t *testing.T, contents ...string) *FileDescriptorSetAndDeps {
+	t.Helper()
+
+	files := map[string]string{}
+	var filenames []string
+	for k, c := range contents {
+		filename := fmt.Sprintf("demo%d.proto", k)
+		files[filename] = c
+		filenames = append(filenames, filename)
+	}
+
+	p := protoparse.Parser{
+		IncludeSourceCodeInfo: true,
+		Accessor: func(filename string) (io.ReadCloser, error) {
+			c, ok := files[filename]
+			if !ok {
+				return nil, os.ErrNotExist
+			}
+			return io.NopCloser(strings.NewReader(c)), nil
+		},
+	}
+
+	descs, err := p.ParseFiles(filenames...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	set := &FileDescriptorSetAndDeps{}
+	for _, desc := range descs {
+		set.File = append(set.File, desc.AsFileDescriptorProto())
+	}
+
+	return set
+}
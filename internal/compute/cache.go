@@ -123,7 +123,7 @@ func checkLoadCache(ctx context.Context, what string, g *Orch, c computeInstance
 
 					hit.VerifiedHit = true
 
-					return p.resolve(ResultWithTimestamp[any]{Result: v, Cached: true, Completed: output.Timestamp}, nil)
+					return p.resolve(ResultWithTimestamp[any]{Result: v, Cached: true, ActionID: p.actionID, Completed: output.Timestamp}, nil)
 				} else {
 					trace.SpanFromContext(ctx).RecordError(err)
 				}
@@ -172,17 +172,44 @@ func deferStore(ctx context.Context, g *Orch, c hasAction, cacheable *cacheable,
 			entry.Debug.PackagePath = inputs.pkgPath
 			entry.Debug.Typename = inputs.typeName
 
-			entry.InputDigests = map[string]string{}
-			for _, kv := range inputs.digests {
-				if kv.IsSet {
-					entry.InputDigests[kv.Name] = kv.Digest
-				}
+			entry.InputDigests = inputs.knownDigests()
+
+			if err := g.cache.StoreEntry(ctx, pointers, entry); err != nil {
+				return err
+			}
+
+			// The latest output is only used to explain cache misses, so it's only
+			// kept track of while a run is being recorded.
+			if latest, ok := g.cache.(cache.LatestIndex); ok && g.runRecorder() != nil {
+				return latest.StoreLatest(ctx, latestKey(c, inputs), entry)
 			}
 
-			return g.cache.StoreEntry(ctx, pointers, entry)
+			return nil
 		})
 }
 
+// Returns a key which identifies a computation across invocations, regardless
+// of its inputs. It's used to keep track of the last output of a computation.
+func latestKey(c hasAction, inputs *computedInputs) string {
+	name, label := tasks.NameOf(c.Action())
+	return fmt.Sprintf("%s.%s:%s:%s", inputs.pkgPath, inputs.typeName, name, label)
+}
+
+func loadLatest(ctx context.Context, g *Orch, c hasAction, inputs *computedInputs) *cache.CachedOutput {
+	latest, ok := g.cache.(cache.LatestIndex)
+	if !ok {
+		return nil
+	}
+
+	// Errors are ignored, this is best-effort information.
+	output, found, err := latest.LoadLatest(ctx, latestKey(c, inputs))
+	if err != nil || !found {
+		return nil
+	}
+
+	return &output
+}
+
 func cacheableFor(outputType interface{}) *cacheable {
 	vt := reflect.TypeOf(outputType)
 	if vt == nil {
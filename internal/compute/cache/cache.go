@@ -67,13 +67,16 @@ func Local() (Cache, error) {
 		return nil, err
 	}
 
-	dir := filepath.Join(cacheDir, "blobs")
+	return LocalAt(filepath.Join(cacheDir, "blobs"))
+}
 
+// LocalAt returns a cache which stores its contents under dir.
+func LocalAt(dir string) (Cache, error) {
 	if err := os.MkdirAll(filepath.Join(dir, "sha256"), 0700|os.ModeDir); err != nil {
 		return nil, err
 	}
 
-	return &localCache{path: dir}, err
+	return &localCache{path: dir}, nil
 }
 
 func Prune(ctx context.Context) error {
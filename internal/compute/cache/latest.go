@@ -0,0 +1,75 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/go-ids"
+)
+
+const latestDir = "latest"
+
+// LatestIndex is optionally implemented by caches which keep track of the
+// last output that was stored for a given computation, regardless of which
+// inputs produced it. It's used to explain why a computation was not cached,
+// and is only written to while a run is recorded (see compute.RecordRun).
+type LatestIndex interface {
+	LoadLatest(context.Context, string) (CachedOutput, bool, error)
+	StoreLatest(context.Context, string, CachedOutput) error
+}
+
+var _ LatestIndex = &localCache{}
+
+func (c *localCache) latestPath(key string) string {
+	h := sha256.Sum256([]byte(key))
+	return filepath.Join(c.path, latestDir, hex.EncodeToString(h[:])+".json")
+}
+
+func (c *localCache) LoadLatest(ctx context.Context, key string) (CachedOutput, bool, error) {
+	contents, err := os.ReadFile(c.latestPath(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return CachedOutput{}, false, nil
+		}
+		return CachedOutput{}, false, err
+	}
+
+	var out CachedOutput
+	if err := json.Unmarshal(contents, &out); err != nil {
+		return out, false, fnerrors.InternalError("failed to decode latest entry: %w", err)
+	}
+
+	return out, true, nil
+}
+
+func (c *localCache) StoreLatest(ctx context.Context, key string, output CachedOutput) error {
+	path := c.latestPath(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fnerrors.InternalError("failed to create cache latest dir: %w", err)
+	}
+
+	marshalled, err := json.Marshal(output)
+	if err != nil {
+		return fnerrors.InternalError("failed to marshal cached output: %w", err)
+	}
+
+	tmpFile := path + "." + ids.NewRandomBase32ID(4)
+	if err := os.WriteFile(tmpFile, marshalled, 0600); err != nil {
+		return fnerrors.InternalError("failed to write latest entry: %w", err)
+	}
+
+	if err := os.Rename(tmpFile, path); err != nil {
+		return fnerrors.InternalError("failed to commit latest entry: %w", err)
+	}
+
+	return nil
+}
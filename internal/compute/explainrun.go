@@ -0,0 +1,281 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package compute
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"namespacelabs.dev/foundation/internal/compute/cache"
+	"namespacelabs.dev/foundation/internal/versions"
+	"namespacelabs.dev/foundation/std/tasks"
+)
+
+// RunRecorder keeps track of every computation that is performed by a graph,
+// so that a report of a concrete evaluation can be produced after the fact.
+// Unlike `Explain`, which walks the static structure of a Computable.
+type RunRecorder struct {
+	mu    sync.Mutex
+	nodes map[tasks.ActionID]*RecordedNode
+}
+
+type RecordedNode struct {
+	ActionID       tasks.ActionID
+	Name, Label    string
+	Requested      time.Time // When the computation was first requested.
+	ComputeStarted time.Time // Zero if the value was loaded from the cache.
+	Completed      time.Time
+	Cached         bool
+	Cacheable      bool
+	Err            error
+	Deps           []tasks.ActionID
+	InputDigests   map[string]string
+	// For cache misses, the last output that was cached for the same computation, if any.
+	Previous *cache.CachedOutput
+}
+
+// RecordRun starts recording all computations performed by the graph.
+func (g *Orch) RecordRun() *RunRecorder {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.recorder == nil {
+		g.recorder = &RunRecorder{nodes: map[tasks.ActionID]*RecordedNode{}}
+	}
+
+	return g.recorder
+}
+
+func (g *Orch) runRecorder() *RunRecorder {
+	if g == nil {
+		return nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.recorder
+}
+
+func (r *RunRecorder) record(node *RecordedNode) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nodes[node.ActionID] = node
+}
+
+func (r *RunRecorder) Nodes() []*RecordedNode {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var nodes []*RecordedNode
+	for _, n := range r.nodes {
+		nodes = append(nodes, n)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Requested.Before(nodes[j].Requested)
+	})
+
+	return nodes
+}
+
+// Duration returns how long it took to produce the node's value, excluding the
+// time it waited for its dependencies.
+func (n *RecordedNode) Duration() time.Duration {
+	if n.ComputeStarted.IsZero() {
+		return n.Completed.Sub(n.Requested)
+	}
+	return n.Completed.Sub(n.ComputeStarted)
+}
+
+func (n *RecordedNode) label() string {
+	if n.Label != "" {
+		return fmt.Sprintf("%s (%s)", n.Label, n.Name)
+	}
+	return n.Name
+}
+
+type CriticalPathStep struct {
+	Node *RecordedNode
+	// How much of the critical path is attributed to this node: from the
+	// moment its last dependency completed, until it completed.
+	Self time.Duration
+}
+
+// CriticalPath returns the chain of computations that determined when the
+// last computation completed. Each step was blocked on the one before it.
+func (r *RunRecorder) CriticalPath() []CriticalPathStep {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var last *RecordedNode
+	for _, n := range r.nodes {
+		if last == nil || n.Completed.After(last.Completed) {
+			last = n
+		}
+	}
+
+	var path []CriticalPathStep
+	for n := last; n != nil; {
+		var gating *RecordedNode
+		for _, dep := range n.Deps {
+			if d, ok := r.nodes[dep]; ok && d != n && (gating == nil || d.Completed.After(gating.Completed)) {
+				gating = d
+			}
+		}
+
+		start := n.Requested
+		if gating != nil && gating.Completed.After(start) {
+			start = gating.Completed
+		}
+
+		path = append(path, CriticalPathStep{Node: n, Self: n.Completed.Sub(start)})
+		n = gating
+	}
+
+	// Order from the first computation, to the last.
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path
+}
+
+// Report writes a summary of the recorded evaluation: its critical path, the
+// slowest computations, and for each cache miss, which inputs changed since
+// the last time the computation's output was cached.
+func (r *RunRecorder) Report(w io.Writer, topN int) {
+	nodes := r.Nodes()
+
+	var cached, computed int
+	for _, n := range nodes {
+		if n.Cached {
+			cached++
+		} else {
+			computed++
+		}
+	}
+
+	fmt.Fprintf(w, "Evaluated %d computations (%d cached, %d computed).\n", len(nodes), cached, computed)
+
+	if path := r.CriticalPath(); len(path) > 0 {
+		total := path[len(path)-1].Node.Completed.Sub(path[0].Node.Requested)
+		fmt.Fprintf(w, "\nCritical path (%v):\n", total.Round(time.Millisecond))
+		for _, step := range path {
+			fmt.Fprintf(w, "  %10v  %s%s\n", step.Self.Round(time.Millisecond), step.Node.label(), cachedMarker(step.Node))
+		}
+	}
+
+	slowest := append([]*RecordedNode(nil), nodes...)
+	sort.SliceStable(slowest, func(i, j int) bool {
+		return slowest[i].Duration() > slowest[j].Duration()
+	})
+	if len(slowest) > topN {
+		slowest = slowest[:topN]
+	}
+
+	if len(slowest) > 0 {
+		fmt.Fprintf(w, "\nSlowest computations:\n")
+		for _, n := range slowest {
+			fmt.Fprintf(w, "  %10v  %s%s\n", n.Duration().Round(time.Millisecond), n.label(), cachedMarker(n))
+		}
+	}
+
+	var misses []*RecordedNode
+	for _, n := range nodes {
+		if n.Cacheable && !n.Cached && n.Err == nil {
+			misses = append(misses, n)
+		}
+	}
+
+	if len(misses) > 0 {
+		fmt.Fprintf(w, "\nCache misses:\n")
+		for _, n := range misses {
+			fmt.Fprintf(w, "  %s\n", n.label())
+			for _, line := range explainMiss(n) {
+				fmt.Fprintf(w, "    %s\n", line)
+			}
+		}
+	}
+}
+
+func cachedMarker(n *RecordedNode) string {
+	if n.Cached {
+		return " [cached]"
+	}
+	return ""
+}
+
+func explainMiss(n *RecordedNode) []string {
+	if n.Previous == nil {
+		return []string{"no previous cache entry"}
+	}
+
+	var lines []string
+
+	if v := versions.Builtin().CacheVersion; n.Previous.CacheVersion != v {
+		lines = append(lines, fmt.Sprintf("cache version changed: %d -> %d", n.Previous.CacheVersion, v))
+	}
+
+	var keys []string
+	for k := range n.InputDigests {
+		keys = append(keys, k)
+	}
+	for k := range n.Previous.InputDigests {
+		if _, ok := n.InputDigests[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		current, hasCurrent := n.InputDigests[k]
+		previous, hasPrevious := n.Previous.InputDigests[k]
+
+		switch {
+		case !hasPrevious:
+			lines = append(lines, fmt.Sprintf("%s: added (%s)", k, shortDigest(current)))
+		case !hasCurrent:
+			lines = append(lines, fmt.Sprintf("%s: removed (was %s)", k, shortDigest(previous)))
+		case current != previous:
+			lines = append(lines, fmt.Sprintf("%s: changed %s -> %s", k, shortDigest(previous), shortDigest(current)))
+		}
+	}
+
+	if len(lines) == 0 {
+		lines = append(lines, fmt.Sprintf("inputs unchanged since %s; the cache entry was likely evicted",
+			n.Previous.Timestamp.Format(time.RFC3339)))
+	}
+
+	return lines
+}
+
+func shortDigest(d string) string {
+	if alg, hex, ok := strings.Cut(d, ":"); ok && len(hex) > 12 {
+		return alg + ":" + hex[:12]
+	}
+	return d
+}
+
+func (n *RecordedNode) markCached() {
+	if n != nil {
+		n.Cached = true
+	}
+}
+
+func (n *RecordedNode) addDeps(results map[string]ResultWithTimestamp[any]) {
+	if n == nil {
+		return
+	}
+
+	for _, res := range results {
+		if res.ActionID != "" {
+			n.Deps = append(n.Deps, res.ActionID)
+		}
+	}
+}
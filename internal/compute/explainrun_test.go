@@ -0,0 +1,65 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package compute
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"namespacelabs.dev/foundation/internal/compute/cache"
+	"namespacelabs.dev/foundation/internal/versions"
+	"namespacelabs.dev/foundation/std/tasks"
+)
+
+func TestCriticalPath(t *testing.T) {
+	base := time.Unix(1000, 0)
+	at := func(sec int) time.Time { return base.Add(time.Duration(sec) * time.Second) }
+
+	r := &RunRecorder{nodes: map[tasks.ActionID]*RecordedNode{}}
+	r.record(&RecordedNode{ActionID: "a", Name: "a", Requested: at(0), Completed: at(2)})
+	r.record(&RecordedNode{ActionID: "b", Name: "b", Requested: at(0), Completed: at(5)})
+	r.record(&RecordedNode{ActionID: "c", Name: "c", Requested: at(0), Completed: at(6), Deps: []tasks.ActionID{"b"}})
+	r.record(&RecordedNode{ActionID: "root", Name: "root", Requested: at(0), Completed: at(7), Deps: []tasks.ActionID{"a", "c"}})
+
+	var got []string
+	var self []time.Duration
+	for _, step := range r.CriticalPath() {
+		got = append(got, step.Node.Name)
+		self = append(self, step.Self)
+	}
+
+	if d := cmp.Diff([]string{"b", "c", "root"}, got); d != "" {
+		t.Errorf("unexpected critical path (-want +got):\n%s", d)
+	}
+
+	if d := cmp.Diff([]time.Duration{5 * time.Second, time.Second, time.Second}, self); d != "" {
+		t.Errorf("unexpected self times (-want +got):\n%s", d)
+	}
+}
+
+func TestExplainMiss(t *testing.T) {
+	n := &RecordedNode{
+		InputDigests: map[string]string{"a": "sha256:1", "b": "sha256:2", "d": "sha256:4"},
+		Previous: &cache.CachedOutput{
+			CacheVersion: versions.Builtin().CacheVersion,
+			InputDigests: map[string]string{"a": "sha256:1", "b": "sha256:0", "c": "sha256:3"},
+		},
+	}
+
+	want := []string{
+		"b: changed sha256:0 -> sha256:2",
+		"c: removed (was sha256:3)",
+		"d: added (sha256:4)",
+	}
+
+	if d := cmp.Diff(want, explainMiss(n)); d != "" {
+		t.Errorf("unexpected explanation (-want +got):\n%s", d)
+	}
+
+	if d := cmp.Diff([]string{"no previous cache entry"}, explainMiss(&RecordedNode{})); d != "" {
+		t.Errorf("unexpected explanation (-want +got):\n%s", d)
+	}
+}
@@ -52,6 +52,7 @@ type Orch struct {
 	mu       sync.Mutex
 	promises map[string]*Promise[any]
 	cleaners []cleaner
+	recorder *RunRecorder
 
 	serialization map[string]*ctxmutex.Mutex
 }
@@ -202,7 +203,16 @@ func waitCompute(ctx context.Context, g *Orch, p *Promise[any], opts computeInst
 	var hits []cacheHit
 
 	ev := opts.Action()
-	name, _ := tasks.NameOf(ev)
+	name, label := tasks.NameOf(ev)
+
+	var node *RecordedNode
+	if recorder := g.runRecorder(); recorder != nil {
+		node = &RecordedNode{ActionID: p.actionID, Name: name, Label: label, Requested: time.Now(), Cacheable: shouldCache}
+		defer func() {
+			node.Completed = time.Now()
+			recorder.record(node)
+		}()
+	}
 
 	if err := ev.ID(p.actionID).RunWithOpts(ctx, tasks.RunOpts{
 		Wait: func(ctx context.Context) (bool, error) {
@@ -214,6 +224,7 @@ func waitCompute(ctx context.Context, g *Orch, p *Promise[any], opts computeInst
 				hits = append(hits, hit)
 			}
 			if hit.VerifiedHit {
+				node.markCached()
 				return true, nil
 			}
 
@@ -223,6 +234,8 @@ func waitCompute(ctx context.Context, g *Orch, p *Promise[any], opts computeInst
 				return false, err
 			}
 
+			node.addDeps(results)
+
 			if outputCachingInformation {
 				addOutputsToSpan(ctx, results)
 			}
@@ -246,6 +259,7 @@ func waitCompute(ctx context.Context, g *Orch, p *Promise[any], opts computeInst
 						hits = append(hits, hit)
 					}
 					if hit.VerifiedHit {
+						node.markCached()
 						return true, nil
 					}
 				}
@@ -258,6 +272,15 @@ func waitCompute(ctx context.Context, g *Orch, p *Promise[any], opts computeInst
 			return false, nil
 		},
 		Run: func(ctx context.Context) error {
+			if node != nil {
+				node.ComputeStarted = time.Now()
+				node.InputDigests = inputs.knownDigests()
+				if shouldCache {
+					// Must be loaded before the computed value is stored.
+					node.Previous = loadLatest(ctx, g, opts.Computable, inputs)
+				}
+			}
+
 			res, err := compute(ctx, g, p.actionID, opts, cacheable, shouldCache, inputs, *resolved)
 			if err != nil {
 				return err
@@ -270,6 +293,9 @@ func waitCompute(ctx context.Context, g *Orch, p *Promise[any], opts computeInst
 			return p.resolve(res, nil)
 		},
 	}); err != nil {
+		if node != nil {
+			node.Err = err
+		}
 		return p.fail(err)
 	}
 
@@ -125,7 +125,8 @@ type computedInputs struct {
 	typeName         string
 	digests          []keyDigest
 	computable       map[string]UntypedComputable
-	nonDeterministic bool // Even waiting for dependencies won't really lead to a deterministic digest.
+	nonDeterministic bool        // Even waiting for dependencies won't really lead to a deterministic digest.
+	finalized        []keyDigest // Set by `Finalize`, includes the digests of resolved dependencies.
 
 	Digest            schema.Digest // Only set if all inputs are known recursively over all dependencies.
 	PostComputeDigest schema.Digest // Only set after `Finalize`, and if all values were resolved.
@@ -155,11 +156,30 @@ func (c *computedInputs) Finalize(resolved map[string]ResultWithTimestamp[any])
 		}
 	}
 
+	c.finalized = computedInputs
+
 	var err error
 	c.PostComputeDigest, err = digestWithInputs(c.pkgPath, c.typeName, c.serial, computedInputs)
 	return err
 }
 
+// Returns the digest of each input which is known, including those of
+// dependencies if the inputs have been finalized.
+func (c *computedInputs) knownDigests() map[string]string {
+	digests := c.digests
+	if c.finalized != nil {
+		digests = c.finalized
+	}
+
+	m := map[string]string{}
+	for _, kv := range digests {
+		if kv.IsSet {
+			m[kv.Name] = kv.Digest
+		}
+	}
+	return m
+}
+
 func digestWithInputs(pkgPath, typeName string, serial int64, inputs []keyDigest) (schema.Digest, error) {
 	h := sha256.New()
 
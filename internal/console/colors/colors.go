@@ -34,6 +34,9 @@ type Style struct {
 	LogScope         Applicable // Highlight of a package for which an invocation is being made.
 	TestSuccess      Applicable // It says it on the tin.
 	TestFailure      Applicable // Here too.
+
+	// Used to tell apart the sources of interleaved logs.
+	LogSources []Applicable
 }
 
 func WithStyle(ctx context.Context, s Style) context.Context {
@@ -69,6 +72,10 @@ var WithColors = Style{
 	ErrorWhat:        aec.MagentaF,
 	TestSuccess:      aec.GreenF,
 	TestFailure:      aec.RedF,
+	LogSources: []Applicable{
+		aec.CyanF, aec.MagentaF, aec.GreenF, aec.YellowF, aec.BlueF,
+		aec.LightCyanF, aec.LightMagentaF, aec.LightGreenF, aec.LightYellowF, aec.LightBlueF,
+	},
 }
 
 var NoColors = Style{
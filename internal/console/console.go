@@ -160,7 +160,7 @@ func consoleOutputFromCtx(ctx context.Context, stderr bool, name string, cat idt
 		}
 
 		buf := &consoleBuffer{
-			actual: append([]writesLines{t}, extra...),
+			actual: append(append([]writesLines{t}, extra...), wrappedLineWriters(tasks.SinkFrom(ctx))...),
 			name:   name,
 			cat:    cat,
 			id:     idtypes.IdAndHashFrom(id),
@@ -22,3 +22,22 @@ func UnwrapSink(sink tasks.ActionSink) tasks.ActionSink {
 type hasUnwrap interface {
 	Unwrap() tasks.ActionSink
 }
+
+// wrappedLineWriters returns the sinks that wrap the console sink, and which
+// also want to observe console output.
+func wrappedLineWriters(sink tasks.ActionSink) []writesLines {
+	var writers []writesLines
+	for sink != nil {
+		x, ok := sink.(hasUnwrap)
+		if !ok {
+			break
+		}
+
+		if w, ok := sink.(writesLines); ok {
+			writers = append(writers, w)
+		}
+
+		sink = x.Unwrap()
+	}
+	return writers
+}
@@ -0,0 +1,109 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package depgraph
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type Impact struct {
+	// Packages which include at least one of the changed files.
+	Changed []*Node
+	// Every node that transitively depends on a changed package, including
+	// the changed packages themselves. Or every node in the graph, if any
+	// changed file is unowned and unowned files are not ignored.
+	Affected []*Node
+	// Changed files which don't belong to any package in the graph.
+	Unowned []string
+}
+
+// AffectedBy computes which nodes are affected by changes to the specified
+// files (absolute paths). Each file is attributed to the package whose
+// directory most closely contains it; or to the Go package in its directory,
+// if it's imported by the Go code of any package.
+//
+// Files which don't belong to any package (e.g. shared libraries, go.mod, or
+// the workspace definition) may impact anything, so unless ignoreUnowned is
+// set, changing any of them marks every node as affected.
+func (g *Graph) AffectedBy(files []string, ignoreUnowned bool) Impact {
+	var impact Impact
+
+	changed := map[string]*Node{}
+	for _, file := range files {
+		owner := g.owner(file)
+		if owner == nil {
+			impact.Unowned = append(impact.Unowned, file)
+			continue
+		}
+
+		changed[owner.ID] = owner
+	}
+
+	for _, n := range changed {
+		impact.Changed = append(impact.Changed, n)
+	}
+	sortNodes(impact.Changed)
+
+	dependents := map[string][]string{}
+	for _, e := range g.Edges {
+		dependents[e.To] = append(dependents[e.To], e.From)
+	}
+
+	visited := map[string]bool{}
+	var queue []string
+	for id := range changed {
+		queue = append(queue, id)
+	}
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+
+		impact.Affected = append(impact.Affected, g.byID[id])
+		queue = append(queue, dependents[id]...)
+	}
+
+	if len(impact.Unowned) > 0 && !ignoreUnowned {
+		impact.Affected = append([]*Node(nil), g.Nodes...)
+	}
+
+	sortNodes(impact.Affected)
+	sort.Strings(impact.Unowned)
+
+	return impact
+}
+
+func (g *Graph) owner(file string) *Node {
+	var owner *Node
+	for _, n := range g.Nodes {
+		var owns bool
+		switch {
+		case n.Dir == "":
+		case n.Kind == KindPackage:
+			owns = file == n.Dir || strings.HasPrefix(file, n.Dir+string(filepath.Separator))
+		case n.Kind == KindGoPackage:
+			// Go packages don't include their subdirectories.
+			owns = filepath.Dir(file) == n.Dir
+		}
+
+		if owns {
+			if owner == nil || len(n.Dir) > len(owner.Dir) {
+				owner = n
+			}
+		}
+	}
+	return owner
+}
+
+func sortNodes(nodes []*Node) {
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+}
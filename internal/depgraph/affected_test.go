@@ -0,0 +1,142 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package depgraph
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"namespacelabs.dev/foundation/schema"
+)
+
+func TestAffectedBy(t *testing.T) {
+	b := &builder{g: &Graph{byID: map[string]*Node{}}, edges: map[Edge]struct{}{}}
+
+	pkg := func(name, dir string) string {
+		return b.addNode(KindPackage, schema.MakePackageSingleRef(schema.PackageName(name)), dir)
+	}
+
+	lib := pkg("example.com/lib", "/ws/lib")
+	libNode := b.addNode(KindNode, schema.MakePackageSingleRef("example.com/lib"), "")
+	b.addEdge(libNode, lib, "defined_in")
+
+	srvPkg := pkg("example.com/server", "/ws/server")
+	srv := b.addNode(KindServer, schema.MakePackageSingleRef("example.com/server"), "")
+	b.addEdge(srv, srvPkg, "defined_in")
+	b.addEdge(srv, libNode, "import")
+
+	test := b.addNode(KindTest, schema.MakePackageRef("example.com/server", "startup"), "")
+	b.addEdge(test, srvPkg, "defined_in")
+	b.addEdge(test, srv, "server_under_test")
+
+	nested := pkg("example.com/server/other", "/ws/server/other")
+	other := b.addNode(KindServer, schema.MakePackageSingleRef("example.com/server/other"), "")
+	b.addEdge(other, nested, "defined_in")
+
+	ids := func(nodes []*Node) []string {
+		var ids []string
+		for _, n := range nodes {
+			ids = append(ids, n.ID)
+		}
+		return ids
+	}
+
+	impact := b.g.AffectedBy([]string{"/ws/lib/main.go", "/ws/README.md"}, true)
+
+	if d := cmp.Diff([]string{lib}, ids(impact.Changed)); d != "" {
+		t.Errorf("unexpected changed packages (-want +got):\n%s", d)
+	}
+
+	if d := cmp.Diff([]string{libNode, lib, srv, test}, ids(impact.Affected)); d != "" {
+		t.Errorf("unexpected affected nodes (-want +got):\n%s", d)
+	}
+
+	if d := cmp.Diff([]string{"/ws/README.md"}, impact.Unowned); d != "" {
+		t.Errorf("unexpected unowned files (-want +got):\n%s", d)
+	}
+
+	// Unless ignored, unowned files affect everything.
+	impact = b.g.AffectedBy([]string{"/ws/lib/main.go", "/ws/go.mod"}, false)
+	if d := cmp.Diff([]string{libNode, lib, srvPkg, nested, srv, other, test}, ids(impact.Affected)); d != "" {
+		t.Errorf("unexpected affected nodes (-want +got):\n%s", d)
+	}
+
+	// Files are attributed to the most specific package.
+	impact = b.g.AffectedBy([]string{"/ws/server/other/main.go"}, false)
+	if d := cmp.Diff([]string{nested, other}, ids(impact.Affected)); d != "" {
+		t.Errorf("unexpected affected nodes (-want +got):\n%s", d)
+	}
+}
+
+func TestAffectedByGoImports(t *testing.T) {
+	ws := t.TempDir()
+
+	write := func(rel, contents string) {
+		p := filepath.Join(ws, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("go.mod", "module example.com/ws\n")
+	write("server/main.go", "package main\n\nimport (\n\t\"fmt\"\n\n\t\"example.com/ws/lib/util\"\n)\n")
+	write("server/main_test.go", "package main\n\nimport _ \"example.com/ws/lib/testonly\"\n")
+	write("lib/util/util.go", "package util\n\nimport _ \"example.com/ws/lib/base\"\n")
+	write("lib/base/base.go", "package base\n")
+	write("lib/base/nested/nested.go", "package nested\n")
+	write("lib/testonly/testonly.go", "package testonly\n")
+	write("other/main.go", "package main\n\nimport _ \"example.com/ws/lib/unused\"\n")
+
+	b := &builder{g: &Graph{byID: map[string]*Node{}}, edges: map[Edge]struct{}{}}
+
+	srvPkg := b.addNode(KindPackage, schema.MakePackageSingleRef("example.com/ws/server"), filepath.Join(ws, "server"))
+	srv := b.addNode(KindServer, schema.MakePackageSingleRef("example.com/ws/server"), "")
+	b.addEdge(srv, srvPkg, "defined_in")
+
+	// Imports packages which don't exist.
+	b.addNode(KindPackage, schema.MakePackageSingleRef("example.com/ws/other"), filepath.Join(ws, "other"))
+
+	b.addGoImports()
+
+	util := nodeID(KindGoPackage, "example.com/ws/lib/util")
+	base := nodeID(KindGoPackage, "example.com/ws/lib/base")
+
+	if d := cmp.Diff([]Edge{
+		{From: srv, To: srvPkg, Kind: "defined_in"},
+		{From: srvPkg, To: util, Kind: "go_import"},
+		{From: util, To: base, Kind: "go_import"},
+	}, b.g.Edges); d != "" {
+		t.Errorf("unexpected edges (-want +got):\n%s", d)
+	}
+
+	ids := func(nodes []*Node) []string {
+		var ids []string
+		for _, n := range nodes {
+			ids = append(ids, n.ID)
+		}
+		return ids
+	}
+
+	impact := b.g.AffectedBy([]string{filepath.Join(ws, "lib/base/base.go")}, true)
+	if d := cmp.Diff([]string{base, util, srvPkg, srv}, ids(impact.Affected)); d != "" {
+		t.Errorf("unexpected affected nodes (-want +got):\n%s", d)
+	}
+
+	// Neither subdirectories of imported packages, nor packages which are only
+	// imported by tests, or by nothing, are owned.
+	impact = b.g.AffectedBy([]string{
+		filepath.Join(ws, "lib/base/nested/nested.go"),
+		filepath.Join(ws, "lib/testonly/testonly.go"),
+		filepath.Join(ws, "lib/unused/unused.go"),
+	}, true)
+	if len(impact.Affected) != 0 || len(impact.Unowned) != 3 {
+		t.Errorf("expected the files to be unowned, got affected=%v unowned=%v", ids(impact.Affected), impact.Unowned)
+	}
+}
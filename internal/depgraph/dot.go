@@ -0,0 +1,48 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package depgraph
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+var dotShapes = map[Kind]string{
+	KindPackage:   "folder",
+	KindServer:    "box3d",
+	KindNode:      "component",
+	KindTest:      "diamond",
+	KindBinary:    "cds",
+	KindResource:  "cylinder",
+	KindGoPackage: "note",
+}
+
+// WriteDOT writes the graph in Graphviz's DOT language.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	if _, err := fmt.Fprintln(w, "digraph workspace {\n  rankdir=LR;\n  node [fontsize=10];"); err != nil {
+		return err
+	}
+
+	for _, n := range g.Nodes {
+		if _, err := fmt.Fprintf(w, "  %s [label=%s, shape=%s];\n", strconv.Quote(n.ID), strconv.Quote(string(n.Kind)+"\n"+n.Ref), dotShapes[n.Kind]); err != nil {
+			return err
+		}
+	}
+
+	for _, e := range g.Edges {
+		style := ""
+		if e.Kind == "defined_in" {
+			style = ", style=dotted"
+		}
+
+		if _, err := fmt.Fprintf(w, "  %s -> %s [label=%s%s];\n", strconv.Quote(e.From), strconv.Quote(e.To), strconv.Quote(e.Kind), style); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
@@ -0,0 +1,128 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package depgraph
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"namespacelabs.dev/foundation/internal/gosupport"
+)
+
+// addGoImports adds an edge from each workspace package to the Go packages its
+// Go files import, transitively, so that changes to shared Go code affect the
+// packages which use it. Go packages which are not also packages of the
+// workspace are added as KindGoPackage nodes. Only imports within the same Go
+// module are followed; build constraints are ignored, as are test files.
+func (b *builder) addGoImports() {
+	byDir := map[string]string{}
+	var roots []*Node
+	for _, n := range b.g.Nodes {
+		if n.Kind == KindPackage && n.Dir != "" {
+			byDir[n.Dir] = n.ID
+			roots = append(roots, n)
+		}
+	}
+
+	visited := map[string]bool{}
+	for _, root := range roots {
+		mod, gomod, err := gosupport.LookupGoModule(root.Dir)
+		if err != nil {
+			continue // Not Go code.
+		}
+
+		modDir := filepath.Dir(gomod)
+		modPath := mod.Module.Mod.Path
+
+		queue := []string{root.Dir}
+		for len(queue) > 0 {
+			dir := queue[0]
+			queue = queue[1:]
+
+			if visited[dir] {
+				continue
+			}
+			visited[dir] = true
+
+			from, ok := byDir[dir]
+			if !ok {
+				from = b.addGoPackage(modDir, modPath, dir)
+				byDir[dir] = from
+			}
+
+			for _, imp := range goImports(dir) {
+				rel, ok := strings.CutPrefix(imp, modPath+"/")
+				if !ok {
+					continue
+				}
+
+				importedDir := filepath.Join(modDir, filepath.FromSlash(rel))
+				if fi, err := os.Stat(importedDir); err != nil || !fi.IsDir() {
+					continue
+				}
+
+				to, ok := byDir[importedDir]
+				if !ok {
+					to = b.addGoPackage(modDir, modPath, importedDir)
+					byDir[importedDir] = to
+				}
+
+				b.addEdge(from, to, "go_import")
+				queue = append(queue, importedDir)
+			}
+		}
+	}
+}
+
+func (b *builder) addGoPackage(modDir, modPath, dir string) string {
+	importPath := modPath
+	if rel, err := filepath.Rel(modDir, dir); err == nil && rel != "." {
+		importPath += "/" + filepath.ToSlash(rel)
+	}
+
+	id := nodeID(KindGoPackage, importPath)
+	if _, ok := b.g.byID[id]; !ok {
+		n := &Node{ID: id, Kind: KindGoPackage, Ref: importPath, Dir: dir}
+		b.g.Nodes = append(b.g.Nodes, n)
+		b.g.byID[id] = n
+	}
+	return id
+}
+
+// goImports returns the imports of the Go files in dir. Files which fail to
+// parse are skipped, as changes may be work in progress.
+func goImports(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	fset := token.NewFileSet()
+
+	var imports []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+
+		for _, imp := range f.Imports {
+			if path, err := strconv.Unquote(imp.Path.Value); err == nil {
+				imports = append(imports, path)
+			}
+		}
+	}
+
+	return imports
+}
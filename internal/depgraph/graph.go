@@ -0,0 +1,285 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+// Package depgraph computes the dependency graph between the packages of a
+// workspace, and the servers, tests, binaries and resources they define.
+package depgraph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"namespacelabs.dev/foundation/schema"
+	"namespacelabs.dev/foundation/std/pkggraph"
+)
+
+type Kind string
+
+const (
+	KindPackage  Kind = "package"
+	KindServer   Kind = "server"
+	KindNode     Kind = "node" // Services and extensions.
+	KindTest     Kind = "test"
+	KindBinary   Kind = "binary"
+	KindResource Kind = "resource"
+	// Go packages which are imported by the Go code of workspace packages,
+	// but which are not workspace packages themselves.
+	KindGoPackage Kind = "go_package"
+)
+
+type Node struct {
+	ID      string             `json:"id"`
+	Kind    Kind               `json:"kind"`
+	Ref     string             `json:"ref"`
+	Package schema.PackageName `json:"package"`
+	// Set for packages that are part of the workspace (vs a dependency), and
+	// for Go packages.
+	Dir string `json:"dir,omitempty"`
+}
+
+type Edge struct {
+	From string `json:"from"` // The node that depends on `to`.
+	To   string `json:"to"`
+	Kind string `json:"kind"`
+}
+
+type Graph struct {
+	Nodes []*Node `json:"nodes"`
+	Edges []Edge  `json:"edges"`
+
+	byID map[string]*Node
+}
+
+func nodeID(kind Kind, ref string) string {
+	return fmt.Sprintf("%s %s", kind, ref)
+}
+
+// Node returns the node with the specified ID, or nil if it doesn't exist.
+func (g *Graph) Node(id string) *Node {
+	return g.byID[id]
+}
+
+type builder struct {
+	packages map[schema.PackageName]*pkggraph.Package
+	g        *Graph
+	edges    map[Edge]struct{}
+	// References are resolved once all packages are loaded.
+	pending []pendingEdge
+}
+
+type pendingEdge struct {
+	from string
+	to   *schema.PackageRef
+	kind string
+}
+
+// Compute loads each of the specified packages, and transitively, every
+// package they refer to; and returns the resulting graph.
+func Compute(ctx context.Context, pl pkggraph.PackageLoader, roots []schema.PackageName) (*Graph, error) {
+	b := &builder{
+		packages: map[schema.PackageName]*pkggraph.Package{},
+		g:        &Graph{byID: map[string]*Node{}},
+		edges:    map[Edge]struct{}{},
+	}
+
+	queue := append([]schema.PackageName(nil), roots...)
+	for len(queue) > 0 {
+		pkgName := queue[0]
+		queue = queue[1:]
+
+		if _, ok := b.packages[pkgName]; ok {
+			continue
+		}
+
+		pkg, err := pl.LoadByName(ctx, pkgName)
+		if err != nil {
+			return nil, err
+		}
+
+		b.packages[pkgName] = pkg
+
+		before := len(b.pending)
+		b.addPackage(pkg)
+
+		for _, p := range b.pending[before:] {
+			if _, ok := b.packages[p.to.AsPackageName()]; !ok {
+				queue = append(queue, p.to.AsPackageName())
+			}
+		}
+	}
+
+	for _, p := range b.pending {
+		b.addEdge(p.from, b.resolve(p.to), p.kind)
+	}
+
+	b.addGoImports()
+
+	sort.Slice(b.g.Nodes, func(i, j int) bool { return b.g.Nodes[i].ID < b.g.Nodes[j].ID })
+	sort.Slice(b.g.Edges, func(i, j int) bool {
+		if b.g.Edges[i].From != b.g.Edges[j].From {
+			return b.g.Edges[i].From < b.g.Edges[j].From
+		}
+		return b.g.Edges[i].To < b.g.Edges[j].To
+	})
+
+	return b.g, nil
+}
+
+func (b *builder) addNode(kind Kind, ref *schema.PackageRef, dir string) string {
+	id := nodeID(kind, ref.Canonical())
+	if _, ok := b.g.byID[id]; !ok {
+		n := &Node{ID: id, Kind: kind, Ref: ref.Canonical(), Package: ref.AsPackageName(), Dir: dir}
+		b.g.Nodes = append(b.g.Nodes, n)
+		b.g.byID[id] = n
+	}
+	return id
+}
+
+func (b *builder) addEdge(from, to, kind string) {
+	if from == to {
+		return
+	}
+
+	e := Edge{From: from, To: to, Kind: kind}
+	if _, ok := b.edges[e]; !ok {
+		b.edges[e] = struct{}{}
+		b.g.Edges = append(b.g.Edges, e)
+	}
+}
+
+func (b *builder) refer(from string, to *schema.PackageRef, kind string) {
+	if to.GetPackageName() != "" {
+		b.pending = append(b.pending, pendingEdge{from: from, to: to, kind: kind})
+	}
+}
+
+func (b *builder) referPackage(from string, pkg string, kind string) {
+	if pkg != "" {
+		b.refer(from, schema.MakePackageSingleRef(schema.MakePackageName(pkg)), kind)
+	}
+}
+
+// resolve maps a reference to the most specific node that represents it.
+func (b *builder) resolve(ref *schema.PackageRef) string {
+	if ref.Name == "" {
+		for _, kind := range []Kind{KindServer, KindNode} {
+			if id := nodeID(kind, ref.Canonical()); b.g.byID[id] != nil {
+				return id
+			}
+		}
+	} else {
+		for _, kind := range []Kind{KindBinary, KindResource, KindTest} {
+			if id := nodeID(kind, ref.Canonical()); b.g.byID[id] != nil {
+				return id
+			}
+		}
+	}
+
+	return b.addNode(KindPackage, schema.MakePackageSingleRef(ref.AsPackageName()), "")
+}
+
+func (b *builder) addPackage(pkg *pkggraph.Package) {
+	var dir string
+	if !pkg.Location.Module.IsExternal() {
+		dir = pkg.Location.Abs()
+	}
+
+	pkgID := b.addNode(KindPackage, schema.MakePackageSingleRef(pkg.PackageName()), dir)
+
+	// Every entity depends on the package where it's defined.
+	entity := func(kind Kind, ref *schema.PackageRef) string {
+		id := b.addNode(kind, ref, "")
+		b.addEdge(id, pkgID, "defined_in")
+		return id
+	}
+
+	if srv := pkg.Server; srv != nil {
+		id := entity(KindServer, schema.MakePackageSingleRef(pkg.PackageName()))
+
+		for _, imp := range srv.Import {
+			b.referPackage(id, imp, "import")
+		}
+
+		if self := srv.Self; self != nil {
+			b.referContainer(id, self.MainContainer)
+			for _, ctr := range self.Sidecar {
+				b.referContainer(id, ctr)
+			}
+			for _, ctr := range self.InitContainer {
+				b.referContainer(id, ctr)
+			}
+			for _, ext := range self.Extension {
+				b.referPackage(id, ext, "extension")
+			}
+			b.referResourcePack(id, self.ResourcePack)
+		}
+	}
+
+	for _, n := range []*schema.Node{pkg.Extension, pkg.Service} {
+		if n == nil {
+			continue
+		}
+
+		id := entity(KindNode, schema.MakePackageSingleRef(pkg.PackageName()))
+		for _, imp := range n.Import {
+			b.referPackage(id, imp, "import")
+		}
+		for _, ext := range n.Extension {
+			b.referPackage(id, ext, "extension")
+		}
+		b.referResourcePack(id, n.ResourcePack)
+	}
+
+	for _, bin := range pkg.Binaries {
+		id := entity(KindBinary, schema.MakePackageRef(pkg.PackageName(), bin.Name))
+		for _, layer := range bin.GetBuildPlan().GetLayerBuildPlan() {
+			b.refer(id, layer.Binary, "layer")
+		}
+	}
+
+	for _, test := range pkg.Tests {
+		id := entity(KindTest, schema.MakePackageRef(pkg.PackageName(), test.Name))
+		b.refer(id, test.Driver, "driver")
+		for _, srv := range test.ServersUnderTest {
+			b.referPackage(id, srv, "server_under_test")
+		}
+	}
+
+	for _, res := range pkg.Resources {
+		b.addResource(entity, res)
+	}
+
+	for _, prov := range pkg.ResourceProviders {
+		for _, res := range prov.Resources {
+			b.addResource(entity, res)
+		}
+	}
+}
+
+func (b *builder) addResource(entity func(Kind, *schema.PackageRef) string, res pkggraph.ResourceInstance) {
+	id := entity(KindResource, res.ResourceRef)
+
+	b.refer(id, res.Spec.Class.Ref, "class")
+	if prov := res.Spec.Provider; prov != nil && prov.Spec != nil {
+		b.referPackage(id, prov.Spec.PackageName, "provider")
+		b.refer(id, prov.Spec.GetInitializedWith().GetBinaryRef(), "provider")
+	}
+
+	for _, input := range res.Spec.ResourceInputs {
+		b.refer(id, input.ResourceRef, "input")
+	}
+}
+
+func (b *builder) referContainer(from string, ctr *schema.Container) {
+	if ctr != nil {
+		b.refer(from, ctr.BinaryRef, "binary")
+	}
+}
+
+func (b *builder) referResourcePack(from string, pack *schema.ResourcePack) {
+	for _, ref := range pack.GetResourceRef() {
+		b.refer(from, ref, "resource")
+	}
+}
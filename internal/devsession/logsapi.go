@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"google.golang.org/grpc/codes"
@@ -25,23 +27,60 @@ func serveLogs(s sessionLike, w http.ResponseWriter, r *http.Request, serverID s
 			return err
 		}
 
-		refs, err := cluster.ResolveContainers(ctx, server)
+		opts, err := parseLogsQuery(r.URL.Query())
 		if err != nil {
-			return err
+			return rpcerrors.Errorf(codes.InvalidArgument, "%v", err)
 		}
 
-		for _, ref := range refs {
-			if ref.Kind == runtimepb.ContainerKind_PRIMARY {
-				return cluster.Cluster().FetchLogsTo(ctx, ref,
-					runtime.FetchLogsOpts{Follow: true, TailLines: 100},
-					runtime.WriteToWriter(wsWriter))
+		return cluster.FetchServerLogs(ctx, []runtime.Deployable{server}, opts, func(line runtime.ServerLogLine) {
+			if line.Event != runtime.ContainerLogLineEvent_LogLine {
+				return
 			}
-		}
 
-		return rpcerrors.Errorf(codes.FailedPrecondition, "server has no identifiable primary container")
+			if line.Container.GetKind() == runtimepb.ContainerKind_PRIMARY {
+				fmt.Fprintf(wsWriter, "%s\n", line.LogLine)
+			} else {
+				fmt.Fprintf(wsWriter, "%s | %s\n", line.Container.GetHumanReference(), line.LogLine)
+			}
+		})
 	})
 }
 
+func parseLogsQuery(query url.Values) (runtime.ServerLogsOpts, error) {
+	opts := runtime.ServerLogsOpts{
+		Observe: runtime.ObserveOpts{
+			Sidecars:       query.Get("sidecars") == "true",
+			ContainerNames: query["container"],
+		},
+		Fetch: runtime.FetchLogsOpts{Follow: true, TailLines: 100},
+	}
+
+	var err error
+	now := time.Now()
+	if opts.Fetch.Since, err = runtime.ParseLogTime(query.Get("since"), now); err != nil {
+		return opts, err
+	}
+
+	if opts.Fetch.Until, err = runtime.ParseLogTime(query.Get("until"), now); err != nil {
+		return opts, err
+	}
+
+	if opts.Fetch.Include, err = runtime.CompileLogPatterns(query["include"]); err != nil {
+		return opts, err
+	}
+
+	if opts.Fetch.Exclude, err = runtime.CompileLogPatterns(query["exclude"]); err != nil {
+		return opts, err
+	}
+
+	if !opts.Fetch.Since.IsZero() {
+		// An explicit range replaces the default tail.
+		opts.Fetch.TailLines = 0
+	}
+
+	return opts, nil
+}
+
 func serveTaskOutput(s *Session, w http.ResponseWriter, r *http.Request, taskID, name string) {
 	copyStream(fmt.Sprintf("task.output[%s]", name), w, r, func(ctx context.Context) (io.ReadCloser, error) {
 		return s.TaskLogByName(taskID, name), nil
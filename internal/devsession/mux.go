@@ -5,79 +5,308 @@
 package devsession
 
 import (
-	"bytes"
 	"context"
+	"fmt"
+	"io"
 	"io/fs"
 	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/gorilla/mux"
 	"namespacelabs.dev/foundation/internal/artifacts/oci"
 	"namespacelabs.dev/foundation/internal/bytestream"
 	"namespacelabs.dev/foundation/internal/compute"
+	"namespacelabs.dev/foundation/internal/console"
+	"namespacelabs.dev/foundation/internal/fnerrors"
 	"namespacelabs.dev/foundation/internal/fnfs"
+	"namespacelabs.dev/foundation/internal/planning"
+	"namespacelabs.dev/foundation/internal/planning/deploy"
+	"namespacelabs.dev/foundation/internal/staticfiles"
+	"namespacelabs.dev/foundation/internal/workspace/dirs"
 	"namespacelabs.dev/foundation/schema"
+	"namespacelabs.dev/foundation/std/pkggraph"
 	"namespacelabs.dev/foundation/std/tasks"
 )
 
-// serveFS returns a Computable[*mux.Router]. If `spa` is true (i.e. single page app),
-// and an index.html is present, it is served on all paths (except the ones for which)
-// real files exist.
-func serveFS(image compute.Computable[oci.Image], pathPrefix string, spa bool) compute.Computable[*mux.Router] {
-	return &fsServing{image: image, spa: spa, pathPrefix: pathPrefix}
+// The files of each static files server of the session are served under
+// this prefix, followed by the server's id.
+const staticFilesPrefix = "/static/"
+
+// serveFS returns a Computable[*mux.Router] which serves the files of the
+// server's image, as built for the deployment plan, the way a static files
+// server built with the same configuration would.
+func serveFS(plan compute.Computable[*deploy.Plan], server schema.PackageName, config *schema.ImageBuildPlan_StaticFilesServer) compute.Computable[*mux.Router] {
+	return &fsServing{plan: plan, server: server, config: config}
 }
 
 type fsServing struct {
-	image      compute.Computable[oci.Image]
-	spa        bool
-	pathPrefix string
+	plan   compute.Computable[*deploy.Plan]
+	server schema.PackageName
+	config *schema.ImageBuildPlan_StaticFilesServer
 
 	compute.LocalScoped[*mux.Router]
 }
 
 func (m *fsServing) Action() *tasks.ActionEvent { return tasks.Action("web.mux") }
 func (m *fsServing) Inputs() *compute.In {
-	return compute.Inputs().Computable("image", m.image).Bool("spa", m.spa)
+	return compute.Inputs().Computable("plan", m.plan).Stringer("server", m.server).Proto("config", m.config)
+}
+func (m *fsServing) Output() compute.Output {
+	return compute.Output{NotCacheable: true}
 }
 func (m *fsServing) Compute(ctx context.Context, deps compute.Resolved) (*mux.Router, error) {
-	image, _ := compute.GetDep(deps, m.image, "image")
+	plan, _ := compute.GetDep(deps, m.plan, "plan")
+
+	images, ok := plan.Value.ServerImages[m.server]
+	if !ok || images.BinaryImage == nil {
+		// E.g. prebuilt images, which are not available locally.
+		fmt.Fprintf(console.Debug(ctx), "devworkflow: %s: no image to serve files from\n", m.server)
+		return nil, nil
+	}
+
+	resolvable, err := compute.GetValue(ctx, images.BinaryImage)
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := anyPlatform(resolvable)
+	if err != nil {
+		return nil, err
+	}
+
+	digest, err := image.Digest()
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := staticfiles.Parse(m.config)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := extractFiles(ctx, image, digest, config.Dir)
+	if err != nil {
+		return nil, err
+	}
 
-	return muxFromFS(ctx, oci.ImageAsFS(image.Value), image.Digest, image.Completed, m.spa, m.pathPrefix)
+	return muxFromFS(os.DirFS(dir), digest.String(), plan.Completed, config), nil
 }
 
-func muxFromFS(ctx context.Context, fsys fs.FS, d schema.Digest, ts time.Time, spa bool, pathPrefix string) (*mux.Router, error) {
+func muxFromFS(fsys fs.FS, etag string, ts time.Time, config *staticfiles.Config) *mux.Router {
 	r := mux.NewRouter()
+	r.PathPrefix("/").Handler(staticfiles.NewHandler(config, fsys, etag, ts))
+	return r
+}
+
+// anyPlatform returns the image of any platform, as they all have the same
+// files.
+func anyPlatform(r oci.ResolvableImage) (oci.Image, error) {
+	if image, err := r.Image(); err == nil {
+		return image, nil
+	}
 
-	if err := fnfs.VisitFiles(ctx, fsys, func(path string, blob bytestream.ByteStream, _ fs.DirEntry) error {
-		var route *mux.Route
+	index, err := r.ImageIndex()
+	if err != nil {
+		return nil, err
+	}
 
-		path = path[len(pathPrefix):]
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
 
-		if path == "index.html" {
-			if spa {
-				route = r.PathPrefix("/")
-			} else {
-				route = r.Path("/")
-			}
-		} else {
-			route = r.Path("/" + path)
+	for _, desc := range manifest.Manifests {
+		// Skip attestations and other artifacts.
+		if desc.Platform == nil || desc.Platform.OS == "unknown" {
+			continue
 		}
 
-		contents, err := bytestream.ReadAll(blob)
-		if err != nil {
-			return err
+		return index.Image(desc.Digest)
+	}
+
+	return nil, fnerrors.BadDataError("image index has no images")
+}
+
+// extractFiles writes the files under dir of the image to a directory which
+// is keyed by the image's digest, so they're only extracted once and are not
+// kept in memory.
+func extractFiles(ctx context.Context, image oci.Image, digest v1.Hash, dir string) (string, error) {
+	parent, err := dirs.Ensure(dirs.Subdir(filepath.Join("static-files", digest.Algorithm)))
+	if err != nil {
+		return "", err
+	}
+
+	target := filepath.Join(parent, digest.Hex)
+	if _, err := os.Stat(target); err == nil {
+		return target, nil
+	}
+
+	tmp, err := os.MkdirTemp(parent, ".extract-*")
+	if err != nil {
+		return "", err
+	}
+
+	defer os.RemoveAll(tmp) // Nothing left to remove once renamed.
+
+	root := strings.TrimPrefix(strings.TrimSuffix(dir, "/")+"/", "/")
+	if err := fnfs.VisitFiles(ctx, oci.ImageAsFS(image), func(p string, blob bytestream.ByteStream, _ fs.DirEntry) error {
+		if p = strings.TrimPrefix(path.Clean("/"+p), "/"); !strings.HasPrefix(p, root) {
+			return nil
 		}
 
-		route.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
-			if d.IsSet() {
-				req.Header.Add("ETag", d.String())
-			}
-			http.ServeContent(rw, req, path, ts, bytes.NewReader(contents))
-		})
-		return nil
+		return writeFile(filepath.Join(tmp, filepath.FromSlash(p)), blob)
 	}); err != nil {
+		return "", fnerrors.Newf("failed to extract static files: %w", err)
+	}
+
+	if err := os.Rename(tmp, target); err != nil {
+		if _, statErr := os.Stat(target); statErr == nil {
+			return target, nil // Extracted concurrently.
+		}
+
+		return "", err
+	}
+
+	return target, nil
+}
+
+func writeFile(dst string, blob bytestream.ByteStream) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	r, err := blob.Reader()
+	if err != nil {
+		return err
+	}
+
+	defer r.Close()
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// prepareStaticFiles returns a Computable[*mux.Router] which serves the files
+// of the server's image in the plan, if it's a static files server; or nil
+// otherwise.
+func prepareStaticFiles(ctx context.Context, server planning.PlannedServer, plan compute.Computable[*deploy.Plan]) (compute.Computable[*mux.Router], error) {
+	binRef := server.MergedFragment.GetMainContainer().GetBinaryRef()
+	if binRef == nil {
+		return nil, nil
+	}
+
+	_, bin, err := pkggraph.LoadBinary(ctx, server.SealedContext(), binRef)
+	if err != nil {
 		return nil, err
 	}
 
-	return r, nil
+	var config *schema.ImageBuildPlan_StaticFilesServer
+	for _, layer := range bin.GetBuildPlan().GetLayerBuildPlan() {
+		if layer.GetStaticFilesServer() != nil {
+			config = layer.GetStaticFilesServer()
+		}
+	}
+
+	if config == nil {
+		return nil, nil
+	}
+
+	return serveFS(plan, server.PackageName(), config), nil
+}
+
+// staticFiles routes requests under staticFilesPrefix to the corresponding
+// server's files.
+type staticFiles struct {
+	mu      sync.Mutex
+	routers map[string]*mux.Router // By server id.
+}
+
+func (sf *staticFiles) set(serverID string, r *mux.Router) {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+
+	if r == nil {
+		delete(sf.routers, serverID)
+		return
+	}
+
+	if sf.routers == nil {
+		sf.routers = map[string]*mux.Router{}
+	}
+
+	sf.routers[serverID] = r
+}
+
+func (sf *staticFiles) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	serverID, rest, _ := strings.Cut(strings.TrimPrefix(req.URL.Path, staticFilesPrefix), "/")
+
+	sf.mu.Lock()
+	r := sf.routers[serverID]
+	sf.mu.Unlock()
+
+	if r == nil {
+		http.NotFound(rw, req)
+		return
+	}
+
+	if rest == "" && !strings.HasSuffix(req.URL.Path, "/") {
+		http.Redirect(rw, req, req.URL.Path+"/", http.StatusMovedPermanently)
+		return
+	}
+
+	prefix := staticFilesPrefix + serverID
+	http.StripPrefix(prefix, r).ServeHTTP(prefixedRedirects{rw, prefix}, req)
+}
+
+// prefixedRedirects keeps the handler's redirects, e.g. to the base path,
+// under the prefix which was stripped from the request.
+type prefixedRedirects struct {
+	http.ResponseWriter
+	prefix string
+}
+
+func (w prefixedRedirects) WriteHeader(code int) {
+	if loc := w.Header().Get("Location"); code >= 300 && code < 400 && strings.HasPrefix(loc, "/") {
+		w.Header().Set("Location", w.prefix+loc)
+	}
+
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// serveStaticFiles keeps serving the latest build of a static files server.
+type serveStaticFiles struct {
+	session *Session
+	server  *schema.Server
+	router  compute.Computable[*mux.Router]
+}
+
+func (s *serveStaticFiles) Inputs() *compute.In {
+	return compute.Inputs().Computable("router", s.router)
+}
+
+func (s *serveStaticFiles) Updated(ctx context.Context, r compute.Resolved) error {
+	s.session.static.set(s.server.Id, compute.MustGetDepValue(r, s.router, "router"))
+
+	fmt.Fprintf(console.Debug(ctx), "devworkflow: serving the files of %s at %s%s/\n", s.server.PackageName, staticFilesPrefix, s.server.Id)
+	return nil
+}
+
+func (s *serveStaticFiles) Cleanup(ctx context.Context) error {
+	s.session.static.set(s.server.Id, nil)
+	return nil
 }
@@ -0,0 +1,84 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package devsession
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"namespacelabs.dev/foundation/internal/fnfs/memfs"
+	"namespacelabs.dev/foundation/internal/staticfiles"
+	"namespacelabs.dev/foundation/schema"
+)
+
+func TestStaticFiles(t *testing.T) {
+	config, err := staticfiles.Parse(&schema.ImageBuildPlan_StaticFilesServer{
+		Dir:                "/app",
+		BasePath:           "/web",
+		DisableSpaFallback: true,
+		Headers:            map[string]string{"X-Frame-Options": "DENY"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fsys memfs.FS
+	fsys.Add("app/index.html", []byte("index"))
+	fsys.Add("app/main.js", []byte("js"))
+
+	router := muxFromFS(&fsys, "", time.Unix(1, 0), config)
+
+	var sf staticFiles
+	sf.set("abc", router)
+
+	r := mux.NewRouter()
+	r.PathPrefix(staticFilesPrefix).Handler(&sf)
+
+	for _, test := range []struct {
+		path     string
+		code     int
+		body     string
+		location string
+	}{
+		{path: "/static/abc/web/", code: http.StatusOK, body: "index"},
+		{path: "/static/abc/web/main.js", code: http.StatusOK, body: "js"},
+		{path: "/static/abc/web/missing", code: http.StatusNotFound},
+		{path: "/static/abc/main.js", code: http.StatusNotFound},
+		{path: "/static/abc/web", code: http.StatusMovedPermanently, location: "/static/abc/web/"},
+		{path: "/static/abc", code: http.StatusMovedPermanently, location: "/static/abc/"},
+		{path: "/static/other/web/", code: http.StatusNotFound},
+	} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, test.path, nil))
+
+		if rec.Code != test.code {
+			t.Errorf("%s: expected %d, got %d", test.path, test.code, rec.Code)
+			continue
+		}
+
+		if test.body != "" && rec.Body.String() != test.body {
+			t.Errorf("%s: expected body %q, got %q", test.path, test.body, rec.Body.String())
+		}
+
+		if test.location != "" && rec.Header().Get("Location") != test.location {
+			t.Errorf("%s: expected a redirect to %q, got %q", test.path, test.location, rec.Header().Get("Location"))
+		}
+
+		if test.code == http.StatusOK && rec.Header().Get("X-Frame-Options") != "DENY" {
+			t.Errorf("%s: expected the configured headers", test.path)
+		}
+	}
+
+	sf.set("abc", nil)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/static/abc/web/", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected files not to be served once the server is removed, got %d", rec.Code)
+	}
+}
@@ -41,6 +41,7 @@ type Session struct {
 	obs           *Observers
 	sink          *tasks.StatefulSink
 	availableEnvs []*schema.Environment
+	static        staticFiles
 
 	mu        sync.Mutex // Protect below.
 	requested struct {
@@ -11,6 +11,7 @@ import (
 
 	"namespacelabs.dev/foundation/internal/compute"
 	"namespacelabs.dev/foundation/internal/console"
+	"namespacelabs.dev/foundation/internal/executor"
 	"namespacelabs.dev/foundation/internal/fnerrors"
 	"namespacelabs.dev/foundation/internal/integrations"
 	"namespacelabs.dev/foundation/internal/planning"
@@ -144,6 +145,18 @@ func (do *buildAndDeploy) Updated(ctx context.Context, r compute.Resolved) error
 			return err
 		}
 
+		var staticFiles []*serveStaticFiles
+		for _, srv := range focus {
+			router, err := prepareStaticFiles(ctx, srv, plan)
+			if err != nil {
+				return err
+			}
+
+			if router != nil {
+				staticFiles = append(staticFiles, &serveStaticFiles{session: do.session, server: srv.Proto(), router: router})
+			}
+		}
+
 		// The actual build + deploy is deferred into a separate Continuously() call, which
 		// reacts to changes to the dependencies of build/deploy (e.g. sources). We can't
 		// block here either or else we won't have updates to the package graph to be
@@ -163,9 +176,22 @@ func (do *buildAndDeploy) Updated(ctx context.Context, r compute.Resolved) error
 		}
 		cancel := compute.SpawnCancelableOnContinuously(ctx, func(ctx context.Context) error {
 			defer close(done)
-			return compute.Continuously(ctx,
-				newUpdateCluster(snapshot.Env(), do.cluster, stack.Proto(), do.serverPackages, observers, plan, do.portForward),
-				transformError)
+
+			eg := executor.New(ctx, "devworkflow.deploy")
+			eg.Go(func(ctx context.Context) error {
+				return compute.Continuously(ctx,
+					newUpdateCluster(snapshot.Env(), do.cluster, stack.Proto(), do.serverPackages, observers, plan, do.portForward),
+					transformError)
+			})
+
+			// Static files servers are also served locally, as they'd be once deployed.
+			for _, sf := range staticFiles {
+				eg.Go(func(ctx context.Context) error {
+					return compute.Continuously(ctx, sf, transformError)
+				})
+			}
+
+			return eg.Wait()
 		})
 
 		do.cancelRunning = func() {
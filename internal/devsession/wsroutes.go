@@ -30,6 +30,7 @@ func RegisterEndpoints(s *Session, r *mux.Router) {
 		v := mux.Vars(r)
 		serveTaskOutput(s, rw, r, v["id"], v["name"])
 	})
+	r.PathPrefix(staticFilesPrefix).Handler(&s.static)
 }
 
 type sessionLike interface {
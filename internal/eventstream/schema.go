@@ -0,0 +1,93 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+// Package eventstream emits a machine-readable stream of what a command is
+// doing, as newline-delimited JSON (one Event per line), so that tools (CI
+// wrappers, IDE plugins, etc) don't need to scrape the human-oriented console.
+//
+// Each event carries the schema version it was produced with in "schema".
+// Within a version, fields are only ever added, never removed or repurposed;
+// consumers should ignore fields and event types they don't know about.
+//
+// Event types in version 1:
+//
+//   - "action.started", "action.done": an action started, or completed. The
+//     "action" field describes it; "error" is set if it failed.
+//   - "action.instant": a point-in-time event, also described in "action".
+//   - "action.attachments": an action produced intermediate results, in
+//     "attachments".
+//   - "log": lines of output, in "log".
+//   - "deploy": a deployment progress event, in "deploy"; it is the JSON
+//     encoding of namespacelabs.dev/foundation/schema/orchestration.Event.
+//   - "test.result": a test completed, in "test"; it is the JSON encoding of
+//     namespacelabs.dev/foundation/schema/storage.TestBundle (without logs).
+//   - "invocation.done": always the last event; "error" is set if the command
+//     failed.
+package eventstream
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	// SchemaVersion is bumped whenever an incompatible change is made to the
+	// event schema.
+	SchemaVersion = 1
+
+	FormatJSON = "json"
+)
+
+const (
+	TypeActionStarted     = "action.started"
+	TypeActionDone        = "action.done"
+	TypeActionInstant     = "action.instant"
+	TypeActionAttachments = "action.attachments"
+	TypeLog               = "log"
+	TypeDeploy            = "deploy"
+	TypeTestResult        = "test.result"
+	TypeInvocationDone    = "invocation.done"
+)
+
+type Event struct {
+	Schema    int       `json:"schema"`
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"ts"`
+
+	Action      *Action         `json:"action,omitempty"`
+	Attachments *Attachments    `json:"attachments,omitempty"`
+	Log         *Log            `json:"log,omitempty"`
+	Deploy      json.RawMessage `json:"deploy,omitempty"`
+	Test        json.RawMessage `json:"test,omitempty"`
+	Error       string          `json:"error,omitempty"`
+}
+
+type Action struct {
+	ActionID      string         `json:"action_id"`
+	ParentID      string         `json:"parent_id,omitempty"`
+	AnchorID      string         `json:"anchor_id,omitempty"`
+	Name          string         `json:"name"`
+	Category      string         `json:"category,omitempty"`
+	HumanReadable string         `json:"human_readable,omitempty"`
+	Scope         []string       `json:"scope,omitempty"`
+	Level         int            `json:"level"`
+	Created       time.Time      `json:"created"`
+	Started       *time.Time     `json:"started,omitempty"`
+	Completed     *time.Time     `json:"completed,omitempty"`
+	Arguments     map[string]any `json:"arguments,omitempty"`
+	Error         string         `json:"error,omitempty"`
+}
+
+type Attachments struct {
+	ActionID string         `json:"action_id"`
+	Results  map[string]any `json:"results,omitempty"`
+}
+
+type Log struct {
+	// The name of the output buffer, e.g. "stdout", or the name of a server.
+	Name     string   `json:"name"`
+	Category string   `json:"category,omitempty"`
+	ActionID string   `json:"action_id,omitempty"`
+	Lines    []string `json:"lines"`
+}
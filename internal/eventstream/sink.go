@@ -0,0 +1,257 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package eventstream
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/schema/orchestration"
+	"namespacelabs.dev/foundation/schema/storage"
+	"namespacelabs.dev/foundation/std/tasks"
+	"namespacelabs.dev/foundation/std/tasks/idtypes"
+)
+
+var (
+	OutputFormat string
+	OutputPath   string
+)
+
+func SetupFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&OutputFormat, "output_events", "", "If set to json, streams a newline-delimited JSON event for each action, log line, deployment and test result.")
+	flags.StringVar(&OutputPath, "output_events_to", "", "Where to write the event stream to; defaults to stdout.")
+}
+
+var _ tasks.ActionSink = &Sink{}
+
+// Sink forwards all events to its parent, while also writing them out as
+// JSON events.
+type Sink struct {
+	parent tasks.ActionSink
+
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func NewSink(parent tasks.ActionSink, w io.Writer) *Sink {
+	return &Sink{parent: parent, enc: json.NewEncoder(w)}
+}
+
+// ValidateFormat returns an error if OutputFormat is set to an unsupported value.
+func ValidateFormat() error {
+	switch OutputFormat {
+	case "", FormatJSON:
+		return nil
+	default:
+		return fnerrors.BadInputError("--output_events: unsupported format %q (only %q is supported)", OutputFormat, FormatJSON)
+	}
+}
+
+func (s *Sink) emit(ev Event) {
+	ev.Schema = SchemaVersion
+	if ev.Timestamp.IsZero() {
+		ev.Timestamp = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Errors are ignored, as failing to produce events should not fail the command.
+	_ = s.enc.Encode(ev)
+}
+
+func (s *Sink) Waiting(ra *tasks.RunningAction) {
+	if s.parent != nil {
+		s.parent.Waiting(ra)
+	}
+}
+
+func (s *Sink) Started(ra *tasks.RunningAction) {
+	if s.parent != nil {
+		s.parent.Started(ra)
+	}
+
+	s.emit(Event{Type: TypeActionStarted, Timestamp: ra.Data.Started, Action: makeAction(ra.Data)})
+}
+
+func (s *Sink) Done(ra *tasks.RunningAction) {
+	if s.parent != nil {
+		s.parent.Done(ra)
+	}
+
+	s.emit(Event{Type: TypeActionDone, Timestamp: ra.Data.Completed, Action: makeAction(ra.Data), Error: errorString(ra.Data.Err)})
+}
+
+func (s *Sink) Instant(ev *tasks.EventData) {
+	if s.parent != nil {
+		s.parent.Instant(ev)
+	}
+
+	s.emit(Event{Type: TypeActionInstant, Timestamp: ev.Created, Action: makeAction(*ev)})
+}
+
+func (s *Sink) AttachmentsUpdated(id tasks.ActionID, data *tasks.ResultData) {
+	if s.parent != nil {
+		s.parent.AttachmentsUpdated(id, data)
+	}
+
+	if data == nil || len(data.Items) == 0 {
+		return
+	}
+
+	s.emit(Event{Type: TypeActionAttachments, Attachments: &Attachments{
+		ActionID: id.String(),
+		Results:  makeArgs(data.Items),
+	}})
+}
+
+func (s *Sink) Output(name, contentType string, outputType idtypes.CatOutputType) io.Writer {
+	if s.parent != nil {
+		return s.parent.Output(name, contentType, outputType)
+	}
+	return nil
+}
+
+// WriteLines is called by the console with each line of output, in addition
+// to the console sink itself.
+func (s *Sink) WriteLines(_ idtypes.IdAndHash, name string, cat idtypes.CatOutputType, actionID tasks.ActionID, ts time.Time, lines [][]byte) {
+	strLines := make([]string, len(lines))
+	for k, line := range lines {
+		strLines[k] = string(line)
+	}
+
+	s.emit(Event{Type: TypeLog, Timestamp: ts, Log: &Log{
+		Name:     name,
+		Category: string(cat),
+		ActionID: actionID.String(),
+		Lines:    strLines,
+	}})
+}
+
+func (s *Sink) Unwrap() tasks.ActionSink {
+	return s.parent
+}
+
+// Close emits the final event of the stream.
+func (s *Sink) Close(err error) {
+	s.emit(Event{Type: TypeInvocationDone, Error: errorString(err)})
+}
+
+// DeployEvent emits a deployment event, if an event stream was requested.
+func DeployEvent(ctx context.Context, ev *orchestration.Event) {
+	if s := sinkFrom(ctx); s != nil {
+		if raw := marshalProto(ev); raw != nil {
+			s.emit(Event{Type: TypeDeploy, Deploy: raw})
+		}
+	}
+}
+
+// TestResult emits the result of a test, if an event stream was requested.
+func TestResult(ctx context.Context, summary *storage.TestBundle) {
+	if s := sinkFrom(ctx); s != nil {
+		if raw := marshalProto(summary); raw != nil {
+			s.emit(Event{Type: TypeTestResult, Test: raw})
+		}
+	}
+}
+
+func sinkFrom(ctx context.Context) *Sink {
+	for sink := tasks.SinkFrom(ctx); sink != nil; {
+		if s, ok := sink.(*Sink); ok {
+			return s
+		}
+
+		unwrap, ok := sink.(interface{ Unwrap() tasks.ActionSink })
+		if !ok {
+			return nil
+		}
+
+		sink = unwrap.Unwrap()
+	}
+
+	return nil
+}
+
+func marshalProto(msg proto.Message) json.RawMessage {
+	raw, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	if err != nil {
+		return nil
+	}
+	return raw
+}
+
+func makeAction(data tasks.EventData) *Action {
+	action := &Action{
+		ActionID:      data.ActionID.String(),
+		ParentID:      data.ParentID.String(),
+		AnchorID:      data.AnchorID.String(),
+		Name:          data.Name,
+		Category:      data.Category,
+		HumanReadable: data.HumanReadable,
+		Level:         data.Level,
+		Created:       data.Created,
+		Error:         errorString(data.Err),
+	}
+
+	if data.Scope.Len() > 0 {
+		action.Scope = data.Scope.PackageNamesAsString()
+	}
+
+	if !data.Started.IsZero() {
+		started := data.Started
+		action.Started = &started
+	}
+
+	if !data.Completed.IsZero() {
+		completed := data.Completed
+		action.Completed = &completed
+	}
+
+	if !data.HasPrivateData {
+		var args []*tasks.ActionArgument
+		for k := range data.Arguments {
+			args = append(args, &data.Arguments[k])
+		}
+		action.Arguments = makeArgs(args)
+	}
+
+	return action
+}
+
+func makeArgs(items []*tasks.ActionArgument) map[string]any {
+	var args map[string]any
+	for _, arg := range items {
+		var value any
+		if msg, ok := arg.Msg.(proto.Message); ok {
+			value = marshalProto(msg)
+		} else {
+			value = arg.Msg
+		}
+
+		// Skip values which we don't know how to serialize.
+		if _, err := json.Marshal(value); err != nil {
+			continue
+		}
+
+		if args == nil {
+			args = map[string]any{}
+		}
+		args[arg.Name] = value
+	}
+	return args
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
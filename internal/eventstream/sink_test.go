@@ -0,0 +1,75 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package eventstream
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"namespacelabs.dev/foundation/schema/orchestration"
+	"namespacelabs.dev/foundation/std/tasks"
+)
+
+func TestEventStream(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewSink(tasks.NullSink(), &buf)
+	ctx := tasks.WithSink(context.Background(), sink)
+
+	if err := tasks.Action("test.action").Arg("key", "value").Run(ctx, func(ctx context.Context) error {
+		DeployEvent(ctx, &orchestration.Event{ResourceId: "server", Ready: orchestration.Event_READY})
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	sink.Close(nil)
+
+	var types []string
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var ev Event
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			t.Fatalf("invalid event %q: %v", scanner.Text(), err)
+		}
+
+		if ev.Schema != SchemaVersion {
+			t.Errorf("expected schema %d, got %d", SchemaVersion, ev.Schema)
+		}
+
+		switch ev.Type {
+		case TypeActionDone:
+			if ev.Action.Name != "test.action" || ev.Action.Arguments["key"] != "value" {
+				t.Errorf("unexpected action: %+v", ev.Action)
+			}
+
+		case TypeDeploy:
+			var deploy map[string]any
+			if err := json.Unmarshal(ev.Deploy, &deploy); err != nil {
+				t.Fatal(err)
+			}
+
+			if deploy["resource_id"] != "server" {
+				t.Errorf("unexpected deploy event: %s", ev.Deploy)
+			}
+		}
+
+		types = append(types, ev.Type)
+	}
+
+	want := []string{TypeActionStarted, TypeDeploy, TypeActionDone, TypeInvocationDone}
+	if len(types) != len(want) {
+		t.Fatalf("expected %v, got %v", want, types)
+	}
+
+	for k := range want {
+		if types[k] != want[k] {
+			t.Errorf("expected %v, got %v", want, types)
+			break
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+// Remediation guides for each of the stable error codes in fnerrors.
+package catalog
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+
+	"namespacelabs.dev/foundation/internal/fnerrors"
+)
+
+var (
+	//go:embed guides/*.md
+	guides embed.FS
+)
+
+type Entry struct {
+	Code  fnerrors.Code
+	Title string
+	// Markdown remediation guide, excluding the title.
+	Guide string
+}
+
+// Lookup returns the entry for the specified code; codes are matched case-insensitively.
+func Lookup(code string) (Entry, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+
+	contents, err := guides.ReadFile(fmt.Sprintf("guides/%s.md", code))
+	if err != nil {
+		return Entry{}, false
+	}
+
+	title, guide, _ := strings.Cut(string(contents), "\n")
+
+	return Entry{
+		Code:  fnerrors.Code(code),
+		Title: strings.TrimSpace(strings.TrimPrefix(title, "#")),
+		Guide: strings.TrimSpace(guide),
+	}, true
+}
+
+// All returns an entry for each known code, in code order.
+func All() []Entry {
+	var entries []Entry
+	for _, code := range fnerrors.AllCodes {
+		if entry, ok := Lookup(string(code)); ok {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
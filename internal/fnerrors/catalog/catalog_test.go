@@ -0,0 +1,56 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package catalog
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"namespacelabs.dev/foundation/internal/fnerrors"
+)
+
+func TestEveryCodeHasAGuide(t *testing.T) {
+	known := map[fnerrors.Code]bool{}
+	for _, code := range fnerrors.AllCodes {
+		if known[code] {
+			t.Errorf("%s: code is declared more than once", code)
+		}
+		known[code] = true
+
+		entry, ok := Lookup(string(code))
+		if !ok {
+			t.Errorf("%s: missing a remediation guide", code)
+			continue
+		}
+
+		if entry.Title == "" || entry.Guide == "" {
+			t.Errorf("%s: guide must have a title and a body", code)
+		}
+	}
+
+	files, err := fs.Glob(guides, "guides/*.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range files {
+		code := fnerrors.Code(strings.TrimSuffix(strings.TrimPrefix(file, "guides/"), ".md"))
+		if !known[code] {
+			t.Errorf("%s: guide for an unknown code", file)
+		}
+	}
+}
+
+func TestLookupIsCaseInsensitive(t *testing.T) {
+	entry, ok := Lookup("ns3001")
+	if !ok {
+		t.Fatal("expected ns3001 to be found")
+	}
+
+	if entry.Code != fnerrors.CodeMissingSecret {
+		t.Errorf("expected %s, got %s", fnerrors.CodeMissingSecret, entry.Code)
+	}
+}
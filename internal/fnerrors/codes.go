@@ -0,0 +1,110 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package fnerrors
+
+import (
+	"errors"
+
+	"google.golang.org/grpc/status"
+	"namespacelabs.dev/foundation/schema/tasks"
+)
+
+// Code is a stable identifier of a class of errors, which users (and tooling)
+// can rely on to look up how to address them. Codes are never reused, and each
+// has a remediation guide (see `ns explain-error`).
+type Code string
+
+const (
+	// NS1xxx: authentication and authorization.
+	CodeReauthRequired   Code = "NS1001"
+	CodePermissionDenied Code = "NS1002"
+
+	// NS2xxx: cluster connectivity.
+	CodeClusterNotConfigured Code = "NS2001"
+	CodeClusterUnreachable   Code = "NS2002"
+
+	// NS3xxx: secrets.
+	CodeMissingSecret Code = "NS3001"
+
+	// NS4xxx: environments.
+	CodeIncompatibleEnvironment Code = "NS4001"
+)
+
+var AllCodes = []Code{
+	CodeReauthRequired,
+	CodePermissionDenied,
+	CodeClusterNotConfigured,
+	CodeClusterUnreachable,
+	CodeMissingSecret,
+	CodeIncompatibleEnvironment,
+}
+
+// WithCode attaches a code to an error, and is meant to be applied where the
+// error is constructed.
+func WithCode(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if x, ok := err.(interface{ baseError() *BaseError }); ok {
+		x.baseError().Code = code
+		return err
+	}
+
+	return &codedError{code: code, err: err}
+}
+
+// CodeOf returns the outermost code attached to the error chain, if any. Joined
+// errors are searched in order.
+func CodeOf(err error) (Code, bool) {
+	for err != nil {
+		if x, ok := err.(interface{ ErrorCode() Code }); ok && x.ErrorCode() != "" {
+			return x.ErrorCode(), true
+		}
+
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, err := range joined.Unwrap() {
+				if code, ok := CodeOf(err); ok {
+					return code, true
+				}
+			}
+
+			return "", false
+		}
+
+		err = errors.Unwrap(err)
+	}
+
+	return "", false
+}
+
+// WithCodeDetail attaches the error's code, if any, to the status.
+func WithCodeDetail(st *status.Status, err error) *status.Status {
+	code, ok := CodeOf(err)
+	if !ok {
+		return st
+	}
+
+	for _, detail := range st.Proto().GetDetails() {
+		if detail.MessageIs(&tasks.ErrorDetail_Code{}) {
+			return st
+		}
+	}
+
+	if p, err := st.WithDetails(&tasks.ErrorDetail_Code{Code: string(code)}); err == nil {
+		return p
+	}
+
+	return st
+}
+
+type codedError struct {
+	code Code
+	err  error
+}
+
+func (e *codedError) Error() string   { return e.err.Error() }
+func (e *codedError) Unwrap() error   { return e.err }
+func (e *codedError) ErrorCode() Code { return e.code }
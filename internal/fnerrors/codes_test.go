@@ -0,0 +1,37 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package fnerrors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCodeOf(t *testing.T) {
+	coded := WithCode(CodeClusterUnreachable, errors.New("dial tcp: connection refused"))
+
+	for _, test := range []struct {
+		name string
+		err  error
+		want Code
+	}{
+		{"nil", nil, ""},
+		{"uncoded", errors.New("failed"), ""},
+		{"coded", coded, CodeClusterUnreachable},
+		{"wrapped", fmt.Errorf("apply: %w", coded), CodeClusterUnreachable},
+		{"base error", WithCode(CodeMissingSecret, BadInputError("missing")), CodeMissingSecret},
+		{"outermost", WithCode(CodeMissingSecret, fmt.Errorf("wrapped: %w", coded)), CodeMissingSecret},
+		{"joined", errors.Join(errors.New("failed"), coded), CodeClusterUnreachable},
+		{"wrapped join", fmt.Errorf("deploy: %w", errors.Join(errors.New("failed"), fmt.Errorf("apply: %w", coded))), CodeClusterUnreachable},
+		{"multiple wraps", fmt.Errorf("%w; %w", errors.New("failed"), coded), CodeClusterUnreachable},
+		{"uncoded join", errors.Join(errors.New("a"), errors.New("b")), ""},
+	} {
+		got, ok := CodeOf(test.err)
+		if got != test.want || ok != (test.want != "") {
+			t.Errorf("%s: got (%q, %v), want %q", test.name, got, ok, test.want)
+		}
+	}
+}
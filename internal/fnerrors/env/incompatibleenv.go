@@ -7,6 +7,7 @@ package env
 import (
 	"fmt"
 
+	"namespacelabs.dev/foundation/internal/fnerrors"
 	"namespacelabs.dev/foundation/schema"
 )
 
@@ -34,3 +35,7 @@ func (err IncompatibleEnvironmentErr) Error() string {
 	return fmt.Sprintf("environment %q is incompatible with %q (included by %s), it requires %s",
 		err.Env.Name, err.RequirementOwner, err.ServerPackageName, req)
 }
+
+func (err IncompatibleEnvironmentErr) ErrorCode() fnerrors.Code {
+	return fnerrors.CodeIncompatibleEnvironment
+}
@@ -51,7 +51,7 @@ func NewWithLocation(loc Location, format string, args ...interface{}) error {
 func AttachLocation(loc Location, err error) error {
 	if userErr, ok := err.(*BaseError); ok {
 		if userErr.Location == nil {
-			return &BaseError{Kind: userErr.Kind, OriginalErr: userErr.OriginalErr, stack: userErr.stack, Location: loc}
+			return &BaseError{Kind: userErr.Kind, OriginalErr: userErr.OriginalErr, stack: userErr.stack, Location: loc, Code: userErr.Code}
 		} else if userErr.Location == loc {
 			return userErr
 		}
@@ -79,11 +79,13 @@ func makeError(kind ErrorKind, format string, args ...interface{}) *BaseError {
 
 func ReauthError(toFixThis string, args ...interface{}) error {
 	err := makeError(Kind_USER, toFixThis, args...)
+	err.Code = CodeReauthRequired
 	return &ReauthErr{BaseError: *err, Why: err.Error()}
 }
 
 func PermissionDeniedError(toFixThis string, args ...interface{}) error {
 	err := makeError(Kind_USER, toFixThis, args...)
+	err.Code = CodePermissionDenied
 	return &PermissionDeniedErr{BaseError: *err, Why: err.Error()}
 }
 
@@ -150,6 +152,7 @@ type BaseError struct {
 	Kind        ErrorKind
 	OriginalErr error
 	Location    Location
+	Code        Code // May be empty.
 
 	stack stacktrace.StackTrace
 }
@@ -169,6 +172,10 @@ func (e *BaseError) IsExpectedError() (error, bool) {
 
 func (e *BaseError) Unwrap() error { return e.OriginalErr }
 
+func (e *BaseError) ErrorCode() Code { return e.Code }
+
+func (e *BaseError) baseError() *BaseError { return e }
+
 // Signature is compatible with pkg/errors and allows frameworks like Sentry to
 // automatically extract the frame.
 func (e *BaseError) StackTrace() stacktrace.StackTrace {
@@ -288,7 +295,7 @@ func (ae *ActionError) Trace() []*protocol.Task { return ae.TraceProto }
 func (ae *ActionError) GRPCStatus() *status.Status {
 	st, _ := status.FromError(ae.OriginalErr)
 	p, _ := st.WithDetails(&tasks.ErrorDetail_ActionID{ActionId: ae.ActionID})
-	return p
+	return WithCodeDetail(p, ae.OriginalErr)
 }
 
 func IsNamespaceError(err error) bool {
@@ -113,6 +113,10 @@ func Format(w io.Writer, err error, args ...FormatOption) {
 	// XXX TODO write stacktrace out if requested.
 	// writeSourceFileAndLine(w, cause, opts.style)
 
+	if code, ok := fnerrors.CodeOf(err); ok {
+		formatCode(w, code, opts)
+	}
+
 	fmt.Fprintln(w, opts.style.ErrorHeader.Apply("========================================"))
 }
 
@@ -191,6 +195,19 @@ func formatErrWithLogs(w io.Writer, err *fnerrors.ErrWithLogs, opts *FormatOptio
 	fmt.Fprintln(w)
 }
 
+func formatCode(w io.Writer, code fnerrors.Code, opts *FormatOptions) {
+	fmt.Fprintln(w)
+
+	switch name.CmdName {
+	case "ns", "nsdev":
+		fmt.Fprintf(w, "%s %s (run `%s` to learn how to address it)\n", opts.style.LessRelevant.Apply("Error code:"), code,
+			opts.style.Highlight.Apply(fmt.Sprintf("%s explain-error %s", name.CmdName, code)))
+
+	default:
+		fmt.Fprintf(w, "%s %s\n", opts.style.LessRelevant.Apply("Error code:"), code)
+	}
+}
+
 func formatUsageError(w io.Writer, err *fnerrors.UsageErr, opts *FormatOptions) {
 	// XXX don't wordwrap if terminal is below 80 chars in width.
 	errTxt := text.Wrap(err.Why, 80)
@@ -21,6 +21,8 @@ func TestErrorFormatting(t *testing.T) {
 			expected: "\n========================================\nFailed: It expired.\n\n  Run 'foobar'.\n========================================\n"},
 		{err: fnerrors.Newf("wrapping it: %w", fnerrors.UsageError("Run 'foobar'.", "It expired.")),
 			expected: "\n========================================\nFailed: wrapping it: It expired.\n\n  Run 'foobar'.\n========================================\n"},
+		{err: fnerrors.Newf("wrapping it: %w", fnerrors.WithCode(fnerrors.CodeMissingSecret, fnerrors.UsageError("Run 'foobar'.", "It's missing."))),
+			expected: "\n========================================\nFailed: wrapping it: It's missing.\n\n  Run 'foobar'.\n\nError code: NS3001 (run `ns explain-error NS3001` to learn how to address it)\n========================================\n"},
 	}
 
 	for _, c := range cases {
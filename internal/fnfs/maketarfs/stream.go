@@ -10,7 +10,10 @@ import (
 	"io"
 	"io/fs"
 	"math"
+	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"time"
 
 	"namespacelabs.dev/foundation/internal/bytestream"
@@ -21,9 +24,40 @@ import (
 
 var FixedPoint = time.Unix(1, 1)
 
+// If set, the number of seconds since the epoch to use as the modification
+// time of every entry. See https://reproducible-builds.org/specs/source-date-epoch/.
+const SourceDateEpochEnv = "SOURCE_DATE_EPOCH"
+
+// ModTime returns the modification time that is set on every entry: either
+// SOURCE_DATE_EPOCH, if set, or FixedPoint. Tar headers only retain seconds,
+// so the result is truncated to avoid depending on how it would be rounded.
+func ModTime() (time.Time, error) {
+	if v := os.Getenv(SourceDateEpochEnv); v != "" {
+		secs, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return time.Time{}, fnerrors.BadInputError("%s: expected a number of seconds, got %q", SourceDateEpochEnv, v)
+		}
+
+		return time.Unix(secs, 0).UTC(), nil
+	}
+
+	return FixedPoint.Truncate(time.Second).UTC(), nil
+}
+
+type entry struct {
+	path string
+	size int64
+}
+
+// TarFS writes the files in vfs as a tar stream. The output only depends on
+// the paths and contents of the files: entries are sorted, every parent
+// directory is emitted, and modification times, ownership and permissions are
+// normalized.
 func TarFS(ctx context.Context, parentW io.Writer, vfs fs.FS, includeFiles []string, excludeFiles []string) error {
-	w := tar.NewWriter(parentW)
-	defer w.Close()
+	modTime, err := ModTime()
+	if err != nil {
+		return err
+	}
 
 	var inclusion uniquestrings.List
 	for _, f := range includeFiles {
@@ -35,43 +69,76 @@ func TarFS(ctx context.Context, parentW io.Writer, vfs fs.FS, includeFiles []str
 		exclusion.Add(f)
 	}
 
-	dirs := map[string]bool{}
-
+	// Not every fs.FS visits files in a deterministic order (e.g. tarfs
+	// follows the order of its source), so paths are sorted before being
+	// written. Contents are only opened when written, so that they're not
+	// all held at once.
+	var entries []entry
 	if err := fnfs.VisitFiles(ctx, vfs, func(path string, contents bytestream.ByteStream, _ fs.DirEntry) error {
 		if exclusion.Has(path) || (len(includeFiles) > 0 && !inclusion.Has(path)) {
 			return nil
 		}
 
-		dir := filepath.Dir(path)
-		if dir != "." && !dirs[dir] {
-			if err := w.WriteHeader(&tar.Header{
-				Name:     dir,
-				Typeflag: tar.TypeDir,
-				Mode:     0555,
-				ModTime:  FixedPoint,
-			}); err != nil {
-				return err
-			}
-			dirs[dir] = true
-		}
-
 		if contents.ContentLength() > math.MaxInt64 {
 			return fnerrors.Newf("file too big")
 		}
 
+		entries = append(entries, entry{path, int64(contents.ContentLength())})
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].path < entries[j].path
+	})
+
+	w := tar.NewWriter(parentW)
+	defer w.Close()
+
+	dirs := map[string]bool{}
+
+	var writeDir func(string) error
+	writeDir = func(dir string) error {
+		if dir == "." || dir == "/" || dirs[dir] {
+			return nil
+		}
+
+		if err := writeDir(filepath.Dir(dir)); err != nil {
+			return err
+		}
+
+		dirs[dir] = true
+		return w.WriteHeader(&tar.Header{
+			Name:     dir,
+			Typeflag: tar.TypeDir,
+			Mode:     0555,
+			ModTime:  modTime,
+		})
+	}
+
+	for _, e := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := writeDir(filepath.Dir(e.path)); err != nil {
+			return err
+		}
+
 		if err := w.WriteHeader(&tar.Header{
-			Name:     path,
-			Size:     int64(contents.ContentLength()),
+			Name:     e.path,
+			Size:     e.size,
 			Typeflag: tar.TypeReg,
 			Mode:     0555,
-			ModTime:  FixedPoint,
+			ModTime:  modTime,
 		}); err != nil {
 			return err
 		}
 
-		return bytestream.WriteTo(w, contents)
-	}); err != nil {
-		return err
+		if err := writeFile(w, vfs, e); err != nil {
+			return err
+		}
 	}
 
 	if err := w.Close(); err != nil {
@@ -80,3 +147,23 @@ func TarFS(ctx context.Context, parentW io.Writer, vfs fs.FS, includeFiles []str
 
 	return nil
 }
+
+func writeFile(w io.Writer, vfs fs.FS, e entry) error {
+	f, err := vfs.Open(e.path)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	n, err := io.Copy(w, f)
+	if err != nil {
+		return err
+	}
+
+	if n != e.size {
+		return fnerrors.BadDataError("%s: expected %d bytes, got %d", e.path, e.size, n)
+	}
+
+	return nil
+}
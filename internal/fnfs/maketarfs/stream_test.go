@@ -0,0 +1,94 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package maketarfs_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"namespacelabs.dev/foundation/internal/fnfs/maketarfs"
+	"namespacelabs.dev/foundation/internal/fnfs/tarfs"
+)
+
+func TestTarFSIsReproducible(t *testing.T) {
+	files := []string{"b/c/file", "a.txt", "b/another"}
+
+	// tarfs visits files in the order they're found in its source.
+	makeFS := func(order []string) tarfs.FS {
+		var buf bytes.Buffer
+		w := tar.NewWriter(&buf)
+		for _, name := range order {
+			if err := w.WriteHeader(&tar.Header{Name: name, Size: int64(len(name)), Typeflag: tar.TypeReg, Mode: 0644, ModTime: time.Now(), Uid: 1000}); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := w.Write([]byte(name)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		return tarfs.FS{TarStream: func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(buf.Bytes())), nil
+		}}
+	}
+
+	tarOf := func(order []string) []byte {
+		var out bytes.Buffer
+		if err := maketarfs.TarFS(context.Background(), &out, makeFS(order), nil, nil); err != nil {
+			t.Fatal(err)
+		}
+		return out.Bytes()
+	}
+
+	a := tarOf(files)
+	b := tarOf([]string{files[2], files[0], files[1]})
+	if !bytes.Equal(a, b) {
+		t.Fatal("expected the same output regardless of the order of the source")
+	}
+
+	var names []string
+	tr := tar.NewReader(bytes.NewReader(a))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+
+		if !hdr.ModTime.Equal(time.Unix(1, 0)) || hdr.Uid != 0 || hdr.Gid != 0 {
+			t.Errorf("%s: expected a normalized header, got mtime=%v uid=%d gid=%d", hdr.Name, hdr.ModTime, hdr.Uid, hdr.Gid)
+		}
+
+		names = append(names, hdr.Name)
+	}
+
+	expected := []string{"a.txt", "b", "b/another", "b/c", "b/c/file"}
+	if len(names) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, names)
+	}
+	for k := range expected {
+		if names[k] != expected[k] {
+			t.Fatalf("expected %v, got %v", expected, names)
+		}
+	}
+
+	t.Setenv(maketarfs.SourceDateEpochEnv, "1700000000")
+
+	hdr, err := tar.NewReader(bytes.NewReader(tarOf(files))).Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !hdr.ModTime.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("expected SOURCE_DATE_EPOCH to be honored, got %v", hdr.ModTime)
+	}
+}
@@ -34,21 +34,22 @@ type cueLayeredImageBuildPlan struct {
 var _ json.Unmarshaler = &cueLayeredImageBuildPlan{}
 
 type cueImageBuildPlan struct {
-	Prebuilt                 string                                 `json:"prebuilt,omitempty"`
-	GoPackage                string                                 `json:"go_package,omitempty"`
-	GoBuild                  *schema.ImageBuildPlan_GoBuild         `json:"go_build,omitempty"`
-	Dockerfile               string                                 `json:"dockerfile,omitempty"`
-	DockerBuild              *schema.ImageBuildPlan_DockerBuild     `json:"docker_build,omitempty"`
-	LlbPlan                  *cueImageBuildPlan_LLBPlan             `json:"llb_plan,omitempty"`
-	NixFlake                 string                                 `json:"nix_flake,omitempty"`
-	Deprecated_SnapshotFiles []string                               `json:"snapshot_files,omitempty"` // Use `files` instead.
-	Files                    []string                               `json:"files,omitempty"`
-	AlpineBuild              *schema.ImageBuildPlan_AlpineBuild     `json:"alpine_build,omitempty"`
-	Binary                   string                                 `json:"binary,omitempty"`
-	FilesFrom                *cueImageBuildPlan_FilesFrom           `json:"files_from,omitempty"`
-	MakeFilesystemImage      *cueImageBuildPlan_MakeFilesystemImage `json:"make_fs_image,omitempty"`
-	ImageID                  string                                 `json:"image_id,omitempty"`
-	MelangeBuild             *schema.ImageBuildPlan_MelangeBuild    `json:"melange_build,omitempty"`
+	Prebuilt                 string                                   `json:"prebuilt,omitempty"`
+	GoPackage                string                                   `json:"go_package,omitempty"`
+	GoBuild                  *schema.ImageBuildPlan_GoBuild           `json:"go_build,omitempty"`
+	Dockerfile               string                                   `json:"dockerfile,omitempty"`
+	DockerBuild              *schema.ImageBuildPlan_DockerBuild       `json:"docker_build,omitempty"`
+	LlbPlan                  *cueImageBuildPlan_LLBPlan               `json:"llb_plan,omitempty"`
+	NixFlake                 string                                   `json:"nix_flake,omitempty"`
+	Deprecated_SnapshotFiles []string                                 `json:"snapshot_files,omitempty"` // Use `files` instead.
+	Files                    []string                                 `json:"files,omitempty"`
+	AlpineBuild              *schema.ImageBuildPlan_AlpineBuild       `json:"alpine_build,omitempty"`
+	Binary                   string                                   `json:"binary,omitempty"`
+	FilesFrom                *cueImageBuildPlan_FilesFrom             `json:"files_from,omitempty"`
+	MakeFilesystemImage      *cueImageBuildPlan_MakeFilesystemImage   `json:"make_fs_image,omitempty"`
+	ImageID                  string                                   `json:"image_id,omitempty"`
+	MelangeBuild             *schema.ImageBuildPlan_MelangeBuild      `json:"melange_build,omitempty"`
+	StaticFilesServer        *schema.ImageBuildPlan_StaticFilesServer `json:"static_files_server,omitempty"`
 }
 
 type cueImageBuildPlan_LLBPlan struct {
@@ -223,6 +224,11 @@ func (bp cueImageBuildPlan) ToSchema(ctx context.Context, pl parsing.EarlyPackag
 		set = append(set, "alpine_build")
 	}
 
+	if bp.StaticFilesServer != nil {
+		plan.StaticFilesServer = bp.StaticFilesServer
+		set = append(set, "static_files_server")
+	}
+
 	if bp.FilesFrom != nil {
 		from, err := bp.FilesFrom.From.ToSchema(ctx, pl, loc)
 		if err != nil {
@@ -20,7 +20,7 @@ import (
 )
 
 // Needs to be consistent with JSON names of cueSecret fields.
-var testFields = []string{"serversUnderTest", "args", "env", "image", "imageFrom", "integration"}
+var testFields = []string{"serversUnderTest", "args", "env", "image", "imageFrom", "integration", "resources"}
 
 type cueTest struct {
 	Servers []string            `json:"serversUnderTest"`
@@ -91,5 +91,19 @@ func parseTest(ctx context.Context, env *schema.Environment, pl parsing.EarlyPac
 		}
 	}
 
+	if r := v.LookupPath("resources"); r.Exists() {
+		resourceList, err := ParseResourceList(r)
+		if err != nil {
+			return nil, fnerrors.NewWithLocation(pkg.Location, "parsing test %q resources failed: %w", name, err)
+		}
+
+		pack, err := resourceList.ToPack(ctx, env, pl, pkg)
+		if err != nil {
+			return nil, err
+		}
+
+		out.ResourcePack = pack
+	}
+
 	return out, nil
 }
@@ -0,0 +1,65 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package cuefrontend
+
+import (
+	"context"
+	"io/fs"
+	"testing"
+
+	"cuelang.org/go/cue/cuecontext"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+	"namespacelabs.dev/foundation/internal/frontend/fncue"
+	"namespacelabs.dev/foundation/schema"
+	"namespacelabs.dev/foundation/std/pkggraph"
+)
+
+func TestParseTestResources(t *testing.T) {
+	ctx := context.Background()
+	pkg := &pkggraph.Package{Location: pkggraph.Location{PackageName: "example.com/current/tests"}}
+	v := cuecontext.New().CompileString(`{
+	serversUnderTest: ["example.com/current/server"]
+	resources: [":local", "example.com/shared/instances:db"]
+}`)
+	if err := v.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	loader := &earlyRecordingPackageLoader{}
+	test, err := parseTest(ctx, &schema.Environment{}, loader, pkg, "hello", &fncue.CueV{Val: v})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &schema.ResourcePack{
+		ResourceRef: []*schema.PackageRef{
+			schema.MakePackageRef("example.com/current/tests", "local"),
+			schema.MakePackageRef("example.com/shared/instances", "db"),
+		},
+	}
+
+	if d := cmp.Diff(expected, test.ResourcePack, protocmp.Transform()); d != "" {
+		t.Errorf("unexpected resource pack (-want +got):\n%s", d)
+	}
+
+	if len(loader.ensured) != 1 || loader.ensured[0] != "example.com/shared/instances" {
+		t.Errorf("expected only the remote resource package to be ensured, got %v", loader.ensured)
+	}
+
+	if _, err := parseTest(ctx, &schema.Environment{}, loader, pkg, "hello", &fncue.CueV{Val: cuecontext.New().CompileString(`{
+	resources: [":local", 3]
+}`)}); err == nil {
+		t.Error("expected malformed resources to be rejected")
+	}
+}
+
+type earlyRecordingPackageLoader struct {
+	recordingPackageLoader
+}
+
+func (*earlyRecordingPackageLoader) WorkspaceOf(context.Context, *pkggraph.Module) (fs.FS, error) {
+	panic("unexpected WorkspaceOf")
+}
@@ -0,0 +1,377 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package scaffold
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/cue/format"
+	"namespacelabs.dev/foundation/internal/console"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/internal/fnfs"
+	"namespacelabs.dev/go-ids"
+)
+
+const (
+	// A template is a package with a manifest, and the files it instantiates
+	// under a "files" directory.
+	TemplateManifestFile = "template.cue"
+	templateFilesDir     = "files"
+	// Template files may have this suffix, so that they aren't picked up by
+	// other tools (e.g. as part of Go packages) in the module that ships them.
+	templateFileSuffix = ".tmpl"
+)
+
+// Template is a user-defined scaffolding template, e.g.
+//
+//	description: "A Go gRPC service, with tracing and Postgres."
+//	params: [
+//		{name: "service", description: "The name of the service.", default: "MyService"},
+//		{name: "postgres", type: "bool", default: true},
+//		{name: "tier", type: "enum", options: ["frontend", "backend"]},
+//	]
+//
+// Files (and their paths) are Go templates, rendered with .Params (by name),
+// .Package (the full name of the package being created), .Module, .Name (the
+// package's base name) and .Id (a fresh random ID, e.g. for server IDs).
+// Files which render to nothing are skipped.
+type Template struct {
+	Name        string
+	Description string          `json:"description"`
+	Params      []TemplateParam `json:"params"`
+
+	fsys fs.FS
+	dir  string
+}
+
+type TemplateParam struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// One of string (the default), int, bool or enum.
+	Type     string   `json:"type"`
+	Default  any      `json:"default"`
+	Options  []string `json:"options"`
+	Pattern  string   `json:"pattern"`
+	Required bool     `json:"required"`
+}
+
+// LoadTemplate loads the template at dir, within fsys.
+func LoadTemplate(fsys fs.FS, dir, name string) (*Template, error) {
+	manifestPath := path.Join(dir, TemplateManifestFile)
+
+	contents, err := fs.ReadFile(fsys, manifestPath)
+	if err != nil {
+		return nil, fnerrors.BadInputError("%s: not a template: %w", name, err)
+	}
+
+	v := cuecontext.New().CompileBytes(contents, cue.Filename(manifestPath))
+	if err := v.Err(); err != nil {
+		return nil, fnerrors.BadInputError("%s: invalid %s: %w", name, TemplateManifestFile, err)
+	}
+
+	t := &Template{Name: name, fsys: fsys, dir: dir}
+	if err := v.Decode(t); err != nil {
+		return nil, fnerrors.BadInputError("%s: invalid %s: %w", name, TemplateManifestFile, err)
+	}
+
+	seen := map[string]bool{}
+	for k, p := range t.Params {
+		if p.Name == "" {
+			return nil, fnerrors.BadInputError("%s: param #%d: name is required", name, k)
+		}
+
+		if seen[p.Name] {
+			return nil, fnerrors.BadInputError("%s: param %q declared more than once", name, p.Name)
+		}
+		seen[p.Name] = true
+
+		switch p.Type {
+		case "":
+			t.Params[k].Type = "string"
+
+		case "string", "int", "bool":
+
+		case "enum":
+			if len(p.Options) == 0 {
+				return nil, fnerrors.BadInputError("%s: param %q: enums require options", name, p.Name)
+			}
+
+		default:
+			return nil, fnerrors.BadInputError("%s: param %q: unsupported type %q", name, p.Name, p.Type)
+		}
+
+		if p.Pattern != "" {
+			if _, err := regexp.Compile(p.Pattern); err != nil {
+				return nil, fnerrors.BadInputError("%s: param %q: invalid pattern: %w", name, p.Name, err)
+			}
+		}
+
+		if p.Default != nil {
+			if _, err := t.Params[k].Parse(fmt.Sprint(p.Default)); err != nil {
+				return nil, fnerrors.BadInputError("%s: param %q: invalid default: %w", name, p.Name, err)
+			}
+		}
+	}
+
+	return t, nil
+}
+
+// ListTemplates returns the names (relative to fsys) of the templates within fsys.
+func ListTemplates(fsys fs.FS) ([]string, error) {
+	var names []string
+	if err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() && strings.HasPrefix(d.Name(), ".") && p != "." {
+			return fs.SkipDir
+		}
+
+		if !d.IsDir() && d.Name() == TemplateManifestFile {
+			names = append(names, path.Dir(p))
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+// DefaultValue returns the parameter's default, as a string.
+func (p TemplateParam) DefaultValue() (string, bool) {
+	if p.Default == nil {
+		return "", false
+	}
+	return fmt.Sprint(p.Default), true
+}
+
+// Parse validates a value for the parameter, and converts it to its type.
+func (p TemplateParam) Parse(value string) (any, error) {
+	switch p.Type {
+	case "int":
+		return strconv.Atoi(value)
+
+	case "bool":
+		return strconv.ParseBool(value)
+
+	case "enum":
+		for _, opt := range p.Options {
+			if opt == value {
+				return value, nil
+			}
+		}
+		return nil, fnerrors.Newf("%q is not one of %s", value, strings.Join(p.Options, ", "))
+
+	default:
+		if p.Pattern != "" {
+			if !regexp.MustCompile(p.Pattern).MatchString(value) {
+				return nil, fnerrors.Newf("%q doesn't match %s", value, p.Pattern)
+			}
+		}
+		return value, nil
+	}
+}
+
+// ResolveParams determines the value of each parameter: specified values take
+// precedence; then, if prompt is set, the user is asked; otherwise defaults
+// are used.
+func (t *Template) ResolveParams(specified map[string]string, prompt func(TemplateParam) (string, error)) (map[string]any, error) {
+	known := map[string]bool{}
+	for _, p := range t.Params {
+		known[p.Name] = true
+	}
+
+	for name := range specified {
+		if !known[name] {
+			return nil, fnerrors.BadInputError("%s: no such param %q", t.Name, name)
+		}
+	}
+
+	values := map[string]any{}
+	for _, p := range t.Params {
+		value, ok := specified[p.Name]
+		if !ok && prompt != nil {
+			v, err := prompt(p)
+			if err != nil {
+				return nil, err
+			}
+			value, ok = v, v != ""
+		}
+
+		if !ok {
+			value, ok = p.DefaultValue()
+		}
+
+		if !ok {
+			if p.Required {
+				return nil, fnerrors.BadInputError("%s: a value for %q is required", t.Name, p.Name)
+			}
+
+			// Zero values are typed, so templates can rely on them.
+			switch p.Type {
+			case "int":
+				values[p.Name] = 0
+			case "bool":
+				values[p.Name] = false
+			default:
+				values[p.Name] = ""
+			}
+			continue
+		}
+
+		parsed, err := p.Parse(value)
+		if err != nil {
+			return nil, fnerrors.BadInputError("%s: %s: %w", t.Name, p.Name, err)
+		}
+
+		values[p.Name] = parsed
+	}
+
+	return values, nil
+}
+
+type userTemplateData struct {
+	Params  map[string]any
+	Package string
+	Module  string
+	Name    string
+	Id      string
+}
+
+var userTemplateFuncs = template.FuncMap{
+	"lower":   strings.ToLower,
+	"upper":   strings.ToUpper,
+	"replace": strings.ReplaceAll,
+	"quote":   strconv.Quote,
+}
+
+// Instantiate renders the template's files into loc. Fails without writing
+// anything if any of the files already exists.
+func (t *Template) Instantiate(ctx context.Context, fsfs fnfs.ReadWriteFS, loc fnfs.Location, params map[string]any) error {
+	data := userTemplateData{
+		Params:  params,
+		Package: path.Join(loc.ModuleName, filepath.ToSlash(loc.RelPath)),
+		Module:  loc.ModuleName,
+		Name:    path.Base(filepath.ToSlash(loc.RelPath)),
+		Id:      ids.NewRandomBase32ID(12),
+	}
+
+	type rendered struct {
+		path     string
+		contents []byte
+	}
+
+	var files []rendered
+	filesDir := path.Join(t.dir, templateFilesDir)
+	if err := fs.WalkDir(t.fsys, filesDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		rel := strings.TrimSuffix(strings.TrimPrefix(p, filesDir+"/"), templateFileSuffix)
+
+		relPath, err := t.render(rel, []byte(rel), data)
+		if err != nil {
+			return err
+		}
+
+		src, err := fs.ReadFile(t.fsys, p)
+		if err != nil {
+			return err
+		}
+
+		contents, err := t.render(rel, src, data)
+		if err != nil {
+			return err
+		}
+
+		if len(bytes.TrimSpace(contents)) == 0 || strings.TrimSpace(string(relPath)) == "" {
+			return nil
+		}
+
+		// Templates may come from dependencies; they can't write outside of
+		// the target package.
+		cleaned := filepath.Clean(filepath.FromSlash(strings.TrimSpace(string(relPath))))
+		if !filepath.IsLocal(cleaned) {
+			return fnerrors.BadInputError("%s: rendered path %q is outside of the target package", rel, relPath)
+		}
+
+		target := loc.Rel(cleaned)
+		if strings.HasSuffix(target, ".cue") {
+			formatted, err := format.Source(contents)
+			if err != nil {
+				fmt.Fprintf(console.Debug(ctx), "The rendered %s was:\n%s\n", rel, contents)
+				return fnerrors.BadInputError("%s: %s: failed to format rendered Cue file: %w", t.Name, rel, err)
+			}
+			contents = formatted
+		}
+
+		files = append(files, rendered{target, contents})
+		return nil
+	}); err != nil {
+		return fnerrors.BadInputError("%s: %w", t.Name, err)
+	}
+
+	if len(files) == 0 {
+		return fnerrors.BadInputError("%s: template has no files", t.Name)
+	}
+
+	var existing []string
+	for _, f := range files {
+		if _, err := fs.Stat(fsfs, f.path); err == nil {
+			existing = append(existing, f.path)
+		}
+	}
+
+	if len(existing) > 0 {
+		return fnerrors.BadInputError("refusing to overwrite existing files: %s", strings.Join(existing, ", "))
+	}
+
+	for _, f := range files {
+		if err := fnfs.WriteWorkspaceFile(ctx, console.Stdout(ctx), fsfs, f.path, func(w io.Writer) error {
+			_, err := w.Write(f.contents)
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *Template) render(name string, src []byte, data userTemplateData) ([]byte, error) {
+	tmpl, err := template.New(name).Funcs(userTemplateFuncs).Option("missingkey=error").Parse(string(src))
+	if err != nil {
+		return nil, fnerrors.BadInputError("%s: %w", t.Name, err)
+	}
+
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return nil, fnerrors.BadInputError("%s: %w", t.Name, err)
+	}
+
+	return out.Bytes(), nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package scaffold
+
+import (
+	"context"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+	"namespacelabs.dev/foundation/internal/fnfs"
+	"namespacelabs.dev/foundation/internal/fnfs/memfs"
+)
+
+func TestUserTemplate(t *testing.T) {
+	templates := fstest.MapFS{
+		"starters/service/template.cue": {Data: []byte(`
+description: "A service."
+params: [
+	{name: "service", default: "Greeter", pattern: "^[A-Z][A-Za-z]+$"},
+	{name: "postgres", type: "bool", default: false},
+	{name: "replicas", type: "int", default: 1},
+]
+`)},
+		"starters/service/files/service.cue.tmpl":                  {Data: []byte(`service: {name: "{{.Params.service}}", package: "{{.Package}}", replicas: {{.Params.replicas}}}`)},
+		"starters/service/files/{{lower .Params.service}}.go.tmpl": {Data: []byte("package {{.Name}}\n")},
+		"starters/service/files/db.sql.tmpl":                       {Data: []byte(`{{if .Params.postgres}}CREATE TABLE x (id INT);{{end}}`)},
+	}
+
+	names, err := ListTemplates(templates)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d := cmp.Diff([]string{"starters/service"}, names); d != "" {
+		t.Errorf("ListTemplates mismatch (-want +got):\n%s", d)
+	}
+
+	tmpl, err := LoadTemplate(templates, "starters/service", "example.com/starters/service")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := tmpl.ResolveParams(map[string]string{"service": "lowercase"}, nil); err == nil {
+		t.Error("expected the pattern to be enforced")
+	}
+
+	params, err := tmpl.ResolveParams(map[string]string{"replicas": "3"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out memfs.FS
+	loc := fnfs.Location{ModuleName: "example.com/app", RelPath: "api/greeter"}
+	if err := tmpl.Instantiate(context.Background(), &out, loc, params); err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]string{}
+	if err := fs.WalkDir(&out, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		contents, err := fs.ReadFile(&out, p)
+		got[p] = string(contents)
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"api/greeter/service.cue": "service: {name: \"Greeter\", package: \"example.com/app/api/greeter\", replicas: 3}\n",
+		"api/greeter/greeter.go":  "package greeter\n",
+	}
+
+	if d := cmp.Diff(expected, got); d != "" {
+		t.Errorf("Instantiate mismatch (-want +got):\n%s", d)
+	}
+
+	if err := tmpl.Instantiate(context.Background(), &out, loc, params); err == nil {
+		t.Error("expected existing files not to be overwritten")
+	}
+}
+
+func TestUserTemplateRejectsEscapingPaths(t *testing.T) {
+	for _, p := range []string{"../../x", "/etc/x", "a/../../x"} {
+		templates := fstest.MapFS{
+			"starters/evil/template.cue":                {Data: []byte(`params: [{name: "path", default: "` + p + `"}]`)},
+			"starters/evil/files/{{.Params.path}}.tmpl": {Data: []byte("contents\n")},
+		}
+
+		tmpl, err := LoadTemplate(templates, "starters/evil", "example.com/starters/evil")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		params, err := tmpl.ResolveParams(nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var out memfs.FS
+		loc := fnfs.Location{ModuleName: "example.com/app", RelPath: "api/greeter"}
+		if err := tmpl.Instantiate(context.Background(), &out, loc, params); err == nil {
+			t.Errorf("%s: expected the rendered path to be rejected", p)
+		}
+
+		if entries, _ := fs.ReadDir(&out, "."); len(entries) > 0 {
+			t.Errorf("%s: expected nothing to be written", p)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package git
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"namespacelabs.dev/foundation/internal/fnerrors"
+)
+
+// ChangedFiles returns the absolute paths of the files that were changed since
+// `since` (any revision git understands), including uncommitted and untracked
+// files in the working tree.
+func ChangedFiles(ctx context.Context, dir, since string) ([]string, error) {
+	if since == "" || strings.HasPrefix(since, "-") {
+		return nil, fnerrors.BadInputError("%q: invalid revision", since)
+	}
+
+	top, errOut, err := RunGit(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fnerrors.Newf("%s: not a git repository: %w: %s", dir, err, errOut)
+	}
+
+	root := strings.TrimSpace(string(top))
+
+	diff, errOut, err := RunGit(ctx, root, "diff", "--name-only", "--no-renames", "--end-of-options", since, "--")
+	if err != nil {
+		return nil, fnerrors.Newf("failed to compute changes since %q: %w: %s", since, err, errOut)
+	}
+
+	untracked, errOut, err := RunGit(ctx, root, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, fnerrors.Newf("failed to list untracked files: %w: %s", err, errOut)
+	}
+
+	seen := map[string]bool{}
+	var files []string
+	for _, line := range strings.Split(string(diff)+"\n"+string(untracked), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || seen[line] {
+			continue
+		}
+
+		seen[line] = true
+		files = append(files, filepath.Join(root, filepath.FromSlash(line)))
+	}
+
+	return files, nil
+}
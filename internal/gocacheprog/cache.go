@@ -0,0 +1,354 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package gocacheprog
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+)
+
+// Remote is a shared, slower tier. It's consulted on local misses, and
+// receives a copy of every local write.
+type Remote interface {
+	// Get writes the contents of key to w; returns false if it doesn't exist.
+	Get(ctx context.Context, key string, w io.Writer) (bool, error)
+	Put(ctx context.Context, key string, size int64, r io.ReadSeeker) error
+}
+
+// Cache keeps entries in a local directory, e.g. a cache volume, as cmd/go
+// requires outputs to be available on disk.
+type Cache struct {
+	dir    string
+	remote Remote
+	errors io.Writer
+
+	uploads sync.WaitGroup
+	stats   Stats
+}
+
+type Stats struct {
+	Gets, Hits, RemoteHits, Puts, Uploads atomic.Int64
+}
+
+func (s *Stats) String() string {
+	return fmt.Sprintf("%d gets (%d hits, %d from remote), %d puts (%d uploaded)",
+		s.Gets.Load(), s.Hits.Load(), s.RemoteHits.Load(), s.Puts.Load(), s.Uploads.Load())
+}
+
+type CacheOpts struct {
+	// If set, entries missing locally are fetched from the remote, and new
+	// entries are uploaded to it.
+	Remote Remote
+	// Failures to reach the remote don't fail builds; they're reported here.
+	Errors io.Writer
+}
+
+// actionEntry is what's stored for each action ID.
+type actionEntry struct {
+	OutputID string    `json:"output_id"`
+	Size     int64     `json:"size"`
+	Time     time.Time `json:"time"`
+}
+
+func NewCache(dir string, opts CacheOpts) (*Cache, error) {
+	for _, sub := range []string{"a", "o"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			return nil, fnerrors.Newf("failed to create cache directory: %w", err)
+		}
+	}
+
+	errs := opts.Errors
+	if errs == nil {
+		errs = io.Discard
+	}
+
+	return &Cache{dir: dir, remote: opts.Remote, errors: errs}, nil
+}
+
+func (c *Cache) Stats() *Stats { return &c.stats }
+
+// Get returns the entry for the action, and the path of its output on disk.
+// Returns a nil entry on a miss.
+func (c *Cache) Get(ctx context.Context, actionID []byte) (*Response, error) {
+	c.stats.Gets.Inc()
+
+	action := hex.EncodeToString(actionID)
+
+	entry, err := c.readAction(action)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry == nil && c.remote != nil {
+		entry, err = c.fetchRemote(ctx, action)
+		if err != nil {
+			fmt.Fprintf(c.errors, "gocacheprog: remote get %s: %v\n", action, err)
+			entry = nil
+		} else if entry != nil {
+			c.stats.RemoteHits.Inc()
+		}
+	}
+
+	if entry == nil {
+		return nil, nil
+	}
+
+	outputID, err := hex.DecodeString(entry.OutputID)
+	if err != nil {
+		return nil, nil
+	}
+
+	path := c.objectPath(entry.OutputID)
+	if st, err := os.Stat(path); err != nil || st.Size() != entry.Size {
+		// The output was evicted, or is incomplete.
+		return nil, nil
+	}
+
+	c.stats.Hits.Inc()
+
+	return &Response{
+		OutputID: outputID,
+		Size:     entry.Size,
+		Time:     &entry.Time,
+		DiskPath: path,
+	}, nil
+}
+
+// Put stores the output of the action, and returns its path on disk.
+func (c *Cache) Put(ctx context.Context, actionID, outputID []byte, body io.Reader, size int64) (string, error) {
+	c.stats.Puts.Inc()
+
+	action := hex.EncodeToString(actionID)
+	output := hex.EncodeToString(outputID)
+	if !validOutputID(output) {
+		return "", fnerrors.BadInputError("put: invalid output id %q", output)
+	}
+
+	path := c.objectPath(output)
+	if st, err := os.Stat(path); err != nil || st.Size() != size {
+		if err := writeAtomically(path, body, size); err != nil {
+			return "", err
+		}
+	}
+
+	entry := actionEntry{OutputID: output, Size: size, Time: time.Now()}
+	serialized, err := json.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+
+	if err := writeAtomically(c.actionPath(action), bytes.NewReader(serialized), int64(len(serialized))); err != nil {
+		return "", err
+	}
+
+	if c.remote != nil {
+		c.uploads.Add(1)
+		go func() {
+			defer c.uploads.Done()
+
+			// Uploads outlive the request that triggered them.
+			ctx := context.WithoutCancel(ctx)
+			if err := c.upload(ctx, action, output, serialized, size); err != nil {
+				fmt.Fprintf(c.errors, "gocacheprog: remote put %s: %v\n", action, err)
+			} else {
+				c.stats.Uploads.Inc()
+			}
+		}()
+	}
+
+	return path, nil
+}
+
+// Close waits for pending uploads to complete.
+func (c *Cache) Close() error {
+	c.uploads.Wait()
+	return nil
+}
+
+func (c *Cache) readAction(action string) (*actionEntry, error) {
+	contents, err := os.ReadFile(c.actionPath(action))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var entry actionEntry
+	if err := json.Unmarshal(contents, &entry); err != nil {
+		// Treat corrupt entries as misses; they'll be overwritten.
+		return nil, nil
+	}
+
+	if !validOutputID(entry.OutputID) {
+		return nil, nil
+	}
+
+	return &entry, nil
+}
+
+func (c *Cache) fetchRemote(ctx context.Context, action string) (*actionEntry, error) {
+	var buf bytes.Buffer
+	if found, err := c.remote.Get(ctx, remoteActionKey(action), &buf); err != nil || !found {
+		return nil, err
+	}
+
+	var entry actionEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		return nil, fnerrors.BadDataError("invalid action entry: %w", err)
+	}
+
+	if !validOutputID(entry.OutputID) {
+		return nil, fnerrors.BadDataError("invalid output id %q", entry.OutputID)
+	}
+
+	path := c.objectPath(entry.OutputID)
+	if st, err := os.Stat(path); err != nil || st.Size() != entry.Size {
+		if err := c.fetchObject(ctx, entry.OutputID, path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writeAtomically(c.actionPath(action), bytes.NewReader(buf.Bytes()), int64(buf.Len())); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+func (c *Cache) fetchObject(ctx context.Context, output, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	found, err := c.remote.Get(ctx, remoteObjectKey(output), f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return fnerrors.BadDataError("%s: output is missing", output)
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+func (c *Cache) upload(ctx context.Context, action, output string, serialized []byte, size int64) error {
+	f, err := os.Open(c.objectPath(output))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// The object goes first, so that remote action entries always point to
+	// existing objects.
+	if err := c.remote.Put(ctx, remoteObjectKey(output), size, f); err != nil {
+		return err
+	}
+
+	return c.remote.Put(ctx, remoteActionKey(action), int64(len(serialized)), bytes.NewReader(serialized))
+}
+
+func (c *Cache) actionPath(action string) string {
+	return filepath.Join(c.dir, "a", action[:2], action)
+}
+
+func (c *Cache) objectPath(output string) string {
+	return filepath.Join(c.dir, "o", output[:2], output)
+}
+
+// validOutputID returns true if output is a hex-encoded SHA-256 digest, as
+// produced by cmd/go. Entries are read from the remote, and output IDs become
+// file names, so anything else is rejected.
+func validOutputID(output string) bool {
+	if len(output) != 2*sha256.Size {
+		return false
+	}
+
+	for _, ch := range output {
+		if !('0' <= ch && ch <= '9' || 'a' <= ch && ch <= 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func remoteActionKey(action string) string { return "a/" + action }
+func remoteObjectKey(output string) string { return "o/" + output }
+
+func writeAtomically(path string, r io.Reader, size int64) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	n, err := io.Copy(f, r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if n != size {
+		return fnerrors.BadDataError("expected %d bytes, got %d", size, n)
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// DirRemote is a Remote backed by a local directory, e.g. a shared mount.
+type DirRemote string
+
+func (d DirRemote) Get(ctx context.Context, key string, w io.Writer) (bool, error) {
+	f, err := os.Open(filepath.Join(string(d), filepath.FromSlash(key)))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(w, f); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (d DirRemote) Put(ctx context.Context, key string, size int64, r io.ReadSeeker) error {
+	return writeAtomically(filepath.Join(string(d), filepath.FromSlash(key)), r, size)
+}
@@ -0,0 +1,46 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package gocacheprog
+
+import (
+	"io"
+	"time"
+)
+
+// The wire protocol spoken between cmd/go and a GOCACHEPROG helper. See
+// https://pkg.go.dev/cmd/go/internal/cacheprog.
+
+type Cmd string
+
+const (
+	CmdGet   Cmd = "get"
+	CmdPut   Cmd = "put"
+	CmdClose Cmd = "close"
+)
+
+type Request struct {
+	ID       int64
+	Command  Cmd
+	ActionID []byte    `json:",omitempty"`
+	OutputID []byte    `json:",omitempty"`
+	Body     io.Reader `json:"-"`
+	BodySize int64     `json:",omitempty"`
+	// Deprecated: sent by older versions of Go, in place of OutputID.
+	ObjectID []byte `json:",omitempty"`
+}
+
+type Response struct {
+	ID  int64
+	Err string `json:",omitempty"`
+
+	// Only set in the initial message, which has ID 0.
+	KnownCommands []Cmd `json:",omitempty"`
+
+	Miss     bool       `json:",omitempty"`
+	OutputID []byte     `json:",omitempty"`
+	Size     int64      `json:",omitempty"`
+	Time     *time.Time `json:",omitempty"`
+	DiskPath string     `json:",omitempty"`
+}
@@ -0,0 +1,134 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package gocacheprog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+
+	"namespacelabs.dev/foundation/internal/fnerrors"
+)
+
+// Serve handles requests from cmd/go (read from r), until it asks the helper
+// to close, or r is closed. Gets and puts are handled concurrently, and
+// responses are written to w as they complete.
+func Serve(ctx context.Context, cache *Cache, r io.Reader, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+
+	var mu sync.Mutex
+	respond := func(res *Response) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if err := enc.Encode(res); err != nil {
+			return err
+		}
+		return bw.Flush()
+	}
+
+	if err := respond(&Response{KnownCommands: []Cmd{CmdGet, CmdPut, CmdClose}}); err != nil {
+		return err
+	}
+
+	var inflight sync.WaitGroup
+	defer inflight.Wait()
+
+	dec := json.NewDecoder(bufio.NewReader(r))
+	for {
+		var req Request
+		if err := dec.Decode(&req); err != nil {
+			if errors.Is(err, io.EOF) {
+				inflight.Wait()
+				return cache.Close()
+			}
+			return fnerrors.BadDataError("failed to decode request: %w", err)
+		}
+
+		if req.Command == CmdPut && req.BodySize > 0 {
+			// The body follows the request, as a base64-encoded JSON string.
+			var body []byte
+			if err := dec.Decode(&body); err != nil {
+				return fnerrors.BadDataError("failed to decode request body: %w", err)
+			}
+
+			if int64(len(body)) != req.BodySize {
+				return fnerrors.BadDataError("request %d: expected a body of %d bytes, got %d", req.ID, req.BodySize, len(body))
+			}
+
+			req.Body = bytes.NewReader(body)
+		} else {
+			req.Body = bytes.NewReader(nil)
+		}
+
+		if req.Command == CmdClose {
+			inflight.Wait()
+
+			if err := cache.Close(); err != nil {
+				return err
+			}
+
+			return respond(&Response{ID: req.ID})
+		}
+
+		inflight.Add(1)
+		go func() {
+			defer inflight.Done()
+
+			res, err := handle(ctx, cache, req)
+			if err != nil {
+				res = &Response{Err: err.Error()}
+			}
+			res.ID = req.ID
+
+			_ = respond(res)
+		}()
+	}
+}
+
+func handle(ctx context.Context, cache *Cache, req Request) (*Response, error) {
+	if len(req.ActionID) == 0 {
+		return nil, fnerrors.BadInputError("%s: missing action id", req.Command)
+	}
+
+	switch req.Command {
+	case CmdGet:
+		res, err := cache.Get(ctx, req.ActionID)
+		if err != nil {
+			return nil, err
+		}
+
+		if res == nil {
+			return &Response{Miss: true}, nil
+		}
+
+		return res, nil
+
+	case CmdPut:
+		outputID := req.OutputID
+		if len(outputID) == 0 {
+			outputID = req.ObjectID
+		}
+
+		if len(outputID) == 0 {
+			return nil, fnerrors.BadInputError("put: missing output id")
+		}
+
+		path, err := cache.Put(ctx, req.ActionID, outputID, req.Body, req.BodySize)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Response{DiskPath: path}, nil
+
+	default:
+		return nil, fnerrors.BadInputError("%s: unknown command", req.Command)
+	}
+}
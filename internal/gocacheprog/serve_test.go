@@ -0,0 +1,155 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package gocacheprog
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServe(t *testing.T) {
+	remote := DirRemote(t.TempDir())
+
+	actionID := bytes.Repeat([]byte{0xaa}, 32)
+	outputID := bytes.Repeat([]byte{0xbb}, 32)
+	body := []byte("compiled output")
+
+	var in bytes.Buffer
+	enc := json.NewEncoder(&in)
+	_ = enc.Encode(Request{ID: 1, Command: CmdGet, ActionID: actionID})
+
+	responses := serve(t, remote, in.Bytes())
+	if !responses[1].Miss {
+		t.Errorf("expected a miss before put, got %+v", responses[1])
+	}
+
+	in.Reset()
+	_ = enc.Encode(Request{ID: 1, Command: CmdPut, ActionID: actionID, OutputID: outputID, BodySize: int64(len(body))})
+	_ = enc.Encode(body)
+	_ = enc.Encode(Request{ID: 2, Command: CmdClose})
+
+	responses = serve(t, remote, in.Bytes())
+	if responses[1].DiskPath == "" {
+		t.Fatalf("expected put to return a disk path, got %+v", responses[1])
+	}
+
+	// A new, empty local cache is populated from the remote.
+	in.Reset()
+	_ = enc.Encode(Request{ID: 1, Command: CmdGet, ActionID: actionID})
+
+	responses = serve(t, remote, in.Bytes())
+	got := responses[1]
+	if got.Miss || !bytes.Equal(got.OutputID, outputID) || got.Size != int64(len(body)) {
+		t.Fatalf("expected a hit from the remote, got %+v", got)
+	}
+
+	contents, err := os.ReadFile(got.DiskPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(contents, body) {
+		t.Errorf("got %q, want %q", contents, body)
+	}
+}
+
+func TestGetRejectsInvalidRemoteEntries(t *testing.T) {
+	actionID := bytes.Repeat([]byte{0xaa}, 32)
+
+	for _, output := range []string{
+		"",
+		"a",
+		"../../../../" + strings.Repeat("0", 52),
+		strings.Repeat("A", 64),
+	} {
+		remote := DirRemote(t.TempDir())
+
+		entry, err := json.Marshal(actionEntry{OutputID: output, Size: 1, Time: time.Now()})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := remote.Put(context.Background(), remoteActionKey(hex.EncodeToString(actionID)), int64(len(entry)), bytes.NewReader(entry)); err != nil {
+			t.Fatal(err)
+		}
+
+		var errs bytes.Buffer
+		cache, err := NewCache(t.TempDir(), CacheOpts{Remote: remote, Errors: &errs})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := cache.Get(context.Background(), actionID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res != nil {
+			t.Errorf("%q: expected a miss, got %+v", output, res)
+		}
+
+		if !strings.Contains(errs.String(), "invalid output id") {
+			t.Errorf("%q: expected the entry to be rejected, got %q", output, errs.String())
+		}
+	}
+}
+
+func TestGetIgnoresInvalidLocalEntries(t *testing.T) {
+	actionID := bytes.Repeat([]byte{0xaa}, 32)
+
+	cache, err := NewCache(t.TempDir(), CacheOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry := []byte(`{"output_id":"","size":0}`)
+	if err := writeAtomically(cache.actionPath(hex.EncodeToString(actionID)), bytes.NewReader(entry), int64(len(entry))); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := cache.Get(context.Background(), actionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res != nil {
+		t.Errorf("expected a miss, got %+v", res)
+	}
+}
+
+func serve(t *testing.T, remote Remote, input []byte) map[int64]Response {
+	cache, err := NewCache(t.TempDir(), CacheOpts{Remote: remote})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := Serve(context.Background(), cache, bytes.NewReader(input), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	responses := map[int64]Response{}
+	dec := json.NewDecoder(strings.NewReader(out.String()))
+	for dec.More() {
+		var res Response
+		if err := dec.Decode(&res); err != nil {
+			t.Fatal(err)
+		}
+
+		if res.Err != "" {
+			t.Fatalf("request %d failed: %s", res.ID, res.Err)
+		}
+
+		responses[res.ID] = res
+	}
+
+	return responses
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/moby/buildkit/client/llb"
 	"github.com/moby/buildkit/frontend/dockerfile/dockerignore"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
 	"github.com/moby/buildkit/frontend/dockerui"
 	specs "github.com/opencontainers/image-spec/specs-go/v1"
 	"golang.org/x/exp/maps"
@@ -90,6 +91,40 @@ func (df dockerfileBuild) BuildImage(ctx context.Context, env pkggraph.SealedCon
 
 func (df dockerfileBuild) PlatformIndependent() bool { return false }
 
+// BaseImages returns the images that stages are built from. Images whose
+// names depend on build arguments are not reported.
+func (df dockerfileBuild) BaseImages(ws build.Workspace) ([]string, error) {
+	dfcontents, err := fs.ReadFile(ws.ReadOnlyFS(df.contextRel), df.plan.Dockerfile)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := parser.Parse(bytes.NewReader(dfcontents))
+	if err != nil {
+		return nil, fnerrors.Newf("failed to parse Dockerfile: %w", err)
+	}
+
+	stages := map[string]bool{}
+	var images []string
+	for _, node := range parsed.AST.Children {
+		if !strings.EqualFold(node.Value, "from") || node.Next == nil {
+			continue
+		}
+
+		base := node.Next.Value
+		if !stages[strings.ToLower(base)] && base != "scratch" && !strings.Contains(base, "$") {
+			images = append(images, base)
+		}
+
+		// FROM image AS stage
+		if n := node.Next.Next; n != nil && strings.EqualFold(n.Value, "as") && n.Next != nil {
+			stages[strings.ToLower(n.Next.Value)] = true
+		}
+	}
+
+	return images, nil
+}
+
 func (df dockerfileBuild) Description() string {
 	return fmt.Sprintf("fromDockerfile(%s)", filepath.Join(df.contextRel, df.plan.Dockerfile))
 }
@@ -0,0 +1,223 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package keys
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"namespacelabs.dev/foundation/internal/bytestream"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/internal/fnfs"
+)
+
+// Image signing keys live under the "signing" directory of the keys
+// directory: <name>.key holds an ECDSA P-256 private key (PKCS#8, PEM) and
+// <name>.pub its public key (PKIX, PEM), which is the format that cosign
+// accepts with `cosign verify --key`. Every .pub file in the directory is
+// trusted when verifying signatures, so public keys of other signers (e.g.
+// CI) can be added by copying them there.
+const signingDir = "signing"
+
+func GenerateSigningKey(ctx context.Context, name string) (*ecdsa.PrivateKey, error) {
+	keysDir, err := EnsureKeysDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := fs.Stat(keysDir, path.Join(signingDir, name+".key")); err == nil {
+		return nil, fnerrors.BadInputError("%s: signing key already exists", name)
+	}
+
+	if err := keysDir.MkdirAll(signingDir, 0700); err != nil {
+		return nil, err
+	}
+
+	pk, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	privBytes, err := x509.MarshalPKCS8PrivateKey(pk)
+	if err != nil {
+		return nil, err
+	}
+
+	pubBytes, err := MarshalSigningPublicKey(&pk.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fnfs.WriteFile(ctx, keysDir, path.Join(signingDir, name+".key"), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privBytes}), 0600); err != nil {
+		return nil, err
+	}
+
+	if err := fnfs.WriteFile(ctx, keysDir, path.Join(signingDir, name+".pub"), pubBytes, 0644); err != nil {
+		return nil, err
+	}
+
+	return pk, nil
+}
+
+// SigningKey returns the private key with the specified name. If no name is
+// specified, then there must be exactly one signing key.
+func SigningKey(name string) (*ecdsa.PrivateKey, error) {
+	keysDir, err := KeysDir()
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		names, err := listSigningKeys(keysDir, ".key")
+		if err != nil {
+			return nil, err
+		}
+
+		switch len(names) {
+		case 0:
+			return nil, fnerrors.UsageError("Run `ns keys signing generate`.", "No image signing key available.")
+		case 1:
+			name = names[0]
+		default:
+			return nil, fnerrors.BadInputError("more than one signing key available (%s), please select one", strings.Join(names, ", "))
+		}
+	}
+
+	contents, err := fs.ReadFile(keysDir, path.Join(signingDir, name+".key"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fnerrors.BadInputError("%s: no such signing key", name)
+		}
+		return nil, err
+	}
+
+	block, _ := pem.Decode(contents)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, fnerrors.BadDataError("%s: expected a PEM-encoded private key", name)
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fnerrors.BadDataError("%s: %w", name, err)
+	}
+
+	pk, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fnerrors.BadDataError("%s: expected an ECDSA key", name)
+	}
+
+	return pk, nil
+}
+
+// TrustedSigningKeys returns the public keys that image signatures are
+// verified against.
+func TrustedSigningKeys(ctx context.Context) (map[string]*ecdsa.PublicKey, error) {
+	keysDir, err := KeysDir()
+	if err != nil {
+		return nil, err
+	}
+
+	trusted := map[string]*ecdsa.PublicKey{}
+	if err := fnfs.VisitFiles(ctx, keysDir, func(p string, blob bytestream.ByteStream, _ fs.DirEntry) error {
+		if path.Dir(p) != signingDir || path.Ext(p) != ".pub" {
+			return nil
+		}
+
+		contents, err := bytestream.ReadAll(blob)
+		if err != nil {
+			return err
+		}
+
+		pub, err := ParseSigningPublicKey(contents)
+		if err != nil {
+			return fnerrors.BadInputError("%s: %w", p, err)
+		}
+
+		trusted[strings.TrimSuffix(path.Base(p), ".pub")] = pub
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return trusted, nil
+}
+
+// TrustSigningKey adds a public key to the set of trusted signing keys.
+func TrustSigningKey(ctx context.Context, name string, contents []byte) error {
+	if _, err := ParseSigningPublicKey(contents); err != nil {
+		return fnerrors.BadInputError("%s: %w", name, err)
+	}
+
+	keysDir, err := EnsureKeysDir(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fs.Stat(keysDir, path.Join(signingDir, name+".pub")); err == nil {
+		return fnerrors.BadInputError("%s: signing key already exists, won't be overwritten", name)
+	}
+
+	if err := keysDir.MkdirAll(signingDir, 0700); err != nil {
+		return err
+	}
+
+	return fnfs.WriteFile(ctx, keysDir, path.Join(signingDir, name+".pub"), contents, 0644)
+}
+
+func MarshalSigningPublicKey(pub *ecdsa.PublicKey) ([]byte, error) {
+	pubBytes, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubBytes}), nil
+}
+
+func ParseSigningPublicKey(contents []byte) (*ecdsa.PublicKey, error) {
+	block, _ := pem.Decode(contents)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, fnerrors.BadDataError("expected a PEM-encoded public key")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	pub, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fnerrors.BadDataError("expected an ECDSA public key")
+	}
+
+	return pub, nil
+}
+
+func listSigningKeys(keysDir fs.FS, ext string) ([]string, error) {
+	entries, err := fs.ReadDir(keysDir, signingDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && filepath.Ext(e.Name()) == ext {
+			names = append(names, strings.TrimSuffix(e.Name(), ext))
+		}
+	}
+
+	return names, nil
+}
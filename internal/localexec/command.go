@@ -11,6 +11,7 @@ import (
 
 	"namespacelabs.dev/foundation/internal/console"
 	"namespacelabs.dev/foundation/std/tasks"
+	"namespacelabs.dev/foundation/std/tasks/actiontracing"
 )
 
 type Command struct {
@@ -40,6 +41,8 @@ func (c Command) Run(ctx context.Context) error {
 		cmd.Stdout = out
 		cmd.Stderr = out
 		cmd.Env = append(os.Environ(), c.AdditionalEnv...)
+		// Lets tools that support OpenTelemetry join the invocation's trace.
+		cmd.Env = append(cmd.Env, actiontracing.Environ(ctx)...)
 
 		if err := RunAndPropagateCancelation(ctx, c.label(), cmd); err != nil {
 			return console.WithLogs(ctx, err)
@@ -0,0 +1,73 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package logtail
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"namespacelabs.dev/foundation/internal/console/colors"
+	"namespacelabs.dev/foundation/internal/runtime"
+	runtimepb "namespacelabs.dev/foundation/schema/runtime"
+)
+
+// PrefixedWriter writes log lines from multiple servers into a single stream,
+// prefixing each line with the server (and container, other than the main
+// one) it originates from.
+type PrefixedWriter struct {
+	out   io.Writer
+	style colors.Style
+
+	mu      sync.Mutex
+	sources map[string]colors.Applicable
+	width   int
+}
+
+func NewPrefixedWriter(ctx context.Context, out io.Writer) *PrefixedWriter {
+	return &PrefixedWriter{
+		out:     out,
+		style:   colors.Ctx(ctx),
+		sources: map[string]colors.Applicable{},
+	}
+}
+
+// Write can be passed to runtime.ClusterNamespace.FetchServerLogs.
+func (w *PrefixedWriter) Write(line runtime.ServerLogLine) {
+	source := sourceName(line.Server.GetName(), line.Container)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	color, ok := w.sources[source]
+	if !ok {
+		color = w.style.Header
+		if palette := w.style.LogSources; len(palette) > 0 {
+			color = palette[len(w.sources)%len(palette)]
+		}
+		w.sources[source] = color
+	}
+
+	w.width = max(w.width, len(source))
+	prefix := color.Apply(source + strings.Repeat(" ", w.width-len(source)))
+
+	switch line.Event {
+	case runtime.ContainerLogLineEvent_LogLine:
+		fmt.Fprintf(w.out, "%s | %s\n", prefix, line.LogLine)
+
+	case runtime.ContainerLogLineEvent_Resuming:
+		fmt.Fprintf(w.out, "%s | %s\n", prefix, w.style.Comment.Apply(fmt.Sprintf("resuming logging (disconnected with: %v)...", line.ResumeErr)))
+	}
+}
+
+func sourceName(server string, ref *runtimepb.ContainerReference) string {
+	if ref.GetKind() == runtimepb.ContainerKind_PRIMARY {
+		return server
+	}
+
+	return fmt.Sprintf("%s:%s", server, ref.GetHumanReference())
+}
@@ -0,0 +1,40 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package logtail
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"namespacelabs.dev/foundation/internal/runtime"
+	"namespacelabs.dev/foundation/schema"
+	runtimepb "namespacelabs.dev/foundation/schema/runtime"
+)
+
+func TestPrefixedWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := NewPrefixedWriter(context.Background(), &out)
+
+	line := func(server string, container *runtimepb.ContainerReference, text string) runtime.ServerLogLine {
+		return runtime.ServerLogLine{
+			ContainerLogLine: runtime.ContainerLogLine{Event: runtime.ContainerLogLineEvent_LogLine, LogLine: []byte(text)},
+			Server:           &schema.Server{Name: server},
+			Container:        container,
+		}
+	}
+
+	primary := &runtimepb.ContainerReference{Kind: runtimepb.ContainerKind_PRIMARY, HumanReference: "main"}
+	sidecar := &runtimepb.ContainerReference{Kind: runtimepb.ContainerKind_SUPPORT, HumanReference: "proxy"}
+
+	w.Write(line("api", primary, "first"))
+	w.Write(line("frontend", sidecar, "second"))
+	w.Write(line("api", primary, "third"))
+
+	want := "api | first\nfrontend:proxy | second\napi            | third\n"
+	if got := out.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
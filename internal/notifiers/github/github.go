@@ -0,0 +1,195 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"namespacelabs.dev/foundation/framework/deploy"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/internal/git"
+	"namespacelabs.dev/foundation/internal/notifiers"
+	"namespacelabs.dev/foundation/std/cfg"
+)
+
+const (
+	defaultAPIEndpoint = "https://api.github.com"
+	// GitHub rejects status descriptions longer than this.
+	maxDescriptionLen = 140
+)
+
+func Register() {
+	notifiers.RegisterNotifier(func(ctx context.Context, env cfg.Context, conf *deploy.GitHubNotifier) (notifiers.Notifier, error) {
+		if len(strings.Split(conf.Repository, "/")) != 2 {
+			return nil, fnerrors.BadInputError("github: repository must be of the form owner/repo, got %q", conf.Repository)
+		}
+
+		if conf.TokenSecretRef == "" {
+			return nil, fnerrors.BadInputError("github: token_secret_ref is required")
+		}
+
+		token, err := notifiers.LoadSecret(ctx, env, conf.TokenSecretRef)
+		if err != nil {
+			return nil, err
+		}
+
+		ref := os.ExpandEnv(conf.Ref)
+		if ref == "" {
+			ref = os.Getenv("GITHUB_SHA")
+		}
+
+		if ref == "" {
+			status, err := git.FetchStatus(ctx, env.Workspace().LoadedFrom().AbsPath)
+			if err != nil {
+				return nil, err
+			}
+			ref = status.Revision
+		}
+
+		if ref == "" {
+			return nil, fnerrors.BadInputError("github: unable to determine which commit is being deployed, please set ref")
+		}
+
+		environment := conf.Environment
+		if environment == "" {
+			environment = env.Environment().Name
+		}
+
+		endpoint := conf.ApiEndpoint
+		if endpoint == "" {
+			endpoint = defaultAPIEndpoint
+		}
+
+		return &notifier{
+			endpoint:    strings.TrimSuffix(endpoint, "/") + "/repos/" + conf.Repository,
+			token:       strings.TrimSpace(string(token)),
+			ref:         ref,
+			environment: environment,
+		}, nil
+	})
+}
+
+type notifier struct {
+	endpoint    string
+	token       string
+	ref         string
+	environment string
+
+	deploymentID int64
+}
+
+func (n *notifier) Notify(ctx context.Context, ev notifiers.Event) error {
+	if ev.Stage == notifiers.StageStarted {
+		var servers []string
+		for _, entry := range ev.Plan.GetStack().GetEntry() {
+			servers = append(servers, entry.GetPackageName().String())
+		}
+
+		var deployment struct {
+			ID int64 `json:"id"`
+		}
+
+		if err := n.post(ctx, "/deployments", map[string]any{
+			"ref":         n.ref,
+			"environment": n.environment,
+			"description": truncate(ev.Reason),
+			"auto_merge":  false,
+			// Don't require any commit status checks to pass.
+			"required_contexts": []string{},
+			"payload":           map[string]any{"servers": servers},
+		}, &deployment); err != nil {
+			return err
+		}
+
+		n.deploymentID = deployment.ID
+	}
+
+	if n.deploymentID == 0 {
+		// Creating the deployment failed.
+		return nil
+	}
+
+	status := map[string]any{
+		"environment": n.environment,
+	}
+
+	if logURL := logURL(); logURL != "" {
+		status["log_url"] = logURL
+	}
+
+	switch {
+	case ev.Stage != notifiers.StageFinished:
+		status["state"] = "in_progress"
+		status["description"] = truncate(ev.Message)
+	case ev.Err != nil:
+		status["state"] = "failure"
+		status["description"] = truncate(ev.Err.Error())
+	default:
+		status["state"] = "success"
+		status["description"] = truncate(fmt.Sprintf("Deployed in %v.", ev.Completed.Sub(ev.Started).Round(time.Second)))
+	}
+
+	return n.post(ctx, fmt.Sprintf("/deployments/%d/statuses", n.deploymentID), status, nil)
+}
+
+func (n *notifier) post(ctx context.Context, path string, body, out any) error {
+	serialized, err := json.Marshal(body)
+	if err != nil {
+		return fnerrors.InternalError("failed to serialize request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.endpoint+path, bytes.NewReader(serialized))
+	if err != nil {
+		return fnerrors.InvocationError("github", "failed to create HTTP request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+n.token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := notifiers.HTTPClient.Do(req)
+	if err != nil {
+		return fnerrors.InvocationError("github", "request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fnerrors.InvocationError("github", "%s returned %v", path, resp.Status)
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fnerrors.InvocationError("github", "bad response: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func logURL() string {
+	if runID := os.Getenv("GITHUB_RUN_ID"); runID != "" {
+		return fmt.Sprintf("%s/%s/actions/runs/%s", os.Getenv("GITHUB_SERVER_URL"), os.Getenv("GITHUB_REPOSITORY"), runID)
+	}
+
+	return os.Getenv("BUILDKITE_BUILD_URL")
+}
+
+// truncate limits str to maxDescriptionLen characters, without splitting
+// multi-byte runes, which GitHub would reject as invalid UTF-8.
+func truncate(str string) string {
+	runes := []rune(str)
+	if len(runes) > maxDescriptionLen {
+		return string(runes[:maxDescriptionLen-3]) + "..."
+	}
+	return str
+}
@@ -0,0 +1,139 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"namespacelabs.dev/foundation/internal/notifiers"
+	"namespacelabs.dev/foundation/schema"
+)
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("short"); got != "short" {
+		t.Errorf("expected short descriptions to be kept, got %q", got)
+	}
+
+	got := truncate(strings.Repeat("é", 200))
+	if !utf8.ValidString(got) {
+		t.Errorf("expected valid UTF-8, got %q", got)
+	}
+
+	if n := utf8.RuneCountInString(got); n != maxDescriptionLen {
+		t.Errorf("expected %d characters, got %d", maxDescriptionLen, n)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	type request struct {
+		Path string
+		Body map[string]any
+	}
+
+	var requests []request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer token" {
+			t.Errorf("bad authorization %q", auth)
+		}
+
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+
+		requests = append(requests, request{r.URL.Path, body})
+
+		if r.URL.Path == "/repos/owner/repo/deployments" {
+			fmt.Fprint(w, `{"id": 42}`)
+		}
+	}))
+	defer srv.Close()
+
+	n := &notifier{endpoint: srv.URL + "/repos/owner/repo", token: "token", ref: "abc", environment: "prod"}
+
+	plan := &schema.DeployPlan{
+		Stack: &schema.Stack{Entry: []*schema.Stack_Entry{{Server: &schema.Server{PackageName: "example.com/server"}}}},
+	}
+
+	start := time.Unix(1000, 0)
+	for _, ev := range []notifiers.Event{
+		{Stage: notifiers.StageStarted, Plan: plan, Reason: "release", Started: start},
+		{Stage: notifiers.StageFinished, Plan: plan, Started: start, Completed: start.Add(3 * time.Second)},
+	} {
+		if err := n.Notify(context.Background(), ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(requests) != 3 {
+		t.Fatalf("expected 3 requests, got %+v", requests)
+	}
+
+	if got := requests[0]; got.Path != "/repos/owner/repo/deployments" || got.Body["ref"] != "abc" || got.Body["environment"] != "prod" || got.Body["description"] != "release" {
+		t.Errorf("unexpected deployment request: %+v", got)
+	}
+
+	if servers := requests[0].Body["payload"].(map[string]any)["servers"]; !reflect.DeepEqual(servers, []any{"example.com/server"}) {
+		t.Errorf("unexpected servers: %v", servers)
+	}
+
+	for k, state := range []string{"in_progress", "success"} {
+		got := requests[k+1]
+		if got.Path != "/repos/owner/repo/deployments/42/statuses" || got.Body["state"] != state {
+			t.Errorf("expected a %s status, got %+v", state, got)
+		}
+	}
+
+	if desc := requests[2].Body["description"]; desc != "Deployed in 3s." {
+		t.Errorf("unexpected description %q", desc)
+	}
+}
+
+func TestNotifyFailures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/fail/deployments":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		case "/hang/deployments":
+			<-r.Context().Done()
+		}
+	}))
+	defer srv.Close()
+
+	ev := notifiers.Event{Stage: notifiers.StageStarted, Plan: &schema.DeployPlan{}}
+
+	failing := &notifier{endpoint: srv.URL + "/fail"}
+	if err := failing.Notify(context.Background(), ev); err == nil || !strings.Contains(err.Error(), "422") {
+		t.Errorf("expected the request to fail, got %v", err)
+	}
+
+	// Failing to create the deployment skips later updates.
+	if err := failing.Notify(context.Background(), withStage(ev, notifiers.StageFinished)); err != nil {
+		t.Errorf("expected updates to be skipped, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	hanging := &notifier{endpoint: srv.URL + "/hang"}
+	if err := hanging.Notify(ctx, ev); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the request to time out, got %v", err)
+	}
+}
+
+func withStage(ev notifiers.Event, stage notifiers.Stage) notifiers.Event {
+	ev.Stage = stage
+	return ev
+}
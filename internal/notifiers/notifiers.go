@@ -0,0 +1,158 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package notifiers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	deploypb "namespacelabs.dev/foundation/framework/deploy"
+	"namespacelabs.dev/foundation/framework/secrets"
+	"namespacelabs.dev/foundation/framework/secrets/combined"
+	"namespacelabs.dev/foundation/internal/console"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/internal/parsing"
+	"namespacelabs.dev/foundation/internal/planning/deploy"
+	"namespacelabs.dev/foundation/internal/protos"
+	"namespacelabs.dev/foundation/schema"
+	"namespacelabs.dev/foundation/std/cfg"
+)
+
+type Stage string
+
+const (
+	StageStarted  Stage = "started"
+	StageProgress Stage = "progress"
+	StageFinished Stage = "finished"
+)
+
+type Event struct {
+	Stage Stage
+	Plan  *schema.DeployPlan
+	// The reason for the deployment, as specified by the user.
+	Reason    string
+	Started   time.Time
+	Completed time.Time // Only set when Stage is StageFinished.
+	// A human-readable description of the progress, e.g. a resource became ready.
+	Message string
+	// If the deployment failed, the resulting error. Only set when Stage is StageFinished.
+	Err error
+}
+
+func (ev Event) EnvName() string {
+	return ev.Plan.GetEnvironment().GetName()
+}
+
+// Notifiers are called inline while deploying, so their requests must not be
+// able to stall a deployment.
+var HTTPClient = &http.Client{Timeout: 15 * time.Second}
+
+type Notifier interface {
+	Notify(context.Context, Event) error
+}
+
+var providers = map[string]func(context.Context, cfg.Context, *anypb.Any) (Notifier, error){}
+
+// RegisterNotifier registers a notifier implementation, which is instantiated
+// for each environment whose deployment configuration includes a notifier of
+// type V.
+func RegisterNotifier[V proto.Message](make func(context.Context, cfg.Context, V) (Notifier, error)) {
+	providers[protos.TypeUrl[V]()] = func(ctx context.Context, env cfg.Context, input *anypb.Any) (Notifier, error) {
+		msg := protos.NewFromType[V]()
+		if err := input.UnmarshalTo(msg); err != nil {
+			return nil, err
+		}
+
+		return make(ctx, env, msg)
+	}
+}
+
+// Notifiers fans out deployment events to a set of notifiers. Failing to
+// notify is never fatal, and only results in a warning.
+type Notifiers []Notifier
+
+func (ns Notifiers) Notify(ctx context.Context, ev Event) {
+	for _, n := range ns {
+		if err := n.Notify(ctx, ev); err != nil {
+			fmt.Fprintf(console.Warnings(ctx), "Failed to notify of deployment %s: %v\n", ev.Stage, err)
+		}
+	}
+}
+
+// Prepare instantiates the notifiers configured for the specified environment.
+// If slackOverride is set, it replaces the legacy update_slack_channel
+// configuration, so that the same channel isn't updated twice.
+func Prepare(ctx context.Context, env cfg.Context, slackOverride Notifier) (Notifiers, error) {
+	var notifiers Notifiers
+	if slackOverride != nil {
+		notifiers = append(notifiers, slackOverride)
+	}
+
+	conf, ok := deploy.GetConfig(env.Configuration())
+	if !ok {
+		return notifiers, nil
+	}
+
+	configured := conf.Notifier
+	if conf.UpdateSlackChannel != "" && slackOverride == nil {
+		legacy, err := anypb.New(&deploypb.SlackNotifier{
+			Channel:           conf.UpdateSlackChannel,
+			BotTokenSecretRef: conf.SlackBotTokenSecretRef,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		configured = append([]*anypb.Any{legacy}, configured...)
+	}
+
+	for _, c := range configured {
+		provider, ok := providers[c.TypeUrl]
+		if !ok {
+			return nil, fnerrors.BadInputError("%s: no such notifier", c.TypeUrl)
+		}
+
+		n, err := provider(ctx, env, c)
+		if err != nil {
+			return nil, fnerrors.Newf("%s: failed to prepare notifier: %w", c.TypeUrl, err)
+		}
+
+		notifiers = append(notifiers, n)
+	}
+
+	return notifiers, nil
+}
+
+// LoadSecret loads the value of the secret referred to by `secretRef`, in the
+// specified environment.
+func LoadSecret(ctx context.Context, env cfg.Context, secretRef string) ([]byte, error) {
+	ref, err := schema.StrictParsePackageRef(secretRef)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := combined.NewCombinedSecrets(env)
+	if err != nil {
+		return nil, err
+	}
+
+	pl := parsing.NewPackageLoader(env)
+	if _, err := pl.LoadByName(ctx, ref.AsPackageName()); err != nil {
+		return nil, err
+	}
+
+	res, err := source.Load(ctx, pl.Seal(), &secrets.SecretLoadRequest{
+		SecretRef: ref,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Value, nil
+}
@@ -2,10 +2,9 @@
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
 
-package orchestration
+package slack
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -13,49 +12,10 @@ import (
 
 	"github.com/slack-go/slack"
 	"k8s.io/utils/strings/slices"
-	"namespacelabs.dev/foundation/framework/secrets"
-	"namespacelabs.dev/foundation/framework/secrets/combined"
-	"namespacelabs.dev/foundation/internal/parsing"
-	"namespacelabs.dev/foundation/internal/planning/deploy"
 	"namespacelabs.dev/foundation/schema"
-	"namespacelabs.dev/foundation/std/cfg"
 )
 
-func resolveSlackTokenAndChannel(ctx context.Context, env cfg.Context) (string, string, error) {
-	if DeployUpdateSlackChannel != "" {
-		return os.ExpandEnv(SlackToken), os.ExpandEnv(DeployUpdateSlackChannel), nil
-	}
-
-	if conf, ok := deploy.GetConfig(env.Configuration()); ok && conf.UpdateSlackChannel != "" {
-		ref, err := schema.StrictParsePackageRef(conf.SlackBotTokenSecretRef)
-		if err != nil {
-			return "", "", err
-		}
-
-		source, err := combined.NewCombinedSecrets(env)
-		if err != nil {
-			return "", "", err
-		}
-
-		pl := parsing.NewPackageLoader(env)
-		if _, err := pl.LoadByName(ctx, ref.AsPackageName()); err != nil {
-			return "", "", err
-		}
-
-		res, err := source.Load(ctx, pl.Seal(), &secrets.SecretLoadRequest{
-			SecretRef: ref,
-		})
-		if err != nil {
-			return "", "", err
-		}
-
-		return string(res.Value), conf.UpdateSlackChannel, nil
-	}
-
-	return "", "", nil
-}
-
-func renderSlackMessage(plan *schema.DeployPlan, start, end time.Time, message string, err error) []slack.Block {
+func renderSlackMessage(plan *schema.DeployPlan, start, end time.Time, message, progress string, err error) []slack.Block {
 	var blocks []slack.Block
 	blocks = append(blocks, slack.NewHeaderBlock(slack.NewTextBlockObject(slack.PlainTextType, timeEmoji(end, err)+" "+deployLabel(end), true, false)))
 
@@ -71,6 +31,9 @@ func renderSlackMessage(plan *schema.DeployPlan, start, end time.Time, message s
 
 	blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType,
 		strings.Join(servers(plan), "\n"), false, false), nil, nil))
+	if end.IsZero() && progress != "" {
+		blocks = append(blocks, slack.NewContextBlock("", slack.NewTextBlockObject(slack.PlainTextType, progress, false, false)))
+	}
 	if !end.IsZero() {
 		blocks = append(blocks, slack.NewContextBlock("", slack.NewTextBlockObject(slack.MarkdownType, maybeTook(start, end), false, false)))
 	}
@@ -0,0 +1,69 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package slack
+
+import (
+	"context"
+
+	"github.com/slack-go/slack"
+	"namespacelabs.dev/foundation/framework/deploy"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/internal/notifiers"
+	"namespacelabs.dev/foundation/std/cfg"
+)
+
+func Register() {
+	notifiers.RegisterNotifier(func(ctx context.Context, env cfg.Context, conf *deploy.SlackNotifier) (notifiers.Notifier, error) {
+		if conf.Channel == "" {
+			return nil, fnerrors.BadInputError("slack: channel is required")
+		}
+
+		if conf.BotTokenSecretRef == "" {
+			return nil, fnerrors.BadInputError("a slack token is required to be able to update a channel")
+		}
+
+		token, err := notifiers.LoadSecret(ctx, env, conf.BotTokenSecretRef)
+		if err != nil {
+			return nil, err
+		}
+
+		return New(string(token), conf.Channel), nil
+	})
+}
+
+// New returns a notifier which posts a message to the specified channel when
+// a deployment starts, and updates it as the deployment progresses.
+func New(token, channel string) notifiers.Notifier {
+	return &notifier{cli: slack.New(token, slack.OptionHTTPClient(notifiers.HTTPClient)), channel: channel}
+}
+
+type notifier struct {
+	cli     *slack.Client
+	channel string
+
+	chid, ts string
+}
+
+func (n *notifier) Notify(ctx context.Context, ev notifiers.Event) error {
+	blocks := slack.MsgOptionBlocks(renderSlackMessage(ev.Plan, ev.Started, ev.Completed, ev.Reason, ev.Message, ev.Err)...)
+
+	if ev.Stage == notifiers.StageStarted {
+		chid, ts, err := n.cli.PostMessageContext(ctx, n.channel, blocks)
+		if err != nil {
+			return err
+		}
+
+		n.chid, n.ts = chid, ts
+		return nil
+	}
+
+	if n.ts == "" {
+		// The initial message was never posted.
+		return nil
+	}
+
+	_, _, _, err := n.cli.UpdateMessageContext(ctx, n.chid, n.ts, blocks)
+	return err
+}
@@ -0,0 +1,140 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"namespacelabs.dev/foundation/framework/deploy"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/internal/notifiers"
+	"namespacelabs.dev/foundation/std/cfg"
+)
+
+const (
+	SignatureHeader = "X-Namespace-Signature"
+	TimestampHeader = "X-Namespace-Timestamp"
+)
+
+func Register() {
+	notifiers.RegisterNotifier(func(ctx context.Context, env cfg.Context, conf *deploy.WebhookNotifier) (notifiers.Notifier, error) {
+		if conf.Url == "" {
+			return nil, fnerrors.BadInputError("webhook: url is required")
+		}
+
+		n := &notifier{conf: conf}
+		if conf.SigningSecretRef != "" {
+			key, err := notifiers.LoadSecret(ctx, env, conf.SigningSecretRef)
+			if err != nil {
+				return nil, err
+			}
+			n.signingKey = key
+		}
+
+		return n, nil
+	})
+}
+
+type notifier struct {
+	conf       *deploy.WebhookNotifier
+	signingKey []byte
+}
+
+// Payload is the JSON document that is posted to the webhook.
+type Payload struct {
+	Stage       string     `json:"stage"`
+	Environment string     `json:"environment"`
+	Workspace   string     `json:"workspace,omitempty"`
+	Servers     []string   `json:"servers,omitempty"`
+	Focus       []string   `json:"focus,omitempty"`
+	Reason      string     `json:"reason,omitempty"`
+	Message     string     `json:"message,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	Started     time.Time  `json:"started"`
+	Completed   *time.Time `json:"completed,omitempty"`
+	BuildURL    string     `json:"build_url,omitempty"`
+}
+
+func MakePayload(ev notifiers.Event) Payload {
+	p := Payload{
+		Stage:       string(ev.Stage),
+		Environment: ev.EnvName(),
+		Workspace:   ev.Plan.GetWorkspace().GetModuleName(),
+		Focus:       ev.Plan.GetFocusServer(),
+		Reason:      ev.Reason,
+		Message:     ev.Message,
+		Started:     ev.Started,
+		BuildURL:    os.Getenv("BUILDKITE_BUILD_URL"),
+	}
+
+	for _, entry := range ev.Plan.GetStack().GetEntry() {
+		p.Servers = append(p.Servers, entry.GetPackageName().String())
+	}
+
+	if !ev.Completed.IsZero() {
+		p.Completed = &ev.Completed
+	}
+
+	if ev.Err != nil {
+		p.Error = ev.Err.Error()
+	}
+
+	return p
+}
+
+// Sign returns the value of the signature header for the specified timestamp
+// header and body. The timestamp is signed so that receivers can reject
+// replayed requests.
+func Sign(key []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (n *notifier) Notify(ctx context.Context, ev notifiers.Event) error {
+	body, err := json.Marshal(MakePayload(ev))
+	if err != nil {
+		return fnerrors.InternalError("failed to serialize payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, os.ExpandEnv(n.conf.Url), bytes.NewReader(body))
+	if err != nil {
+		return fnerrors.InvocationError("webhook", "failed to create HTTP request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for _, h := range n.conf.Header {
+		req.Header.Set(h.Name, os.ExpandEnv(h.Value))
+	}
+
+	if n.signingKey != nil {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(TimestampHeader, timestamp)
+		req.Header.Set(SignatureHeader, Sign(n.signingKey, timestamp, body))
+	}
+
+	resp, err := notifiers.HTTPClient.Do(req)
+	if err != nil {
+		return fnerrors.InvocationError("webhook", "failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fnerrors.InvocationError("webhook", "webhook returned %v", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"namespacelabs.dev/foundation/framework/deploy"
+	"namespacelabs.dev/foundation/internal/notifiers"
+	"namespacelabs.dev/foundation/schema"
+)
+
+func TestNotify(t *testing.T) {
+	key := []byte("secret")
+
+	var got Payload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		timestamp := r.Header.Get(TimestampHeader)
+		if timestamp == "" {
+			t.Error("expected a timestamp")
+		}
+
+		if sig := r.Header.Get(SignatureHeader); sig != Sign(key, timestamp, body) {
+			t.Errorf("bad signature %q", sig)
+		}
+
+		if v := r.Header.Get("X-Extra"); v != "value" {
+			t.Errorf("expected header to be set, got %q", v)
+		}
+
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+
+	n := &notifier{
+		conf: &deploy.WebhookNotifier{
+			Url:    srv.URL,
+			Header: []*deploy.WebhookNotifier_Header{{Name: "X-Extra", Value: "value"}},
+		},
+		signingKey: key,
+	}
+
+	start := time.Unix(1000, 0).UTC()
+	if err := n.Notify(context.Background(), notifiers.Event{
+		Stage: notifiers.StageFinished,
+		Plan: &schema.DeployPlan{
+			Environment: &schema.Environment{Name: "prod"},
+			Stack:       &schema.Stack{Entry: []*schema.Stack_Entry{{Server: &schema.Server{PackageName: "example.com/server"}}}},
+		},
+		Reason:    "release",
+		Started:   start,
+		Completed: start.Add(time.Minute),
+		Err:       errors.New("failed"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Stage != "finished" || got.Environment != "prod" || got.Reason != "release" || got.Error != "failed" {
+		t.Errorf("unexpected payload: %+v", got)
+	}
+
+	if len(got.Servers) != 1 || got.Servers[0] != "example.com/server" {
+		t.Errorf("unexpected servers: %v", got.Servers)
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"namespacelabs.dev/foundation/schema"
 	"namespacelabs.dev/foundation/schema/storage"
 	"namespacelabs.dev/foundation/std/cfg"
+	"namespacelabs.dev/foundation/std/pkggraph"
 	"namespacelabs.dev/foundation/std/tasks"
 )
 
@@ -59,6 +60,11 @@ func MakeBuildPlan(ctx context.Context, rc runtime.Planner, server planning.Plan
 				opts = &integrations.HotReloadOpts{}
 			}
 
+			definition, err := serverBuildDefinition(ctx, server, spec)
+			if err != nil {
+				return build.Plan{}, err
+			}
+
 			return build.Plan{
 				SourceLabel:   fmt.Sprintf("Server %s", server.PackageName()),
 				SourcePackage: server.PackageName(),
@@ -66,10 +72,35 @@ func MakeBuildPlan(ctx context.Context, rc runtime.Planner, server planning.Plan
 				Spec:          spec,
 				Workspace:     hotreload.NewDevModule(ws, observeChanges, digestMode, *opts, &codegenTrigger{srv: server.Server}),
 				Platforms:     platforms,
+				Definition:    definition,
+				Module:        server.Module(),
 			}, nil
 		})
 }
 
+// serverBuildDefinition describes what's built for the server, as binaries do
+// with their build plans. Returns nil if the server's image is produced by its
+// framework's integration without one.
+func serverBuildDefinition(ctx context.Context, server planning.PlannedServer, spec build.Spec) (*schema.LayeredImageBuildPlan, error) {
+	if imgid, ok := build.IsPrebuilt(spec); ok {
+		return &schema.LayeredImageBuildPlan{
+			LayerBuildPlan: []*schema.ImageBuildPlan{{ImageId: imgid.RepoAndDigest()}},
+		}, nil
+	}
+
+	binRef := server.MergedFragment.GetMainContainer().GetBinaryRef()
+	if binRef == nil {
+		return nil, nil
+	}
+
+	_, bin, err := pkggraph.LoadBinary(ctx, server.SealedContext(), binRef)
+	if err != nil {
+		return nil, err
+	}
+
+	return bin.BuildPlan, nil
+}
+
 type prepareServerConfig struct {
 	planner         runtime.Planner
 	serverPackage   schema.PackageName
@@ -15,6 +15,7 @@ import (
 	"namespacelabs.dev/foundation/internal/artifacts/registry"
 	"namespacelabs.dev/foundation/internal/build"
 	"namespacelabs.dev/foundation/internal/build/assets"
+	"namespacelabs.dev/foundation/internal/build/attest"
 	"namespacelabs.dev/foundation/internal/build/binary"
 	"namespacelabs.dev/foundation/internal/build/multiplatform"
 	"namespacelabs.dev/foundation/internal/compute"
@@ -39,6 +40,9 @@ var (
 	AlsoDeployIngress        = true
 	PushPrebuiltsToRegistry  = true
 	MirrorPrebuiltToRegistry = false
+	// If set, deployments to production environments are refused unless
+	// every image is signed by a trusted key (see keys.TrustedSigningKeys).
+	VerifyImageSignatures = false
 )
 
 type ResolvedServerImages struct {
@@ -105,6 +109,7 @@ func PrepareDeployStack(ctx context.Context, planner planning.Planner, stack *pl
 	})
 
 	g := &makeDeployGraph{
+		planner:          planner,
 		stack:            stack,
 		prepare:          prepare,
 		ingressFragments: fragmentsOnly,
@@ -126,6 +131,7 @@ func makeBuildAssets(ingressFragments compute.Computable[*ComputeIngressResult])
 }
 
 type makeDeployGraph struct {
+	planner          planning.Planner
 	stack            *planning.Stack
 	prepare          compute.Computable[prepareAndBuildResult]
 	ingressFragments compute.Computable[[]*schema.IngressFragment]
@@ -141,6 +147,7 @@ type Plan struct {
 	Computed           *schema.ComputedConfigurations
 	Hints              []string // Optional messages to pass to the user.
 	NamespaceReference string
+	ServerImages       map[schema.PackageName]ResolvedServerImages
 }
 
 func (m *makeDeployGraph) Action() *tasks.ActionEvent {
@@ -148,7 +155,7 @@ func (m *makeDeployGraph) Action() *tasks.ActionEvent {
 }
 
 func (m *makeDeployGraph) Inputs() *compute.In {
-	in := compute.Inputs().Computable("prepare", m.prepare).Indigestible("stack", m.stack)
+	in := compute.Inputs().Computable("prepare", m.prepare).Indigestible("planner", m.planner).Indigestible("stack", m.stack)
 	// TODO predeploy orchestration server already from here?
 	if m.ingressFragments != nil {
 		in = in.Computable("ingress", m.ingressFragments).Computable("ingressPlan", m.ingressPlan)
@@ -172,6 +179,7 @@ func (m *makeDeployGraph) Compute(ctx context.Context, deps compute.Resolved) (*
 		ComputedStack:      m.stack,
 		Hints:              pbr.Hints,
 		NamespaceReference: pbr.NamespaceReference,
+		ServerImages:       pbr.ServerImages,
 	}
 
 	if ingress, ok := compute.GetDep(deps, m.ingressPlan, "ingressPlan"); ok {
@@ -194,6 +202,12 @@ func (m *makeDeployGraph) Compute(ctx context.Context, deps compute.Resolved) (*
 
 	plan.Computed = pbr.HandlerResult.MergedComputedConfigurations()
 
+	if VerifyImageSignatures && m.planner.Context.Environment().GetPurpose() == schema.Environment_PRODUCTION {
+		if err := verifyImageSignatures(ctx, m.planner, g.Definitions()); err != nil {
+			return nil, err
+		}
+	}
+
 	return plan, nil
 }
 
@@ -215,6 +229,7 @@ func prepareHandlerInvocations(ctx context.Context, planner planning.Planner, st
 type prepareAndBuildResult struct {
 	HandlerResult      *handlerResult
 	ResourcePlan       *resourcePlan
+	ServerImages       map[schema.PackageName]ResolvedServerImages
 	Ops                []*schema.SerializedInvocation
 	Hints              []string
 	NamespaceReference string
@@ -278,6 +293,7 @@ func prepareBuildAndDeployment(ctx context.Context, planner planning.Planner, st
 				srv := compute.MustGetDepValue(deps, images[k], pkg.String())
 				m[pkg] = srv
 			}
+
 			return m, nil
 		})
 
@@ -306,6 +322,7 @@ func prepareBuildAndDeployment(ctx context.Context, planner planning.Planner, st
 	return compute.Map(tasks.Action("plan.combine"), compute.Inputs().
 		Indigestible("planner", planner).
 		Computable("resourcePlan", resourcePlan).
+		Computable("images", imageIDs).
 		Computable("deploymentSpec", deploymentSpec).
 		Computable("stackAndDefs", stackDef).
 		Computable("prepared", preparedComp), compute.Output{},
@@ -338,6 +355,7 @@ func prepareBuildAndDeployment(ctx context.Context, planner planning.Planner, st
 			return prepareAndBuildResult{
 				HandlerResult:      compute.MustGetDepValue(deps, stackDef, "stackAndDefs"),
 				ResourcePlan:       resourcePlan,
+				ServerImages:       compute.MustGetDepValue(deps, imageIDs, "images"),
 				Ops:                ops,
 				Hints:              deploymentPlan.Hints,
 				NamespaceReference: deploymentPlan.NamespaceReference,
@@ -709,7 +727,7 @@ func ensureImage(ctx context.Context, env pkggraph.SealedContext, registry regis
 	}
 
 	return imagePoster{
-		ImageID:     oci.PublishResolvable(name, bin, p),
+		ImageID:     attest.Publish(name, bin, p),
 		SourceImage: bin,
 	}, nil
 }
@@ -0,0 +1,127 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"namespacelabs.dev/foundation/framework/kubernetes/kubedef"
+	"namespacelabs.dev/foundation/framework/rpcerrors/multierr"
+	"namespacelabs.dev/foundation/internal/artifacts/oci"
+	"namespacelabs.dev/foundation/internal/artifacts/registry"
+	"namespacelabs.dev/foundation/internal/console"
+	"namespacelabs.dev/foundation/internal/executor"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/internal/keys"
+	"namespacelabs.dev/foundation/internal/planning"
+	"namespacelabs.dev/foundation/schema"
+	"namespacelabs.dev/foundation/std/cfg"
+	"namespacelabs.dev/foundation/std/tasks"
+)
+
+// verifyImageSignatures verifies the signatures of the images that the plan's
+// invocations deploy. The same set of images is verified whether the plan was
+// just computed, or is deployed later (see VerifyPlanImageSignatures).
+func verifyImageSignatures(ctx context.Context, planner planning.Planner, invocations []*schema.SerializedInvocation) error {
+	imageIDs, err := imagesOf(invocations)
+	if err != nil {
+		return err
+	}
+
+	var access oci.RegistryAccess
+	if planner.Registry != nil {
+		access = planner.Registry.Access()
+	}
+
+	return verifyImageIDs(ctx, access, imageIDs)
+}
+
+// VerifyPlanImageSignatures verifies the signatures of the images that a
+// previously computed plan deploys (e.g. with `ns deploy-plan`, or when
+// rolling back), if required for the environment (see VerifyImageSignatures).
+func VerifyPlanImageSignatures(ctx context.Context, env cfg.Context, plan *schema.DeployPlan) error {
+	if !VerifyImageSignatures || env.Environment().GetPurpose() != schema.Environment_PRODUCTION {
+		return nil
+	}
+
+	imageIDs, err := imagesOf(plan.GetProgram().GetInvocation())
+	if err != nil {
+		return err
+	}
+
+	r, err := registry.GetRegistry(ctx, env)
+	if err != nil {
+		return err
+	}
+
+	return verifyImageIDs(ctx, r.Access(), imageIDs)
+}
+
+func imagesOf(invocations []*schema.SerializedInvocation) ([]oci.ImageID, error) {
+	var refs []string
+	for _, inv := range invocations {
+		images, err := kubedef.ImagesOf(inv)
+		if err != nil {
+			return nil, err
+		}
+
+		refs = append(refs, images...)
+	}
+
+	slices.Sort(refs)
+	refs = slices.Compact(refs)
+
+	var imageIDs []oci.ImageID
+	for _, ref := range refs {
+		imageID, err := oci.ParseImageID(ref)
+		if err != nil {
+			return nil, err
+		}
+
+		imageIDs = append(imageIDs, imageID)
+	}
+
+	return imageIDs, nil
+}
+
+func verifyImageIDs(ctx context.Context, access oci.RegistryAccess, imageIDs []oci.ImageID) error {
+	return tasks.Action("deploy.verify-image-signatures").Run(ctx, func(ctx context.Context) error {
+		trusted, err := keys.TrustedSigningKeys(ctx)
+		if err != nil {
+			return err
+		}
+
+		if len(trusted) == 0 {
+			return fnerrors.UsageError("Add the public keys of trusted signers to the keys directory, or run `ns keys signing generate`.",
+				"Image signature verification is enabled, but there are no trusted signing keys.")
+		}
+
+		eg := executor.New(ctx, "deploy.verify-image-signatures")
+		errs := make([]error, len(imageIDs))
+		for k, imageID := range imageIDs {
+			k := k             // Close k.
+			imageID := imageID // Close imageID.
+
+			eg.Go(func(ctx context.Context) error {
+				signer, err := oci.VerifyImageSignature(ctx, imageID, access, trusted)
+				if err != nil {
+					errs[k] = err
+					return nil
+				}
+
+				fmt.Fprintf(console.Debug(ctx), "%s: signed by %q\n", imageID.RepoAndDigest(), signer)
+				return nil
+			})
+		}
+
+		if err := eg.Wait(); err != nil {
+			return err
+		}
+
+		return multierr.New(errs...)
+	})
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/kr/text"
 	"namespacelabs.dev/foundation/internal/console"
 	"namespacelabs.dev/foundation/internal/console/renderwait"
+	"namespacelabs.dev/foundation/internal/eventstream"
 	"namespacelabs.dev/foundation/internal/runtime"
 	"namespacelabs.dev/foundation/schema/orchestration"
 	runtimepb "namespacelabs.dev/foundation/schema/runtime"
@@ -188,6 +189,8 @@ func observeContainers(ctx context.Context, env cfg.Context, cluster runtime.Clu
 					parent <- ev
 				}
 
+				eventstream.DeployEvent(ctx, ev)
+
 				if ev.Stage >= orchestration.Event_COMMITTED {
 					state, ok := committed[ev.ResourceId]
 					if !ok {
@@ -0,0 +1,147 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package policy
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	policypb "namespacelabs.dev/foundation/framework/policy"
+	"namespacelabs.dev/foundation/internal/console"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/schema"
+	"namespacelabs.dev/foundation/std/cfg"
+	"namespacelabs.dev/foundation/std/pkggraph"
+)
+
+var policyConfigType = cfg.DefineConfigType[*policypb.Policy]()
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Target is a server which is subject to policies.
+type Target struct {
+	Location pkggraph.Location
+	Server   *schema.Server
+	// The server's definition. When evaluating a stack, this is merged with
+	// the fragments contributed by the server's dependencies.
+	Fragment *schema.ServerFragment
+	// The binaries of the server's containers.
+	Binaries []*schema.Binary
+	// The binary of the server's main container, if it has one.
+	MainBinary *schema.Binary
+}
+
+type Finding struct {
+	Rule     string
+	Severity Severity
+	Location pkggraph.Location
+	Message  string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s: %s [%s]", f.Location.ErrorLocation(), f.Severity, f.Message, f.Rule)
+}
+
+// Evaluate checks each target against the rules configured for the environment.
+func Evaluate(env cfg.Context, targets []Target) ([]Finding, error) {
+	conf, ok := policyConfigType.CheckGet(env.Configuration())
+	if !ok {
+		return nil, nil
+	}
+
+	var findings []Finding
+	for _, rule := range conf.Rule {
+		check, ok := builtinRules[rule.Name]
+		if !ok {
+			return nil, fnerrors.BadInputError("%s: no such policy rule", rule.Name)
+		}
+
+		if len(rule.Purpose) > 0 && !slices.Contains(rule.Purpose, env.Environment().GetPurpose()) {
+			continue
+		}
+
+		severity := SeverityError
+		if rule.Severity == policypb.Policy_WARNING {
+			severity = SeverityWarning
+		}
+
+		for _, t := range targets {
+			if slices.Contains(rule.ExemptServer, t.Location.PackageName.String()) {
+				continue
+			}
+
+			for _, msg := range check(rule, t) {
+				findings = append(findings, Finding{
+					Rule:     rule.Name,
+					Severity: severity,
+					Location: t.Location,
+					Message:  msg,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Location.PackageName < findings[j].Location.PackageName
+	})
+
+	return findings, nil
+}
+
+// Enforce evaluates the configured policies, reports every finding, and fails
+// if any of them has error severity.
+func Enforce(ctx context.Context, env cfg.Context, targets []Target) error {
+	findings, err := Evaluate(env, targets)
+	if err != nil {
+		return err
+	}
+
+	var violations int
+	for _, f := range findings {
+		fmt.Fprintln(console.Warnings(ctx), f)
+
+		if f.Severity == SeverityError {
+			violations++
+		}
+	}
+
+	if violations > 0 {
+		return fnerrors.Newf("%d policy violation(s) in environment %q", violations, env.Environment().Name)
+	}
+
+	return nil
+}
+
+// LoadTarget returns a target for the server, loading the binaries of each
+// of its containers.
+func LoadTarget(ctx context.Context, pl pkggraph.PackageLoader, loc pkggraph.Location, server *schema.Server, fragment *schema.ServerFragment) (Target, error) {
+	t := Target{Location: loc, Server: server, Fragment: fragment}
+
+	for _, c := range containers(fragment) {
+		ref := c.container.GetBinaryRef()
+		if ref == nil {
+			continue
+		}
+
+		_, bin, err := pkggraph.LoadBinary(ctx, pl, ref)
+		if err != nil {
+			return Target{}, err
+		}
+
+		t.Binaries = append(t.Binaries, bin)
+		if c.main {
+			t.MainBinary = bin
+		}
+	}
+
+	return t, nil
+}
@@ -0,0 +1,149 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	policypb "namespacelabs.dev/foundation/framework/policy"
+	"namespacelabs.dev/foundation/internal/runtime"
+	"namespacelabs.dev/foundation/schema"
+)
+
+type ruleFunc func(*policypb.Policy_Rule, Target) []string
+
+var builtinRules = map[string]ruleFunc{
+	"no_privileged":           noPrivileged,
+	"no_host_network":         noHostNetwork,
+	"require_resource_limits": requireResourceLimits,
+	"require_readiness_probe": requireReadinessProbe,
+	"allowed_registries":      allowedRegistries,
+	"required_labels":         requiredLabels,
+}
+
+func noPrivileged(_ *policypb.Policy_Rule, t Target) []string {
+	var msgs []string
+	for _, c := range containers(t.Fragment) {
+		if c.container.GetSecurity().GetPrivileged() {
+			msgs = append(msgs, fmt.Sprintf("%s must not be privileged", c.label))
+		}
+	}
+	return msgs
+}
+
+func noHostNetwork(_ *policypb.Policy_Rule, t Target) []string {
+	var msgs []string
+	for _, c := range containers(t.Fragment) {
+		if c.container.GetSecurity().GetHostNetwork() {
+			msgs = append(msgs, fmt.Sprintf("%s must not use the host network", c.label))
+		}
+	}
+	return msgs
+}
+
+func requireResourceLimits(_ *policypb.Policy_Rule, t Target) []string {
+	var msgs []string
+	for _, c := range containers(t.Fragment) {
+		if c.init {
+			continue
+		}
+
+		var missing []string
+		if c.container.GetLimits().GetCpu() == "" {
+			missing = append(missing, "cpu")
+		}
+		if c.container.GetLimits().GetMemory() == "" {
+			missing = append(missing, "memory")
+		}
+
+		if len(missing) > 0 {
+			msgs = append(msgs, fmt.Sprintf("%s must declare %s limits", c.label, strings.Join(missing, " and ")))
+		}
+	}
+	return msgs
+}
+
+func requireReadinessProbe(_ *policypb.Policy_Rule, t Target) []string {
+	for _, probe := range t.Fragment.GetProbe() {
+		if probe.Kind == runtime.FnServiceReadyz {
+			return nil
+		}
+	}
+
+	return []string{"server must declare a readiness probe"}
+}
+
+func allowedRegistries(rule *policypb.Policy_Rule, t Target) []string {
+	var msgs []string
+	for _, bin := range t.Binaries {
+		for _, layer := range bin.GetBuildPlan().GetLayerBuildPlan() {
+			if layer.ImageId == "" {
+				continue
+			}
+
+			if !isAllowedImage(layer.ImageId, rule.AllowedRegistry) {
+				msgs = append(msgs, fmt.Sprintf("binary %q uses image %q, which is not hosted in an allowed registry", bin.Name, layer.ImageId))
+			}
+		}
+	}
+	return msgs
+}
+
+func isAllowedImage(imageID string, allowed []string) bool {
+	repo := imageID
+	if ref, err := name.ParseReference(imageID); err == nil {
+		repo = ref.Context().Name()
+	}
+
+	for _, prefix := range allowed {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if repo == prefix || strings.HasPrefix(repo, prefix+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func requiredLabels(rule *policypb.Policy_Rule, t Target) []string {
+	declared := map[string]bool{}
+	for _, label := range t.MainBinary.GetLabels() {
+		declared[label.Name] = true
+	}
+
+	var msgs []string
+	for _, required := range rule.RequiredLabel {
+		if !declared[required] {
+			msgs = append(msgs, fmt.Sprintf("main container binary must declare label %q", required))
+		}
+	}
+	return msgs
+}
+
+type containerWithLabel struct {
+	label     string
+	container *schema.Container
+	main      bool
+	init      bool
+}
+
+func containers(frag *schema.ServerFragment) []containerWithLabel {
+	var cs []containerWithLabel
+	if frag.GetMainContainer() != nil {
+		cs = append(cs, containerWithLabel{label: "main container", container: frag.MainContainer, main: true})
+	}
+
+	for _, sidecar := range frag.GetSidecar() {
+		cs = append(cs, containerWithLabel{label: fmt.Sprintf("sidecar %q", sidecar.Name), container: sidecar})
+	}
+
+	for _, init := range frag.GetInitContainer() {
+		cs = append(cs, containerWithLabel{label: fmt.Sprintf("init container %q", init.Name), container: init, init: true})
+	}
+
+	return cs
+}
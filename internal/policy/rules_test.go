@@ -0,0 +1,67 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	policypb "namespacelabs.dev/foundation/framework/policy"
+	"namespacelabs.dev/foundation/internal/runtime"
+	"namespacelabs.dev/foundation/schema"
+)
+
+func TestRules(t *testing.T) {
+	server := &schema.Binary{
+		Name:   "server",
+		Labels: []*schema.Label{{Name: "owner", Value: "payments"}},
+		BuildPlan: &schema.LayeredImageBuildPlan{
+			LayerBuildPlan: []*schema.ImageBuildPlan{{ImageId: "us-docker.pkg.dev/project/images/server@sha256:abcd"}},
+		},
+	}
+
+	target := Target{
+		Fragment: &schema.ServerFragment{
+			MainContainer: &schema.Container{
+				Security: &schema.Container_Security{Privileged: true},
+				Limits:   &schema.Container_ResourceLimits{Memory: "1Gi"},
+			},
+			Sidecar: []*schema.Container{{
+				Name:     "proxy",
+				Security: &schema.Container_Security{HostNetwork: true},
+				Limits:   &schema.Container_ResourceLimits{Memory: "128Mi", Cpu: "100m"},
+			}},
+			Probe: []*schema.Probe{{Kind: runtime.FnServiceLivez}},
+		},
+		Binaries: []*schema.Binary{{
+			Name: "nginx",
+			BuildPlan: &schema.LayeredImageBuildPlan{
+				LayerBuildPlan: []*schema.ImageBuildPlan{{ImageId: "nginx:1.25"}},
+			},
+		}, server},
+		MainBinary: server,
+	}
+
+	rule := &policypb.Policy_Rule{
+		AllowedRegistry: []string{"us-docker.pkg.dev/project"},
+		RequiredLabel:   []string{"owner", "team"},
+	}
+
+	for _, test := range []struct {
+		Rule     string
+		Expected []string
+	}{
+		{"no_privileged", []string{"main container must not be privileged"}},
+		{"no_host_network", []string{`sidecar "proxy" must not use the host network`}},
+		{"require_resource_limits", []string{"main container must declare cpu limits"}},
+		{"require_readiness_probe", []string{"server must declare a readiness probe"}},
+		{"allowed_registries", []string{`binary "nginx" uses image "nginx:1.25", which is not hosted in an allowed registry`}},
+		{"required_labels", []string{`main container binary must declare label "team"`}},
+	} {
+		if d := cmp.Diff(test.Expected, builtinRules[test.Rule](rule, target)); d != "" {
+			t.Errorf("%s: unexpected findings (-want +got):\n%s", test.Rule, d)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package policy
+
+import (
+	"context"
+
+	"namespacelabs.dev/foundation/internal/parsing"
+	"namespacelabs.dev/foundation/internal/planning"
+	"namespacelabs.dev/foundation/schema"
+	"namespacelabs.dev/foundation/std/cfg"
+)
+
+// TargetsFromStack returns a target for each server in the stack, including
+// the configuration contributed by each server's dependencies.
+func TargetsFromStack(ctx context.Context, stack *planning.Stack) ([]Target, error) {
+	var targets []Target
+	for _, srv := range stack.Servers {
+		t, err := LoadTarget(ctx, srv.SealedContext(), srv.Location, srv.Proto(), srv.MergedFragment)
+		if err != nil {
+			return nil, err
+		}
+
+		targets = append(targets, t)
+	}
+
+	return targets, nil
+}
+
+// EnforcePlan enforces the configured policies on the servers of a deployment
+// plan, which may have been computed elsewhere (or earlier, when rolling
+// back). Plans don't retain binary definitions, so those are loaded from the
+// workspace.
+func EnforcePlan(ctx context.Context, env cfg.Context, plan *schema.DeployPlan) error {
+	if _, ok := policyConfigType.CheckGet(env.Configuration()); !ok {
+		return nil
+	}
+
+	pl := parsing.NewPackageLoader(env)
+
+	var targets []Target
+	for _, entry := range plan.GetStack().GetEntry() {
+		pkg, err := pl.LoadByName(ctx, schema.PackageName(entry.GetServer().GetPackageName()))
+		if err != nil {
+			return err
+		}
+
+		t, err := LoadTarget(ctx, pl, pkg.Location, entry.Server, mergeFragments(entry.ServerFragment))
+		if err != nil {
+			return err
+		}
+
+		targets = append(targets, t)
+	}
+
+	return Enforce(ctx, env, targets)
+}
+
+// mergeFragments merges the parts of a server's fragments which policies
+// inspect, the way planning does.
+func mergeFragments(fragments []*schema.ServerFragment) *schema.ServerFragment {
+	merged := &schema.ServerFragment{}
+	for _, frag := range fragments {
+		if merged.MainContainer == nil || frag.GetMainContainer().GetBinaryRef() != nil {
+			merged.MainContainer = frag.GetMainContainer()
+		}
+
+		merged.Sidecar = append(merged.Sidecar, frag.Sidecar...)
+		merged.InitContainer = append(merged.InitContainer, frag.InitContainer...)
+		merged.Probe = append(merged.Probe, frag.Probe...)
+	}
+
+	return merged
+}
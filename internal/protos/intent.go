@@ -14,6 +14,7 @@ import (
 	"golang.org/x/exp/maps"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
 	"namespacelabs.dev/foundation/internal/artifacts"
 	"namespacelabs.dev/foundation/internal/artifacts/download"
 	"namespacelabs.dev/foundation/internal/compute"
@@ -186,6 +187,18 @@ func allocateSingleValue(ctx context.Context, pctx ParseContext, field protorefl
 }
 
 func AllocateWellKnownMessage(ctx context.Context, pctx ParseContext, messageType protoreflect.MessageDescriptor, value any) (protoreflect.ProtoMessage, error) {
+	if messageType.FullName() == "google.protobuf.Any" {
+		// Nested messages of arbitrary type are specified with an explicit @type.
+		if m, ok := value.(map[string]any); ok && m["@type"] != nil {
+			msg, err := AllocateFrom(ctx, pctx, maps.Clone(m))
+			if err != nil {
+				return nil, err
+			}
+
+			return anypb.New(msg)
+		}
+	}
+
 	if pctx.SupportWellKnownMessages {
 		// Handle well-known types.
 		switch messageType.FullName() {
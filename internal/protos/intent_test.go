@@ -14,6 +14,8 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/anypb"
+	"namespacelabs.dev/foundation/framework/deploy"
 	"namespacelabs.dev/foundation/schema"
 )
 
@@ -93,6 +95,17 @@ func TestAllocateMessage(t *testing.T) {
 				},
 			},
 		},
+		{
+			JSON: `{
+				"notifier": [{
+					"@type": "type.googleapis.com/foundation.framework.deploy.SlackNotifier",
+					"channel": "deploys"
+				}]
+			}`,
+			Expected: &deploy.Deployment{
+				Notifier: []*anypb.Any{mustAny(&deploy.SlackNotifier{Channel: "deploys"})},
+			},
+		},
 	} {
 		msg, err := AllocateWellKnownMessage(context.Background(), ParseContext{FS: testData, SupportWellKnownMessages: true},
 			test.Expected.ProtoReflect().Descriptor(), unmarshal(test.JSON))
@@ -117,3 +130,12 @@ func unmarshal(str string) any {
 
 	return m
 }
+
+func mustAny(msg proto.Message) *anypb.Any {
+	any, err := anypb.New(msg)
+	if err != nil {
+		panic(err)
+	}
+
+	return any
+}
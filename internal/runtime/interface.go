@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"regexp"
 	"strings"
 	"time"
 
@@ -174,6 +175,11 @@ type ClusterNamespace interface {
 	// generated for production environments for now.
 	DeployedConfigImageID(context.Context, Deployable) (oci.ImageID, error)
 
+	// DeployedResourceConfig retrieves the serialized resource configuration
+	// (i.e. resources.json) that was injected into the specified server when it
+	// was last deployed.
+	DeployedResourceConfig(context.Context, Deployable) ([]byte, error)
+
 	// Returns a list of containers that the server has deployed.
 	ResolveContainers(context.Context, Deployable) ([]*runtimepb.ContainerReference, error)
 
@@ -198,6 +204,11 @@ type ClusterNamespace interface {
 	// Observe runs until the context is cancelled.
 	Observe(context.Context, Deployable, ObserveOpts, func(ObserveEvent) (bool, error)) error
 
+	// Fetches the logs of the servers' containers, as selected by
+	// ServerLogsOpts.Observe, merged into a single stream ordered by
+	// timestamp. When following, containers are picked up as they come and go.
+	FetchServerLogs(ctx context.Context, servers []Deployable, opts ServerLogsOpts, callback func(ServerLogLine)) error
+
 	// WaitUntilReady blocks until the target deployable reports ready. If the
 	// deployable represents a collection of replicas, readiness waits for all
 	// replicas to become ready.
@@ -209,10 +220,81 @@ type ClusterNamespace interface {
 	// Deletes a previously deployed DeployableSpec.
 	DeleteDeployable(ctx context.Context, deployable Deployable) error
 
+	// Injects a fault into the instances of a previously deployed server, to
+	// exercise its resilience in tests. Faults which persist (e.g. paused
+	// servers) are reverted by the returned function, which is nil otherwise.
+	InjectFault(ctx context.Context, server Deployable, fault Fault) (func(context.Context) error, error)
+
 	// Deletes the scoped environment, and all of its associated resources (e.g.
 	// after a test invocation). If wait is true, waits until the target
 	// resources have been removed. Returns true if resources were deleted.
 	DeleteRecursively(ctx context.Context, wait bool) (bool, error)
+
+	// Persists a record of a deployment plan which was successfully applied to
+	// this namespace. Returns the assigned revision number.
+	RecordDeploymentRevision(ctx context.Context, rev DeploymentRevision, plan *schema.DeployPlan) (int64, error)
+
+	// Returns the deployment revisions recorded in this namespace, most recent first.
+	ListDeploymentRevisions(ctx context.Context) ([]DeploymentRevision, error)
+
+	// Loads the plan that was recorded with the specified revision.
+	LoadDeploymentRevision(ctx context.Context, revision int64) (*schema.DeployPlan, error)
+
+	// Acquires the deployment lock of this namespace, failing with
+	// ErrDeploymentsLocked if it's held by someone else. Manual locks require
+	// the namespace to exist, and only expire if a ttl is set. Otherwise, the
+	// namespace is created if needed, and the lock is kept alive until the
+	// returned function is called, and otherwise expires after ttl. If the
+	// lock can't be renewed, the returned context is canceled.
+	LockDeployments(ctx context.Context, lock DeployLock, ttl time.Duration) (context.Context, func(context.Context) error, error)
+
+	// Removes the deployment lock, regardless of who holds it. Returns the
+	// lock that was removed, if any.
+	UnlockDeployments(ctx context.Context) (*DeployLock, error)
+}
+
+type DeployLock struct {
+	Holder string `json:"holder"`
+	Reason string `json:"reason,omitempty"`
+	// Manual locks are created by users to freeze deployments, rather than
+	// being held for the duration of a deployment.
+	Manual   bool      `json:"manual,omitempty"`
+	Acquired time.Time `json:"acquired"`
+	// Zero if the lock doesn't expire.
+	Expires time.Time `json:"expires,omitempty"`
+}
+
+type ErrDeploymentsLocked struct {
+	Lock DeployLock
+}
+
+func (e ErrDeploymentsLocked) Error() string {
+	what := "a deployment"
+	if e.Lock.Manual {
+		what = "deployments"
+	}
+
+	msg := fmt.Sprintf("%s locked %s since %s", e.Lock.Holder, what, e.Lock.Acquired.Local().Format(time.RFC3339))
+	if e.Lock.Reason != "" {
+		msg += fmt.Sprintf(" (%s)", e.Lock.Reason)
+	}
+	if !e.Lock.Expires.IsZero() {
+		msg += fmt.Sprintf(", expires at %s", e.Lock.Expires.Local().Format(time.RFC3339))
+	}
+
+	return msg
+}
+
+type DeploymentRevision struct {
+	Revision   int64               `json:"revision"`
+	Created    time.Time           `json:"created"`
+	Author     string              `json:"author,omitempty"`
+	Reason     string              `json:"reason,omitempty"`
+	PlanDigest string              `json:"plan_digest"`
+	VCS        *runtimepb.BuildVCS `json:"vcs,omitempty"`
+	Servers    []string            `json:"servers,omitempty"`
+	// If this revision is the result of a rollback, the revision that was restored.
+	RollbackOf int64 `json:"rollback_of,omitempty"`
 }
 
 type Deployable interface {
@@ -285,6 +367,22 @@ type DeployableSpec struct {
 	Probes []*schema.Probe
 }
 
+type FaultKind string
+
+const (
+	FaultRestart      FaultKind = "restart"       // Gracefully terminates each instance, which is then replaced.
+	FaultKill         FaultKind = "kill"          // Terminates each instance without a grace period.
+	FaultPause        FaultKind = "pause"         // Cuts each instance off from all network traffic, in and out.
+	FaultBlockTraffic FaultKind = "block-traffic" // Drops new connections from Source to the server.
+	FaultLameduck     FaultKind = "lameduck"      // Begins the lameduck phase of each instance, without terminating it.
+)
+
+type Fault struct {
+	Kind      FaultKind
+	Source    Deployable // Required by FaultBlockTraffic.
+	AdminPort int32      // Required by FaultLameduck: the port of the server's internal HTTP endpoint.
+}
+
 // A resource whose instance is known.
 type ComputedResource struct {
 	ResourceInstanceID     string
@@ -351,9 +449,22 @@ type FetchLogsOpts struct {
 	TailLines        int // Only used if it's a positive value.
 	Follow           bool
 	FetchLastFailure bool
+
+	// If set, only logs emitted within this range are fetched. When following,
+	// streaming stops once Until is reached.
+	Since, Until time.Time
+	// If set, only lines matching at least one of Include, and none of
+	// Exclude, are passed along. Events other than log lines are not filtered.
+	Include, Exclude []*regexp.Regexp
 }
 
 type ObserveOpts struct {
+	// Also observe the server's sidecars.
+	Sidecars bool
+	// Also observe the server's init containers.
+	InitContainers bool
+	// If set, only containers with one of these names are observed.
+	ContainerNames []string
 }
 
 type ObserveEvent struct {
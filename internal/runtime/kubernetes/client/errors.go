@@ -0,0 +1,49 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+
+	"namespacelabs.dev/foundation/internal/fnerrors"
+)
+
+// MaybeUnreachable attaches fnerrors.CodeClusterUnreachable to errors which
+// were caused by failing to reach the cluster's API server at all (as opposed
+// to the API server returning an error). Canceled requests are left alone.
+func MaybeUnreachable(err error) error {
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	if errors.As(err, &opErr) || errors.As(err, &dnsErr) {
+		return fnerrors.WithCode(fnerrors.CodeClusterUnreachable, err)
+	}
+
+	return err
+}
+
+// unreachableTransport applies MaybeUnreachable to the failures of every
+// request made by the client, so that applying, waiting and port forwarding
+// all report when the cluster can't be reached.
+type unreachableTransport struct {
+	rt http.RoundTripper
+}
+
+func (t unreachableTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.rt.RoundTrip(req)
+	if err != nil {
+		return nil, MaybeUnreachable(err)
+	}
+
+	return resp, nil
+}
+
+func (t unreachableTransport) WrappedRoundTripper() http.RoundTripper { return t.rt }
@@ -0,0 +1,97 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"namespacelabs.dev/foundation/internal/fnerrors"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestUnreachableTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		http.Error(rw, "forbidden", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	// Nothing listens on a closed listener's address.
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedAddr := lis.Addr().String()
+	lis.Close()
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, test := range []struct {
+		name        string
+		url         string
+		ctx         context.Context
+		wantErr     bool
+		unreachable bool
+	}{
+		{name: "api server error", url: srv.URL, ctx: context.Background()},
+		{name: "connection refused", url: "http://" + closedAddr, ctx: context.Background(), wantErr: true, unreachable: true},
+		{name: "canceled", url: srv.URL, ctx: canceled, wantErr: true},
+	} {
+		cli := &http.Client{Transport: unreachableTransport{http.DefaultTransport}}
+
+		req, err := http.NewRequestWithContext(test.ctx, http.MethodGet, test.url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := cli.Do(req)
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+
+		if code, _ := fnerrors.CodeOf(err); (code == fnerrors.CodeClusterUnreachable) != test.unreachable {
+			t.Errorf("%s: got code %q for %v", test.name, code, err)
+		}
+	}
+}
+
+func TestMaybeUnreachable(t *testing.T) {
+	canceledDial := &net.OpError{Op: "dial", Net: "tcp", Err: context.Canceled}
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+
+	if code, ok := fnerrors.CodeOf(MaybeUnreachable(refused)); !ok || code != fnerrors.CodeClusterUnreachable {
+		t.Errorf("expected a refused dial to be reported as unreachable, got %q", code)
+	}
+
+	if _, ok := fnerrors.CodeOf(MaybeUnreachable(canceledDial)); ok {
+		t.Error("expected a canceled dial not to be reported as unreachable")
+	}
+
+	if _, ok := fnerrors.CodeOf(MaybeUnreachable(errors.New("forbidden"))); ok {
+		t.Error("expected api server errors not to be reported as unreachable")
+	}
+
+	rt := unreachableTransport{roundTripFunc(func(*http.Request) (*http.Response, error) { return nil, refused })}
+	if _, err := rt.RoundTrip(httptest.NewRequest(http.MethodGet, "http://example.com", nil)); !errors.Is(err, refused) {
+		t.Errorf("expected the original error to be preserved, got %v", err)
+	}
+
+	if rt.WrappedRoundTripper() == nil {
+		t.Error("expected the wrapped round tripper to be exposed")
+	}
+}
@@ -6,6 +6,7 @@ package client
 
 import (
 	"context"
+	"net/http"
 	"net/url"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -166,6 +167,10 @@ func NewClientFromResult(ctx context.Context, hostEnv *HostEnv, computed *config
 		return nil, err
 	}
 
+	restcfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return unreachableTransport{rt}
+	})
+
 	clientset, err := k8s.NewForConfig(restcfg)
 	if err != nil {
 		return nil, err
@@ -215,7 +220,8 @@ func copyAndSetDefaults(config rest.Config, gv schema.GroupVersion) *rest.Config
 func CheckGetHostEnv(cfg cfg.Configuration) (*HostEnv, error) {
 	hostEnv, ok := hostEnvConfigType.CheckGet(cfg)
 	if !ok {
-		return nil, fnerrors.UsageError("Try running one `ns prepare local` or `ns prepare eks`", "%s: no kubernetes configuration available", cfg.EnvKey())
+		return nil, fnerrors.WithCode(fnerrors.CodeClusterNotConfigured,
+			fnerrors.UsageError("Try running one `ns prepare local` or `ns prepare eks`", "%s: no kubernetes configuration available", cfg.EnvKey()))
 	}
 	return hostEnv, nil
 }
@@ -26,6 +26,7 @@ import (
 	"namespacelabs.dev/foundation/internal/fnerrors"
 	"namespacelabs.dev/foundation/internal/runtime"
 	"namespacelabs.dev/foundation/internal/runtime/kubernetes/client"
+	"namespacelabs.dev/foundation/internal/runtime/kubernetes/faults"
 	"namespacelabs.dev/foundation/internal/runtime/kubernetes/kubeobserver"
 	"namespacelabs.dev/foundation/internal/runtime/kubernetes/readiness"
 	orchclient "namespacelabs.dev/foundation/orchestration/client"
@@ -326,27 +327,17 @@ func (r *ClusterNamespace) Observe(ctx context.Context, srv runtime.Deployable,
 	}
 
 	_, err = kubeobserver.WatchPods(ctx, r.underlying.cli, r.target.namespace, kubedef.SelectById(srv), func(pod corev1.Pod) (any, bool, error) {
-		instance := kubeobj.MakePodRef(r.target.namespace, pod.Name, kubedef.ServerCtrName(srv), kubedef.DecideKind(srv))
-
 		t := untrackContainer
 		if pod.Status.Phase == corev1.PodRunning {
 			t = trackContainer
 		}
 
-		if done, err := t(pod, instance); err != nil {
-			return nil, false, err
-		} else if done {
-			return nil, true, nil
-		}
-
-		if ObserveInitContainerLogs {
-			for _, container := range pod.Spec.InitContainers {
-				instance := kubeobj.MakePodRef(r.target.namespace, pod.Name, container.Name, kubedef.DecideKind(srv))
-				if done, err := t(pod, instance); err != nil {
-					return nil, false, err
-				} else if done {
-					return nil, true, nil
-				}
+		for _, name := range observedContainers(srv, pod, opts) {
+			instance := kubeobj.MakePodRef(r.target.namespace, pod.Name, name, kubedef.DecideKind(srv))
+			if done, err := t(pod, instance); err != nil {
+				return nil, false, err
+			} else if done {
+				return nil, true, nil
 			}
 		}
 
@@ -439,39 +430,15 @@ func (r *ClusterNamespace) resolvePod(ctx context.Context, cli *kubernetes.Clien
 func (r *ClusterNamespace) DeployedConfigImageID(ctx context.Context, deployable runtime.Deployable) (oci.ImageID, error) {
 	return tasks.Return(ctx, tasks.Action("kubernetes.resolve-config-image-id").Scope(deployable.GetPackageRef().AsPackageName()),
 		func(ctx context.Context) (oci.ImageID, error) {
-			name := kubedef.MakeDeploymentId(deployable)
-
-			var o kubeobj.Object
-			switch schema.DeployableClass(deployable.GetDeployableClass()) {
-			case schema.DeployableClass_STATELESS:
-				d, err := r.underlying.cli.AppsV1().Deployments(r.target.namespace).Get(ctx, name, metav1.GetOptions{})
-				if err != nil {
-					return oci.ImageID{}, fnerrors.InvocationError("kubernetes", "failed to fetch deployment %s: %w", name, err)
-				}
-				o = d
-
-			case schema.DeployableClass_STATEFUL:
-				ss, err := r.underlying.cli.AppsV1().StatefulSets(r.target.namespace).Get(ctx, name, metav1.GetOptions{})
-				if err != nil {
-					return oci.ImageID{}, fnerrors.InvocationError("kubernetes", "failed to fetch stateful set %s: %w", name, err)
-				}
-				o = ss
-
-			case schema.DeployableClass_DAEMONSET:
-				ds, err := r.underlying.cli.AppsV1().DaemonSets(r.target.namespace).Get(ctx, name, metav1.GetOptions{})
-				if err != nil {
-					return oci.ImageID{}, fnerrors.InvocationError("kubernetes", "failed to fetch deamon set %s: %w", name, err)
-				}
-				o = ds
-
-			default:
-				return oci.ImageID{}, fnerrors.InternalError("unable to fetch config image id: unsupported deployable class %q", deployable.GetDeployableClass())
+			o, err := r.deployedObject(ctx, deployable)
+			if err != nil {
+				return oci.ImageID{}, err
 			}
 
 			cfgimage, ok := o.GetAnnotations()[kubedef.K8sConfigImage]
 			if !ok {
 				return oci.ImageID{}, fnerrors.BadInputError("%s: %q is missing as an annotation in %q",
-					deployable.GetPackageRef().GetPackageName(), kubedef.K8sConfigImage, name)
+					deployable.GetPackageRef().GetPackageName(), kubedef.K8sConfigImage, o.GetName())
 			}
 
 			imgid, err := oci.ParseImageID(cfgimage)
@@ -485,6 +452,64 @@ func (r *ClusterNamespace) DeployedConfigImageID(ctx context.Context, deployable
 		})
 }
 
+func (r *ClusterNamespace) DeployedResourceConfig(ctx context.Context, deployable runtime.Deployable) ([]byte, error) {
+	return tasks.Return(ctx, tasks.Action("kubernetes.fetch-resource-config").Scope(deployable.GetPackageRef().AsPackageName()),
+		func(ctx context.Context) ([]byte, error) {
+			o, err := r.deployedObject(ctx, deployable)
+			if err != nil {
+				return nil, err
+			}
+
+			configId, ok := o.GetAnnotations()[kubedef.K8sRuntimeConfig]
+			if !ok {
+				return nil, fnerrors.BadInputError("%s: %q is missing as an annotation in %q",
+					deployable.GetPackageRef().GetPackageName(), kubedef.K8sRuntimeConfig, o.GetName())
+			}
+
+			cm, err := r.underlying.cli.CoreV1().ConfigMaps(r.target.namespace).Get(ctx, configId, metav1.GetOptions{})
+			if err != nil {
+				return nil, fnerrors.InvocationError("kubernetes", "failed to fetch runtime configuration %s: %w", configId, err)
+			}
+
+			data, ok := cm.Data["resources.json"]
+			if !ok {
+				return nil, fnerrors.BadInputError("%s: was deployed without resources", deployable.GetPackageRef().GetPackageName())
+			}
+
+			return []byte(data), nil
+		})
+}
+
+func (r *ClusterNamespace) deployedObject(ctx context.Context, deployable runtime.Deployable) (kubeobj.Object, error) {
+	name := kubedef.MakeDeploymentId(deployable)
+
+	switch schema.DeployableClass(deployable.GetDeployableClass()) {
+	case schema.DeployableClass_STATELESS:
+		d, err := r.underlying.cli.AppsV1().Deployments(r.target.namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fnerrors.InvocationError("kubernetes", "failed to fetch deployment %s: %w", name, err)
+		}
+		return d, nil
+
+	case schema.DeployableClass_STATEFUL:
+		ss, err := r.underlying.cli.AppsV1().StatefulSets(r.target.namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fnerrors.InvocationError("kubernetes", "failed to fetch stateful set %s: %w", name, err)
+		}
+		return ss, nil
+
+	case schema.DeployableClass_DAEMONSET:
+		ds, err := r.underlying.cli.AppsV1().DaemonSets(r.target.namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fnerrors.InvocationError("kubernetes", "failed to fetch deamon set %s: %w", name, err)
+		}
+		return ds, nil
+
+	default:
+		return nil, fnerrors.InternalError("unable to fetch deployed object: unsupported deployable class %q", deployable.GetDeployableClass())
+	}
+}
+
 func (r *ClusterNamespace) StartTerminal(ctx context.Context, server runtime.Deployable, rio runtime.TerminalIO, command string, rest ...string) error {
 	cmd := append([]string{command}, rest...)
 
@@ -515,3 +540,14 @@ func (r *ClusterNamespace) DeleteDeployable(ctx context.Context, deployable runt
 		return fnerrors.InternalError("%s: unsupported deployable class", deployable.GetDeployableClass())
 	}
 }
+
+func (r *ClusterNamespace) InjectFault(ctx context.Context, server runtime.Deployable, fault runtime.Fault) (func(context.Context) error, error) {
+	return tasks.Return(ctx, tasks.Action("kubernetes.inject-fault").Arg("kind", fault.Kind).Arg("name", server.GetName()),
+		func(ctx context.Context) (func(context.Context) error, error) {
+			return faults.Inject(ctx, r.underlying.cli, r.target.namespace, server, faults.Fault{
+				Kind:      faults.Kind(fault.Kind),
+				Source:    fault.Source,
+				AdminPort: fault.AdminPort,
+			})
+		})
+}
@@ -0,0 +1,259 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package kubernetes
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "k8s.io/client-go/kubernetes"
+	"namespacelabs.dev/foundation/framework/kubernetes/kubedef"
+	"namespacelabs.dev/foundation/internal/console"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/internal/runtime"
+)
+
+const deployLockName = "ns-deploy-lock"
+
+// Deployment locks are implemented with a Lease, which is kept alive by
+// periodically bumping its renew time. A Lease without a duration never
+// expires.
+func (r *ClusterNamespace) LockDeployments(ctx context.Context, lock runtime.DeployLock, ttl time.Duration) (context.Context, func(context.Context) error, error) {
+	return lockDeployments(ctx, r.underlying.cli, r.target, lock, ttl)
+}
+
+func (r *ClusterNamespace) UnlockDeployments(ctx context.Context) (*runtime.DeployLock, error) {
+	return unlockDeployments(ctx, r.underlying.cli, r.target.namespace)
+}
+
+func lockDeployments(ctx context.Context, cli k8s.Interface, target BoundNamespace, lock runtime.DeployLock, ttl time.Duration) (context.Context, func(context.Context) error, error) {
+	leases := cli.CoordinationV1().Leases(target.namespace)
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, nil, err
+	}
+
+	now := metav1.NewMicroTime(time.Now())
+	identity := fmt.Sprintf("%s/%s", lock.Holder, hex.EncodeToString(suffix))
+
+	desired := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deployLockName,
+			Namespace: target.namespace,
+			Labels:    kubedef.MakeLabels(target.env, nil),
+			Annotations: map[string]string{
+				kubedef.K8sDeployLockHolder: lock.Holder,
+				kubedef.K8sDeployLockReason: lock.Reason,
+			},
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity: &identity,
+			AcquireTime:    &now,
+			RenewTime:      &now,
+		},
+	}
+
+	if lock.Manual {
+		desired.Annotations[kubedef.K8sDeployLockManual] = "true"
+	} else {
+		// The first deployment to a namespace creates it; do it upfront, so
+		// that the first deployment is also protected by the lock.
+		if _, err := cli.CoreV1().Namespaces().Apply(ctx, MakeNamespace(target.env, target.namespace), kubedef.Ego()); err != nil {
+			return nil, nil, fnerrors.InvocationError("kubernetes", "failed to ensure namespace %q: %w", target.namespace, err)
+		}
+	}
+
+	if ttl > 0 {
+		seconds := int32(ttl.Seconds())
+		desired.Spec.LeaseDurationSeconds = &seconds
+	}
+
+	current, err := acquireLease(ctx, cli, target.namespace, desired)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if lock.Manual || ttl <= 0 {
+		return ctx, func(context.Context) error { return nil }, nil
+	}
+
+	lockCtx, cancel := context.WithCancelCause(ctx)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		t := time.NewTicker(ttl / 3)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-lockCtx.Done():
+				return
+
+			case <-t.C:
+				renewed := current.DeepCopy()
+				renewTime := metav1.NewMicroTime(time.Now())
+				renewed.Spec.RenewTime = &renewTime
+
+				updated, err := leases.Update(lockCtx, renewed, metav1.UpdateOptions{FieldManager: kubedef.K8sFieldManager})
+				if err != nil {
+					if lockCtx.Err() != nil {
+						return
+					}
+
+					// Someone else took over, or removed the lock; or it may
+					// have expired before we managed to renew it.
+					if k8serrors.IsConflict(err) || k8serrors.IsNotFound(err) || time.Now().After(leaseExpiration(current)) {
+						cancel(fnerrors.InvocationError("kubernetes", "lost the deployment lock: %w", err))
+						return
+					}
+
+					fmt.Fprintf(console.Debug(ctx), "deploy lock: failed to renew, will retry: %v\n", err)
+					continue
+				}
+
+				current = updated
+			}
+		}
+	}()
+
+	return lockCtx, func(ctx context.Context) error {
+		cancel(nil)
+		wg.Wait()
+
+		// Only remove the lease if it wasn't modified by someone else in the meantime.
+		if err := leases.Delete(ctx, deployLockName, metav1.DeleteOptions{
+			Preconditions: &metav1.Preconditions{UID: &current.UID, ResourceVersion: &current.ResourceVersion},
+		}); err != nil && !k8serrors.IsNotFound(err) && !k8serrors.IsConflict(err) {
+			return fnerrors.InvocationError("kubernetes", "failed to release the deployment lock: %w", err)
+		}
+
+		return nil
+	}, nil
+}
+
+// acquireLease creates the lease, or takes it over if it has expired.
+func acquireLease(ctx context.Context, cli k8s.Interface, namespace string, desired *coordinationv1.Lease) (*coordinationv1.Lease, error) {
+	leases := cli.CoordinationV1().Leases(namespace)
+
+	for attempt := 0; attempt < 3; attempt++ {
+		existing, err := leases.Get(ctx, deployLockName, metav1.GetOptions{})
+		if err != nil {
+			if !k8serrors.IsNotFound(err) {
+				return nil, fnerrors.InvocationError("kubernetes", "failed to check the deployment lock: %w", err)
+			}
+
+			created, err := leases.Create(ctx, desired, metav1.CreateOptions{FieldManager: kubedef.K8sFieldManager})
+			if err == nil {
+				return created, nil
+			}
+
+			if k8serrors.IsAlreadyExists(err) {
+				continue
+			}
+
+			if k8serrors.IsNotFound(err) {
+				if _, nsErr := cli.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{}); k8serrors.IsNotFound(nsErr) {
+					return nil, fnerrors.BadInputError("can't lock deployments: namespace %q does not exist", namespace)
+				}
+			}
+
+			return nil, fnerrors.InvocationError("kubernetes", "failed to acquire the deployment lock: %w", err)
+		}
+
+		if !leaseExpired(existing, time.Now()) {
+			return nil, runtime.ErrDeploymentsLocked{Lock: leaseToLock(existing)}
+		}
+
+		takeover := desired.DeepCopy()
+		takeover.ResourceVersion = existing.ResourceVersion
+
+		updated, err := leases.Update(ctx, takeover, metav1.UpdateOptions{FieldManager: kubedef.K8sFieldManager})
+		if err == nil {
+			return updated, nil
+		}
+
+		if !k8serrors.IsConflict(err) {
+			return nil, fnerrors.InvocationError("kubernetes", "failed to acquire the deployment lock: %w", err)
+		}
+	}
+
+	return nil, fnerrors.InvocationError("kubernetes", "failed to acquire the deployment lock: too much contention")
+}
+
+func unlockDeployments(ctx context.Context, cli k8s.Interface, namespace string) (*runtime.DeployLock, error) {
+	leases := cli.CoordinationV1().Leases(namespace)
+
+	existing, err := leases.Get(ctx, deployLockName, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fnerrors.InvocationError("kubernetes", "failed to check the deployment lock: %w", err)
+	}
+
+	if err := leases.Delete(ctx, deployLockName, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &existing.UID},
+	}); err != nil && !k8serrors.IsNotFound(err) {
+		return nil, fnerrors.InvocationError("kubernetes", "failed to remove the deployment lock: %w", err)
+	}
+
+	lock := leaseToLock(existing)
+	return &lock, nil
+}
+
+func leaseExpired(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.LeaseDurationSeconds == nil {
+		return false
+	}
+
+	return now.After(leaseExpiration(lease))
+}
+
+func leaseExpiration(lease *coordinationv1.Lease) time.Time {
+	if lease.Spec.LeaseDurationSeconds == nil {
+		return time.Time{}
+	}
+
+	last := lease.CreationTimestamp.Time
+	if lease.Spec.RenewTime != nil {
+		last = lease.Spec.RenewTime.Time
+	} else if lease.Spec.AcquireTime != nil {
+		last = lease.Spec.AcquireTime.Time
+	}
+
+	return last.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+}
+
+func leaseToLock(lease *coordinationv1.Lease) runtime.DeployLock {
+	lock := runtime.DeployLock{
+		Holder:  lease.Annotations[kubedef.K8sDeployLockHolder],
+		Reason:  lease.Annotations[kubedef.K8sDeployLockReason],
+		Manual:  lease.Annotations[kubedef.K8sDeployLockManual] == "true",
+		Expires: leaseExpiration(lease),
+	}
+
+	if lock.Holder == "" && lease.Spec.HolderIdentity != nil {
+		lock.Holder = *lease.Spec.HolderIdentity
+	}
+
+	if lease.Spec.AcquireTime != nil {
+		lock.Acquired = lease.Spec.AcquireTime.Time
+	} else {
+		lock.Acquired = lease.CreationTimestamp.Time
+	}
+
+	return lock
+}
@@ -0,0 +1,150 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"namespacelabs.dev/foundation/framework/kubernetes/kubedef"
+	"namespacelabs.dev/foundation/internal/runtime"
+)
+
+func TestLeaseExpired(t *testing.T) {
+	renewed := metav1.NewMicroTime(time.Unix(1000, 0))
+	duration := int32(30)
+
+	lease := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{kubedef.K8sDeployLockHolder: "alice", kubedef.K8sDeployLockManual: "true"},
+		},
+		Spec: coordinationv1.LeaseSpec{AcquireTime: &renewed, RenewTime: &renewed},
+	}
+
+	if leaseExpired(lease, time.Unix(1_000_000, 0)) {
+		t.Error("leases without a duration should never expire")
+	}
+
+	if lock := leaseToLock(lease); lock.Holder != "alice" || !lock.Manual || !lock.Expires.IsZero() {
+		t.Errorf("unexpected lock: %+v", lock)
+	}
+
+	lease.Spec.LeaseDurationSeconds = &duration
+
+	if leaseExpired(lease, time.Unix(1020, 0)) {
+		t.Error("lease expired too early")
+	}
+
+	if !leaseExpired(lease, time.Unix(1031, 0)) {
+		t.Error("lease should have expired")
+	}
+}
+
+func TestLockDeploymentsContention(t *testing.T) {
+	ctx := context.Background()
+	cli := fake.NewClientset()
+	target := BoundNamespace{namespace: "test-ns"}
+
+	lockCtx, release, err := lockDeployments(ctx, cli, target, runtime.DeployLock{Holder: "alice", Reason: "deploying"}, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = lockDeployments(ctx, cli, target, runtime.DeployLock{Holder: "bob"}, time.Minute)
+
+	var locked runtime.ErrDeploymentsLocked
+	if !errors.As(err, &locked) {
+		t.Fatalf("expected ErrDeploymentsLocked, got %v", err)
+	}
+
+	if locked.Lock.Holder != "alice" || locked.Lock.Reason != "deploying" || locked.Lock.Expires.IsZero() {
+		t.Errorf("unexpected lock: %+v", locked.Lock)
+	}
+
+	if err := release(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if !errors.Is(context.Cause(lockCtx), context.Canceled) {
+		t.Errorf("expected the lock context to be canceled on release, got %v", context.Cause(lockCtx))
+	}
+
+	_, release, err = lockDeployments(ctx, cli, target, runtime.DeployLock{Holder: "bob"}, time.Minute)
+	if err != nil {
+		t.Fatalf("expected the released lock to be available: %v", err)
+	}
+
+	if err := release(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLockDeploymentsTakesOverExpiredLease(t *testing.T) {
+	ctx := context.Background()
+	target := BoundNamespace{namespace: "test-ns"}
+
+	renewed := metav1.NewMicroTime(time.Now().Add(-time.Minute))
+	duration := int32(10)
+
+	cli := fake.NewClientset(&coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        deployLockName,
+			Namespace:   "test-ns",
+			Annotations: map[string]string{kubedef.K8sDeployLockHolder: "alice"},
+		},
+		Spec: coordinationv1.LeaseSpec{AcquireTime: &renewed, RenewTime: &renewed, LeaseDurationSeconds: &duration},
+	})
+
+	if _, _, err := lockDeployments(ctx, cli, target, runtime.DeployLock{Holder: "bob", Manual: true}, 0); err != nil {
+		t.Fatalf("expected the expired lease to be taken over: %v", err)
+	}
+
+	lease, err := cli.CoordinationV1().Leases("test-ns").Get(ctx, deployLockName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if lock := leaseToLock(lease); lock.Holder != "bob" || !lock.Manual || !lock.Expires.IsZero() {
+		t.Errorf("unexpected lock: %+v", lock)
+	}
+}
+
+func TestLockDeploymentsCancelsOnLostLease(t *testing.T) {
+	ctx := context.Background()
+	cli := fake.NewClientset()
+
+	cli.PrependReactor("update", "leases", func(action k8stesting.Action) (bool, k8sruntime.Object, error) {
+		return true, nil, k8serrors.NewConflict(schema.GroupResource{Group: "coordination.k8s.io", Resource: "leases"}, deployLockName, errors.New("taken over"))
+	})
+
+	lockCtx, release, err := lockDeployments(ctx, cli, BoundNamespace{namespace: "test-ns"}, runtime.DeployLock{Holder: "alice"}, 300*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-lockCtx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the lock context to be canceled after failing to renew the lease")
+	}
+
+	if cause := context.Cause(lockCtx); cause == nil || !strings.Contains(cause.Error(), "lost the deployment lock") {
+		t.Errorf("unexpected cause: %v", cause)
+	}
+
+	if err := release(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,408 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+// Package faults injects faults into the servers of a namespace, to exercise
+// their resilience in tests. Faults are injected by the runtime, on behalf of
+// test drivers, which are granted no additional permissions.
+package faults
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"namespacelabs.dev/foundation/framework/kubernetes/kubeobj"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/std/runtime/constants"
+)
+
+type Deployable interface {
+	GetId() string
+	GetName() string
+}
+
+type Kind string
+
+const (
+	// Gracefully terminates each instance, which is then replaced.
+	Restart Kind = "restart"
+	// Terminates each instance without a grace period, which is then replaced.
+	Kill Kind = "kill"
+	// Cuts each instance off from all network traffic, in and out. This is not
+	// a process pause: the server keeps running, kubelet probes still reach it,
+	// and established connections may survive, depending on the network plugin.
+	Pause Kind = "pause"
+	// Drops new connections from Source to the server.
+	BlockTraffic Kind = "block-traffic"
+	// Begins the lameduck phase of each instance, without terminating it.
+	Lameduck Kind = "lameduck"
+)
+
+type Fault struct {
+	Kind Kind
+	// Required by BlockTraffic.
+	Source Deployable
+	// Required by Lameduck: the port of the server's internal HTTP endpoint.
+	AdminPort int32
+}
+
+const (
+	faultLabel = "k8s.namespacelabs.dev/fault"
+
+	pollInterval = 500 * time.Millisecond
+	pollTimeout  = 2 * time.Minute
+
+	// Runs the probes which verify that network faults are enforced.
+	probeImage = "busybox:1.36"
+	// How many seconds a probe waits for a fault to take effect.
+	probeAttempts = 15
+)
+
+// Inject injects a fault into each instance of the target. Faults which
+// persist, i.e. Pause and BlockTraffic, are reverted by calling the returned
+// function; it's nil otherwise.
+func Inject(ctx context.Context, cli kubernetes.Interface, namespace string, target Deployable, fault Fault) (func(context.Context) error, error) {
+	switch fault.Kind {
+	case Restart:
+		return nil, replacePods(ctx, cli, namespace, target, nil)
+
+	case Kill:
+		var zero int64
+		return nil, replacePods(ctx, cli, namespace, target, &zero)
+
+	case Pause:
+		return pause(ctx, cli, namespace, target)
+
+	case BlockTraffic:
+		if fault.Source == nil {
+			return nil, fnerrors.BadInputError("blocking traffic requires a source")
+		}
+
+		return blockTraffic(ctx, cli, namespace, fault.Source, target)
+
+	case Lameduck:
+		if fault.AdminPort <= 0 {
+			return nil, fnerrors.BadInputError("lameduck requires the server's internal http port")
+		}
+
+		return nil, beginLameduck(ctx, cli, namespace, target, fault.AdminPort)
+	}
+
+	return nil, fnerrors.BadInputError("unsupported fault %q", fault.Kind)
+}
+
+func listPods(ctx context.Context, cli kubernetes.Interface, namespace string, target Deployable) ([]corev1.Pod, error) {
+	pods, err := cli.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: kubeobj.SerializeSelector(kubeobj.SelectById(target)),
+	})
+	if err != nil {
+		return nil, fnerrors.InvocationError("kubernetes", "failed to list pods: %w", err)
+	}
+
+	if len(pods.Items) == 0 {
+		return nil, fnerrors.Newf("%s: no instances found", target.GetName())
+	}
+
+	return pods.Items, nil
+}
+
+func replacePods(ctx context.Context, cli kubernetes.Interface, namespace string, target Deployable, gracePeriod *int64) error {
+	pods, err := listPods(ctx, cli, namespace, target)
+	if err != nil {
+		return err
+	}
+
+	for _, pod := range pods {
+		if err := cli.CoreV1().Pods(namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{GracePeriodSeconds: gracePeriod}); err != nil && !k8serrors.IsNotFound(err) {
+			return fnerrors.InvocationError("kubernetes", "failed to delete %q: %w", pod.Name, err)
+		}
+
+		// Pods managed by a controller are replaced by it. But servers are
+		// deployed as bare pods in tests, and those are recreated here.
+		if len(pod.OwnerReferences) > 0 {
+			continue
+		}
+
+		if err := waitUntilDeleted(ctx, cli, namespace, pod.Name); err != nil {
+			return err
+		}
+
+		if _, err := cli.CoreV1().Pods(namespace).Create(ctx, recreate(pod), metav1.CreateOptions{}); err != nil {
+			return fnerrors.InvocationError("kubernetes", "failed to recreate %q: %w", pod.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func recreate(pod corev1.Pod) *corev1.Pod {
+	spec := pod.Spec.DeepCopy()
+	spec.NodeName = "" // Let the scheduler decide.
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        pod.Name,
+			Namespace:   pod.Namespace,
+			Labels:      pod.Labels,
+			Annotations: pod.Annotations,
+		},
+		Spec: *spec,
+	}
+}
+
+func waitUntilDeleted(ctx context.Context, cli kubernetes.Interface, namespace, name string) error {
+	return wait.PollUntilContextTimeout(ctx, pollInterval, pollTimeout, true, func(ctx context.Context) (bool, error) {
+		_, err := cli.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			return true, nil
+		}
+
+		return false, err
+	})
+}
+
+// pause relies on a network plugin which enforces NetworkPolicies. Unlike
+// suspending the server's processes, this requires no privileges; the server
+// keeps running, but can neither be reached nor reach anything else. As not
+// every network plugin enforces NetworkPolicies, the fault is only reported
+// as injected once a probe fails to reach the server.
+func pause(ctx context.Context, cli kubernetes.Interface, namespace string, target Deployable) (func(context.Context) error, error) {
+	pods, err := listPods(ctx, cli, namespace, target)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := pausePolicy(namespace, target)
+	probe, err := probePod(policy, nil, pods)
+	if err != nil {
+		return nil, err
+	}
+
+	return applyEnforcedPolicy(ctx, cli, policy, probe)
+}
+
+func pausePolicy(namespace string, target Deployable) *networkingv1.NetworkPolicy {
+	// No rules: all ingress and egress traffic is denied.
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("pause-%s", target.GetId()),
+			Namespace: namespace,
+			Labels:    map[string]string{faultLabel: string(Pause)},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: kubeobj.SelectById(target)},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+		},
+	}
+}
+
+// blockTraffic relies on a network plugin which enforces NetworkPolicies. The
+// target remains reachable from everything else, including other namespaces.
+// As with pause, enforcement is verified with a probe which poses as source.
+func blockTraffic(ctx context.Context, cli kubernetes.Interface, namespace string, source, target Deployable) (func(context.Context) error, error) {
+	pods, err := listPods(ctx, cli, namespace, target)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := blockPolicy(namespace, source, target)
+	probe, err := probePod(policy, kubeobj.SelectById(source), pods)
+	if err != nil {
+		return nil, err
+	}
+
+	return applyEnforcedPolicy(ctx, cli, policy, probe)
+}
+
+func blockPolicy(namespace string, source, target Deployable) *networkingv1.NetworkPolicy {
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("block-%s-%s", source.GetId(), target.GetId()),
+			Namespace: namespace,
+			Labels:    map[string]string{faultLabel: string(BlockTraffic)},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: kubeobj.SelectById(target)},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{
+				From: []networkingv1.NetworkPolicyPeer{
+					{PodSelector: &metav1.LabelSelector{
+						MatchExpressions: []metav1.LabelSelectorRequirement{{
+							Key:      kubeobj.K8sServerId,
+							Operator: metav1.LabelSelectorOpNotIn,
+							Values:   []string{source.GetId()},
+						}},
+					}},
+					{NamespaceSelector: &metav1.LabelSelector{
+						MatchExpressions: []metav1.LabelSelectorRequirement{{
+							Key:      corev1.LabelMetadataName,
+							Operator: metav1.LabelSelectorOpNotIn,
+							Values:   []string{namespace},
+						}},
+					}},
+				},
+			}},
+		},
+	}
+}
+
+// probePod returns a pod, labelled with labels, which exits successfully once
+// none of the targets accepts connections on its first port; or fails if they
+// remain reachable after probeAttempts. It's never ready, so that it isn't
+// picked up by any service whose selector its labels match.
+func probePod(policy *networkingv1.NetworkPolicy, labels map[string]string, targets []corev1.Pod) (*corev1.Pod, error) {
+	var addrs []string
+	for _, pod := range targets {
+		port := firstPort(pod)
+		if pod.Status.PodIP == "" || port == 0 {
+			return nil, fnerrors.Newf("%s: can't verify that the fault is enforced: no address to probe", pod.Name)
+		}
+
+		addrs = append(addrs, net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(port))))
+	}
+
+	podLabels := map[string]string{faultLabel: "probe"}
+	for k, v := range labels {
+		podLabels[k] = v
+	}
+
+	script := fmt.Sprintf(`for addr in $PROBE_ADDRS; do
+  host="${addr%%:*}"; host="${host#[}"; host="${host%%]}"
+  n=0
+  while nc -z -w 2 "$host" "${addr##*:}"; do
+    n=$((n+1))
+    if [ "$n" -ge %d ]; then echo "$addr is reachable"; exit 1; fi
+    sleep 1
+  done
+done`, probeAttempts)
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-probe", policy.Name),
+			Namespace: policy.Namespace,
+			Labels:    podLabels,
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{{
+				Name:    "probe",
+				Image:   probeImage,
+				Command: []string{"sh", "-c", script},
+				Env:     []corev1.EnvVar{{Name: "PROBE_ADDRS", Value: strings.Join(addrs, " ")}},
+				ReadinessProbe: &corev1.Probe{
+					ProbeHandler: corev1.ProbeHandler{Exec: &corev1.ExecAction{Command: []string{"false"}}},
+				},
+			}},
+		},
+	}, nil
+}
+
+func firstPort(pod corev1.Pod) int32 {
+	for _, ctr := range pod.Spec.Containers {
+		for _, port := range ctr.Ports {
+			if port.Protocol == "" || port.Protocol == corev1.ProtocolTCP {
+				return port.ContainerPort
+			}
+		}
+	}
+
+	return 0
+}
+
+// applyEnforcedPolicy applies the policy, and then runs the probe; if the
+// probe fails, i.e. the policy isn't enforced, the policy is reverted.
+func applyEnforcedPolicy(ctx context.Context, cli kubernetes.Interface, policy *networkingv1.NetworkPolicy, probe *corev1.Pod) (func(context.Context) error, error) {
+	revert, err := applyPolicy(ctx, cli, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := runProbe(ctx, cli, probe); err != nil {
+		if revertErr := revert(context.WithoutCancel(ctx)); revertErr != nil {
+			return nil, errors.Join(err, revertErr)
+		}
+
+		return nil, err
+	}
+
+	return revert, nil
+}
+
+func runProbe(ctx context.Context, cli kubernetes.Interface, probe *corev1.Pod) error {
+	pods := cli.CoreV1().Pods(probe.Namespace)
+
+	if _, err := pods.Create(ctx, probe, metav1.CreateOptions{}); err != nil {
+		return fnerrors.InvocationError("kubernetes", "%s: failed to verify fault: %w", probe.Name, err)
+	}
+
+	defer func() {
+		var zero int64
+		_ = pods.Delete(context.WithoutCancel(ctx), probe.Name, metav1.DeleteOptions{GracePeriodSeconds: &zero})
+	}()
+
+	var phase corev1.PodPhase
+	if err := wait.PollUntilContextTimeout(ctx, pollInterval, pollTimeout, true, func(ctx context.Context) (bool, error) {
+		pod, err := pods.Get(ctx, probe.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		phase = pod.Status.Phase
+		return phase == corev1.PodSucceeded || phase == corev1.PodFailed, nil
+	}); err != nil {
+		return fnerrors.InvocationError("kubernetes", "%s: failed to verify fault: %w", probe.Name, err)
+	}
+
+	if phase != corev1.PodSucceeded {
+		return fnerrors.Newf("%s: the fault is not enforced, the server is still reachable; the cluster's network plugin must enforce NetworkPolicies", probe.Name)
+	}
+
+	return nil
+}
+
+func applyPolicy(ctx context.Context, cli kubernetes.Interface, policy *networkingv1.NetworkPolicy) (func(context.Context) error, error) {
+	created, err := cli.NetworkingV1().NetworkPolicies(policy.Namespace).Create(ctx, policy, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fnerrors.InvocationError("kubernetes", "%s: failed to inject fault: %w", policy.Name, err)
+	}
+
+	return func(ctx context.Context) error {
+		if err := cli.NetworkingV1().NetworkPolicies(created.Namespace).Delete(ctx, created.Name, metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+			return fnerrors.InvocationError("kubernetes", "%s: failed to revert fault: %w", created.Name, err)
+		}
+
+		return nil
+	}, nil
+}
+
+func beginLameduck(ctx context.Context, cli kubernetes.Interface, namespace string, target Deployable, port int32) error {
+	pods, err := listPods(ctx, cli, namespace, target)
+	if err != nil {
+		return err
+	}
+
+	for _, pod := range pods {
+		if err := cli.CoreV1().RESTClient().Post().
+			Namespace(namespace).
+			Resource("pods").
+			SubResource("proxy").
+			Name(fmt.Sprintf("%s:%d", pod.Name, port)).
+			Suffix(constants.LameduckPath).
+			Do(ctx).Error(); err != nil {
+			return fnerrors.InvocationError("kubernetes", "%s: failed to begin lameduck: %w", pod.Name, err)
+		}
+	}
+
+	return nil
+}
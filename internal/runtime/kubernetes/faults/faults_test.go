@@ -0,0 +1,194 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package faults
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"namespacelabs.dev/foundation/framework/kubernetes/kubeobj"
+)
+
+type server struct{ id string }
+
+func (s server) GetId() string   { return s.id }
+func (s server) GetName() string { return s.id }
+
+func TestPausePolicy(t *testing.T) {
+	policy := pausePolicy("ns", server{"target"})
+
+	if policy.Name != "pause-target" || policy.Namespace != "ns" {
+		t.Errorf("unexpected policy: %s/%s", policy.Namespace, policy.Name)
+	}
+
+	want := networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{kubeobj.K8sServerId: "target"}},
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+	}
+
+	if d := cmp.Diff(want, policy.Spec); d != "" {
+		t.Errorf("unexpected spec (-want +got):\n%s", d)
+	}
+}
+
+func TestBlockPolicy(t *testing.T) {
+	policy := blockPolicy("ns", server{"source"}, server{"target"})
+
+	if policy.Name != "block-source-target" {
+		t.Errorf("unexpected policy name: %s", policy.Name)
+	}
+
+	if d := cmp.Diff(map[string]string{kubeobj.K8sServerId: "target"}, policy.Spec.PodSelector.MatchLabels); d != "" {
+		t.Errorf("unexpected pod selector (-want +got):\n%s", d)
+	}
+
+	if d := cmp.Diff([]networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, policy.Spec.PolicyTypes); d != "" {
+		t.Errorf("egress must not be restricted (-want +got):\n%s", d)
+	}
+
+	if len(policy.Spec.Ingress) != 1 || len(policy.Spec.Ingress[0].From) != 2 {
+		t.Fatalf("expected a single ingress rule with two peers, got %+v", policy.Spec.Ingress)
+	}
+
+	// Everything but the source, in the same namespace; and other namespaces.
+	pods, namespaces := policy.Spec.Ingress[0].From[0], policy.Spec.Ingress[0].From[1]
+
+	if d := cmp.Diff([]metav1.LabelSelectorRequirement{{
+		Key:      kubeobj.K8sServerId,
+		Operator: metav1.LabelSelectorOpNotIn,
+		Values:   []string{"source"},
+	}}, pods.PodSelector.MatchExpressions); d != "" || pods.NamespaceSelector != nil {
+		t.Errorf("unexpected pod peer (-want +got):\n%s", d)
+	}
+
+	if d := cmp.Diff([]metav1.LabelSelectorRequirement{{
+		Key:      corev1.LabelMetadataName,
+		Operator: metav1.LabelSelectorOpNotIn,
+		Values:   []string{"ns"},
+	}}, namespaces.NamespaceSelector.MatchExpressions); d != "" || namespaces.PodSelector != nil {
+		t.Errorf("unexpected namespace peer (-want +got):\n%s", d)
+	}
+}
+
+func TestProbePod(t *testing.T) {
+	policy := blockPolicy("ns", server{"source"}, server{"target"})
+
+	targets := []corev1.Pod{
+		targetPod("a", "10.0.0.1", corev1.ContainerPort{ContainerPort: 53, Protocol: corev1.ProtocolUDP}, corev1.ContainerPort{ContainerPort: 8080}),
+		targetPod("b", "fd00::1", corev1.ContainerPort{ContainerPort: 9000, Protocol: corev1.ProtocolTCP}),
+	}
+
+	probe, err := probePod(policy, map[string]string{kubeobj.K8sServerId: "source"}, targets)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if probe.Name != "block-source-target-probe" || probe.Namespace != "ns" {
+		t.Errorf("unexpected probe: %s/%s", probe.Namespace, probe.Name)
+	}
+
+	if d := cmp.Diff(map[string]string{kubeobj.K8sServerId: "source", faultLabel: "probe"}, probe.Labels); d != "" {
+		t.Errorf("unexpected labels (-want +got):\n%s", d)
+	}
+
+	if probe.Spec.RestartPolicy != corev1.RestartPolicyNever {
+		t.Errorf("the probe must not be restarted, got %q", probe.Spec.RestartPolicy)
+	}
+
+	ctr := probe.Spec.Containers[0]
+	if d := cmp.Diff([]corev1.EnvVar{{Name: "PROBE_ADDRS", Value: "10.0.0.1:8080 [fd00::1]:9000"}}, ctr.Env); d != "" {
+		t.Errorf("unexpected addresses (-want +got):\n%s", d)
+	}
+
+	if ctr.ReadinessProbe == nil {
+		t.Error("the probe must never become ready, to stay out of the source's services")
+	}
+
+	if _, err := probePod(policy, nil, []corev1.Pod{targetPod("c", "10.0.0.2")}); err == nil || !strings.Contains(err.Error(), "no address to probe") {
+		t.Errorf("expected an error for a pod without ports, got %v", err)
+	}
+
+	if _, err := probePod(policy, nil, []corev1.Pod{targetPod("d", "", corev1.ContainerPort{ContainerPort: 8080})}); err == nil {
+		t.Error("expected an error for a pod without an address")
+	}
+}
+
+func TestReplacePods(t *testing.T) {
+	bare := targetPod("bare", "10.0.0.1", corev1.ContainerPort{ContainerPort: 8080})
+	bare.Annotations = map[string]string{"a": "b"}
+	bare.Spec.NodeName = "node-1"
+	bare.ResourceVersion = "42"
+
+	owned := targetPod("owned", "10.0.0.2", corev1.ContainerPort{ContainerPort: 8080})
+	owned.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "rs"}}
+
+	other := targetPod("other", "10.0.0.3")
+	other.Labels = map[string]string{kubeobj.K8sServerId: "other"}
+
+	cli := fake.NewClientset(&bare, &owned, &other)
+
+	if err := replacePods(context.Background(), cli, "ns", server{"target"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	pods, err := cli.CoreV1().Pods("ns").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]corev1.Pod{}
+	for _, pod := range pods.Items {
+		got[pod.Name] = pod
+	}
+
+	if _, ok := got["owned"]; ok {
+		t.Error("pods with an owner are replaced by their controller, and must not be recreated")
+	}
+
+	if _, ok := got["other"]; !ok {
+		t.Error("pods of other servers must be left alone")
+	}
+
+	recreated, ok := got["bare"]
+	if !ok {
+		t.Fatal("expected the bare pod to be recreated")
+	}
+
+	if recreated.Spec.NodeName != "" || recreated.ResourceVersion == "42" || recreated.Status.PodIP != "" {
+		t.Errorf("expected a fresh pod, got %+v", recreated)
+	}
+
+	if d := cmp.Diff(bare.Labels, recreated.Labels); d != "" {
+		t.Errorf("unexpected labels (-want +got):\n%s", d)
+	}
+
+	if d := cmp.Diff(bare.Annotations, recreated.Annotations); d != "" {
+		t.Errorf("unexpected annotations (-want +got):\n%s", d)
+	}
+
+	if d := cmp.Diff(bare.Spec.Containers, recreated.Spec.Containers); d != "" {
+		t.Errorf("unexpected containers (-want +got):\n%s", d)
+	}
+}
+
+func targetPod(name, ip string, ports ...corev1.ContainerPort) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "ns",
+			Labels:    map[string]string{kubeobj.K8sServerId: "target"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "server", Image: "example.com/server", Ports: ports}},
+		},
+		Status: corev1.PodStatus{PodIP: ip},
+	}
+}
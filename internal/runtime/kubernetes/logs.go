@@ -57,6 +57,20 @@ func fetchPodLogs(ctx context.Context, cli *kubernetes.Clientset, namespace, pod
 		logOpts.TailLines = &tailLines
 	}
 
+	if !opts.Since.IsZero() {
+		since := metav1.NewTime(opts.Since)
+		logOpts.SinceTime = &since
+	}
+
+	if opts.Follow && !opts.Until.IsZero() {
+		// There's no server-side support for an upper bound, so stop streaming once it's reached.
+		var cancel func()
+		ctx, cancel = context.WithDeadline(ctx, opts.Until)
+		defer cancel()
+	}
+
+	callback = opts.Filter(callback)
+
 	var buf bytes.Buffer
 	chunk := make([]byte, 4096)
 
@@ -111,7 +125,7 @@ func fetchPodLogs(ctx context.Context, cli *kubernetes.Clientset, namespace, pod
 				}
 			}
 
-			if err == io.EOF || errors.Is(err, context.Canceled) {
+			if err == io.EOF || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
 				return nil
 			} else if err != nil {
 				if !logOpts.Follow {
@@ -0,0 +1,166 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package kubernetes
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"google.golang.org/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "k8s.io/client-go/kubernetes"
+	"namespacelabs.dev/foundation/framework/kubernetes/kubedef"
+	"namespacelabs.dev/foundation/framework/kubernetes/kubeobj"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/internal/runtime"
+	"namespacelabs.dev/foundation/schema"
+)
+
+const (
+	// Older revisions are removed when a new revision is recorded.
+	maxDeployRevisions = 20
+
+	revisionMetadataKey = "revision.json"
+	revisionPlanKey     = "deployplan.binarypb.gz"
+)
+
+// Deployment revisions are stored as Secrets, as plans may embed sensitive
+// configuration.
+func (r *ClusterNamespace) RecordDeploymentRevision(ctx context.Context, rev runtime.DeploymentRevision, plan *schema.DeployPlan) (int64, error) {
+	return recordDeploymentRevision(ctx, r.underlying.cli, r.target, rev, plan)
+}
+
+func (r *ClusterNamespace) ListDeploymentRevisions(ctx context.Context) ([]runtime.DeploymentRevision, error) {
+	return listDeploymentRevisions(ctx, r.underlying.cli, r.target.namespace)
+}
+
+func (r *ClusterNamespace) LoadDeploymentRevision(ctx context.Context, revision int64) (*schema.DeployPlan, error) {
+	return loadDeploymentRevision(ctx, r.underlying.cli, r.target.namespace, revision)
+}
+
+func recordDeploymentRevision(ctx context.Context, cli k8s.Interface, target BoundNamespace, rev runtime.DeploymentRevision, plan *schema.DeployPlan) (int64, error) {
+	existing, err := listDeploymentRevisions(ctx, cli, target.namespace)
+	if err != nil {
+		return 0, err
+	}
+
+	rev.Revision = 1
+	if len(existing) > 0 {
+		rev.Revision = existing[0].Revision + 1
+	}
+
+	metadata, err := json.Marshal(rev)
+	if err != nil {
+		return 0, fnerrors.InternalError("failed to serialize revision: %w", err)
+	}
+
+	serialized, err := proto.Marshal(plan)
+	if err != nil {
+		return 0, fnerrors.InternalError("failed to serialize plan: %w", err)
+	}
+
+	var compressed bytes.Buffer
+	w := gzip.NewWriter(&compressed)
+	if _, err := w.Write(serialized); err != nil {
+		return 0, err
+	}
+	if err := w.Close(); err != nil {
+		return 0, err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        revisionSecretName(rev.Revision),
+			Namespace:   target.namespace,
+			Labels:      kubedef.MakeLabels(target.env, nil),
+			Annotations: kubedef.MakeAnnotations(target.env),
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			revisionMetadataKey: metadata,
+			revisionPlanKey:     compressed.Bytes(),
+		},
+	}
+	secret.Labels[kubedef.K8sKind] = kubedef.K8sDeployRevisionKind
+	secret.Labels[kubedef.K8sDeployRevision] = strconv.FormatInt(rev.Revision, 10)
+
+	// Create (rather than apply) so that concurrent deployments don't silently
+	// overwrite each other's revision.
+	if _, err := cli.CoreV1().Secrets(target.namespace).Create(ctx, secret, metav1.CreateOptions{FieldManager: kubedef.K8sFieldManager}); err != nil {
+		return 0, fnerrors.InvocationError("kubernetes", "failed to record revision %d: %w", rev.Revision, err)
+	}
+
+	for k := maxDeployRevisions - 1; k < len(existing); k++ {
+		if err := cli.CoreV1().Secrets(target.namespace).Delete(ctx, revisionSecretName(existing[k].Revision), metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+			return rev.Revision, fnerrors.InvocationError("kubernetes", "failed to remove old revision %d: %w", existing[k].Revision, err)
+		}
+	}
+
+	return rev.Revision, nil
+}
+
+func loadDeploymentRevision(ctx context.Context, cli k8s.Interface, namespace string, revision int64) (*schema.DeployPlan, error) {
+	secret, err := cli.CoreV1().Secrets(namespace).Get(ctx, revisionSecretName(revision), metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, fnerrors.BadInputError("revision %d not found in namespace %q", revision, namespace)
+		}
+		return nil, fnerrors.InvocationError("kubernetes", "failed to load revision %d: %w", revision, err)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(secret.Data[revisionPlanKey]))
+	if err != nil {
+		return nil, fnerrors.BadDataError("revision %d: failed to decompress plan: %w", revision, err)
+	}
+
+	serialized, err := io.ReadAll(gz)
+	if err != nil {
+		return nil, fnerrors.BadDataError("revision %d: failed to decompress plan: %w", revision, err)
+	}
+
+	plan := &schema.DeployPlan{}
+	if err := proto.Unmarshal(serialized, plan); err != nil {
+		return nil, fnerrors.BadDataError("revision %d: failed to unmarshal plan: %w", revision, err)
+	}
+
+	return plan, nil
+}
+
+func listDeploymentRevisions(ctx context.Context, cli k8s.Interface, namespace string) ([]runtime.DeploymentRevision, error) {
+	list, err := cli.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: kubeobj.SerializeSelector(map[string]string{
+			kubedef.K8sKind: kubedef.K8sDeployRevisionKind,
+		}),
+	})
+	if err != nil {
+		return nil, fnerrors.InvocationError("kubernetes", "failed to list revisions: %w", err)
+	}
+
+	var revisions []runtime.DeploymentRevision
+	for _, secret := range list.Items {
+		var rev runtime.DeploymentRevision
+		if err := json.Unmarshal(secret.Data[revisionMetadataKey], &rev); err != nil {
+			return nil, fnerrors.BadDataError("%s: failed to parse revision: %w", secret.Name, err)
+		}
+
+		revisions = append(revisions, rev)
+	}
+
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i].Revision > revisions[j].Revision })
+
+	return revisions, nil
+}
+
+func revisionSecretName(revision int64) string {
+	return fmt.Sprintf("ns-deploy-revision-%d", revision)
+}
@@ -0,0 +1,114 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"namespacelabs.dev/foundation/framework/kubernetes/kubedef"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/internal/runtime"
+	"namespacelabs.dev/foundation/schema"
+)
+
+func TestRecordAndLoadDeploymentRevision(t *testing.T) {
+	ctx := context.Background()
+	cli := fake.NewClientset()
+	target := BoundNamespace{env: &schema.Environment{Name: "prod", Purpose: schema.Environment_PRODUCTION}, namespace: "test-ns"}
+
+	plan := &schema.DeployPlan{
+		Environment: target.env,
+		FocusServer: []string{"example.com/server"},
+	}
+
+	revision, err := recordDeploymentRevision(ctx, cli, target, runtime.DeploymentRevision{Author: "alice", Reason: "first"}, plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if revision != 1 {
+		t.Errorf("expected revision 1, got %d", revision)
+	}
+
+	secret, err := cli.CoreV1().Secrets("test-ns").Get(ctx, revisionSecretName(1), metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if secret.Labels[kubedef.K8sKind] != kubedef.K8sDeployRevisionKind || secret.Labels[kubedef.K8sDeployRevision] != "1" {
+		t.Errorf("unexpected labels: %v", secret.Labels)
+	}
+
+	loaded, err := loadDeploymentRevision(ctx, cli, "test-ns", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !proto.Equal(plan, loaded) {
+		t.Errorf("loaded plan differs: got %v, want %v", loaded, plan)
+	}
+
+	revisions, err := listDeploymentRevisions(ctx, cli, "test-ns")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(revisions) != 1 || revisions[0].Revision != 1 || revisions[0].Author != "alice" || revisions[0].Reason != "first" {
+		t.Errorf("unexpected revisions: %+v", revisions)
+	}
+}
+
+func TestLoadMissingDeploymentRevision(t *testing.T) {
+	_, err := loadDeploymentRevision(context.Background(), fake.NewClientset(), "test-ns", 3)
+	if !fnerrors.IsOfKind(err, fnerrors.Kind_BADINPUT) {
+		t.Errorf("expected a bad input error, got %v", err)
+	}
+}
+
+func TestDeploymentRevisionsArePruned(t *testing.T) {
+	ctx := context.Background()
+	cli := fake.NewClientset()
+	target := BoundNamespace{env: &schema.Environment{Name: "prod"}, namespace: "test-ns"}
+
+	const total = maxDeployRevisions + 5
+	for k := 1; k <= total; k++ {
+		revision, err := recordDeploymentRevision(ctx, cli, target, runtime.DeploymentRevision{}, &schema.DeployPlan{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if revision != int64(k) {
+			t.Fatalf("expected revision %d, got %d", k, revision)
+		}
+	}
+
+	revisions, err := listDeploymentRevisions(ctx, cli, "test-ns")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(revisions) != maxDeployRevisions {
+		t.Fatalf("expected %d revisions, got %d", maxDeployRevisions, len(revisions))
+	}
+
+	// Newest first.
+	for k, rev := range revisions {
+		if want := int64(total - k); rev.Revision != want {
+			t.Errorf("revisions[%d]: expected revision %d, got %d", k, want, rev.Revision)
+		}
+	}
+
+	if _, err := loadDeploymentRevision(ctx, cli, "test-ns", total-maxDeployRevisions); err == nil {
+		t.Errorf("expected revision %d to have been pruned", total-maxDeployRevisions)
+	}
+
+	if _, err := loadDeploymentRevision(ctx, cli, "test-ns", total-maxDeployRevisions+1); err != nil {
+		t.Errorf("expected revision %d to be kept: %v", total-maxDeployRevisions+1, err)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"namespacelabs.dev/foundation/framework/kubernetes/kubedef"
+	"namespacelabs.dev/foundation/framework/kubernetes/kubeobj"
+	"namespacelabs.dev/foundation/internal/executor"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/internal/runtime"
+	runtimepb "namespacelabs.dev/foundation/schema/runtime"
+)
+
+// How long lines are held back, so that lines from different containers can
+// be emitted in timestamp order.
+const logMergeWindow = 500 * time.Millisecond
+
+func (r *ClusterNamespace) FetchServerLogs(ctx context.Context, servers []runtime.Deployable, opts runtime.ServerLogsOpts, callback func(runtime.ServerLogLine)) error {
+	if opts.Fetch.Follow && !opts.Fetch.Until.IsZero() {
+		// Each stream stops on its own once Until is reached, but new containers
+		// would otherwise keep being observed.
+		var cancel func()
+		ctx, cancel = context.WithDeadline(ctx, opts.Fetch.Until.Add(logMergeWindow))
+		defer cancel()
+	}
+
+	merger := runtime.NewLogMerger(logMergeWindow, callback)
+
+	// The merger outlives the streams, so that their last lines are emitted.
+	mergeCtx, stopMerging := context.WithCancel(context.WithoutCancel(ctx))
+	merged := make(chan struct{})
+	go func() {
+		defer close(merged)
+		merger.Run(mergeCtx)
+	}()
+
+	eg := executor.New(ctx, "kubernetes.fetch-server-logs")
+	for _, srv := range servers {
+		eg.Go(func(ctx context.Context) error {
+			if opts.Fetch.Follow {
+				return r.followServerLogs(ctx, srv, opts, merger)
+			}
+
+			return r.dumpServerLogs(ctx, srv, opts, merger)
+		})
+	}
+
+	err := eg.Wait()
+
+	stopMerging()
+	<-merged
+
+	if errors.Is(err, context.DeadlineExceeded) && !opts.Fetch.Until.IsZero() {
+		return nil
+	}
+
+	return err
+}
+
+func (r *ClusterNamespace) dumpServerLogs(ctx context.Context, srv runtime.Deployable, opts runtime.ServerLogsOpts, merger *runtime.LogMerger) error {
+	pods, err := r.underlying.cli.CoreV1().Pods(r.target.namespace).List(ctx, metav1.ListOptions{LabelSelector: kubeobj.SerializeSelector(kubedef.SelectById(srv))})
+	if err != nil {
+		return fnerrors.InvocationError("kubernetes", "failed to list pods: %w", err)
+	}
+
+	eg := executor.New(ctx, "kubernetes.dump-server-logs")
+
+	var matched int
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodPending {
+			continue
+		}
+
+		for _, name := range observedContainers(srv, pod, opts.Observe) {
+			matched++
+			ref := kubeobj.MakePodRef(r.target.namespace, pod.Name, name, kubedef.DecideKind(srv))
+			eg.Go(func(ctx context.Context) error {
+				return r.underlying.FetchLogsTo(ctx, ref, opts.Fetch, mergeInto(merger, srv, ref))
+			})
+		}
+	}
+
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	if matched == 0 {
+		return fnerrors.Newf("%s: no matching containers", srv.GetName())
+	}
+
+	return nil
+}
+
+func (r *ClusterNamespace) followServerLogs(ctx context.Context, srv runtime.Deployable, opts runtime.ServerLogsOpts, merger *runtime.LogMerger) error {
+	eg := executor.New(ctx, "kubernetes.follow-server-logs")
+
+	var mu sync.Mutex
+	streams := map[string]func(){} // Cancels each stream, by container.
+
+	eg.Go(func(ctx context.Context) error {
+		return r.Observe(ctx, srv, opts.Observe, func(ev runtime.ObserveEvent) (bool, error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			ref := ev.ContainerReference
+			if cancel, ok := streams[ref.UniqueId]; ok && ev.Removed {
+				cancel()
+				delete(streams, ref.UniqueId)
+			} else if !ok && ev.Added {
+				streams[ref.UniqueId] = eg.GoCancelable(func(ctx context.Context) error {
+					return r.underlying.FetchLogsTo(ctx, ref, opts.Fetch, mergeInto(merger, srv, ref))
+				})
+			}
+
+			return false, nil
+		})
+	})
+
+	return eg.Wait()
+}
+
+func mergeInto(merger *runtime.LogMerger, srv runtime.Deployable, ref *runtimepb.ContainerReference) func(runtime.ContainerLogLine) {
+	return func(line runtime.ContainerLogLine) {
+		merger.Add(runtime.ServerLogLine{ContainerLogLine: line, Server: srv, Container: ref})
+	}
+}
+
+func observedContainers(srv runtime.Deployable, pod corev1.Pod, opts runtime.ObserveOpts) []string {
+	var names []string
+
+	// When specific containers are requested, only those are observed.
+	if len(opts.ContainerNames) > 0 {
+		for _, container := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+			if slices.Contains(opts.ContainerNames, container.Name) {
+				names = append(names, container.Name)
+			}
+		}
+		return names
+	}
+
+	if ObserveInitContainerLogs || opts.InitContainers {
+		for _, container := range pod.Spec.InitContainers {
+			names = append(names, container.Name)
+		}
+	}
+
+	main := kubedef.ServerCtrName(srv)
+	for _, container := range pod.Spec.Containers {
+		if container.Name == main || opts.Sidecars {
+			names = append(names, container.Name)
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,206 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"namespacelabs.dev/foundation/internal/fnerrors"
+	runtimepb "namespacelabs.dev/foundation/schema/runtime"
+)
+
+// ParseLogTime parses either an RFC3339 timestamp, or a duration (e.g. 10m)
+// which is relative to `now`.
+func ParseLogTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fnerrors.BadInputError("%q: expected a duration (e.g. 10m), or an RFC3339 timestamp", value)
+	}
+
+	return t, nil
+}
+
+func CompileLogPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	var compiled []*regexp.Regexp
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fnerrors.BadInputError("%q: invalid regular expression: %w", p, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// Matches returns true if the log line should be passed along, given the
+// time range and patterns in opts.
+func (opts FetchLogsOpts) Matches(line ContainerLogLine) bool {
+	if line.Event != ContainerLogLineEvent_LogLine {
+		return true
+	}
+
+	if !line.MissingTimestamp {
+		if !opts.Since.IsZero() && line.Timestamp.Before(opts.Since) {
+			return false
+		}
+
+		if !opts.Until.IsZero() && line.Timestamp.After(opts.Until) {
+			return false
+		}
+	}
+
+	for _, re := range opts.Exclude {
+		if re.Match(line.LogLine) {
+			return false
+		}
+	}
+
+	if len(opts.Include) == 0 {
+		return true
+	}
+
+	for _, re := range opts.Include {
+		if re.Match(line.LogLine) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Filter wraps callback so it only observes lines that match opts.
+func (opts FetchLogsOpts) Filter(callback func(ContainerLogLine)) func(ContainerLogLine) {
+	if opts.Since.IsZero() && opts.Until.IsZero() && len(opts.Include) == 0 && len(opts.Exclude) == 0 {
+		return callback
+	}
+
+	return func(line ContainerLogLine) {
+		if opts.Matches(line) {
+			callback(line)
+		}
+	}
+}
+
+type ServerLogsOpts struct {
+	Observe ObserveOpts
+	Fetch   FetchLogsOpts
+}
+
+// ServerLogLine is a log line, attributed to the server and container which
+// emitted it.
+type ServerLogLine struct {
+	ContainerLogLine
+
+	Server    Deployable
+	Container *runtimepb.ContainerReference
+}
+
+// LogMerger merges log lines from multiple containers into a single stream,
+// ordered by timestamp.
+//
+// Lines from different containers don't arrive in order, so they're held for
+// a short window before being emitted.
+type LogMerger struct {
+	emit   func(ServerLogLine)
+	window time.Duration
+
+	mu      sync.Mutex
+	pending []pendingLogLine
+	seq     int64
+}
+
+type pendingLogLine struct {
+	line    ServerLogLine
+	arrived time.Time
+	seq     int64
+}
+
+func NewLogMerger(window time.Duration, emit func(ServerLogLine)) *LogMerger {
+	return &LogMerger{emit: emit, window: window}
+}
+
+func (m *LogMerger) Add(line ServerLogLine) {
+	if line.Event == ContainerLogLineEvent_Connected {
+		return
+	}
+
+	if line.MissingTimestamp || line.Timestamp.IsZero() {
+		line.Timestamp = time.Now()
+	}
+
+	// Callers may reuse their buffers once Add returns.
+	line.LogLine = bytes.Clone(line.LogLine)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.seq++
+	m.pending = append(m.pending, pendingLogLine{line: line, arrived: time.Now(), seq: m.seq})
+}
+
+// Run periodically emits buffered lines, until the context is done; at which
+// point all remaining lines are emitted.
+func (m *LogMerger) Run(ctx context.Context) {
+	t := time.NewTicker(m.window / 2)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			m.Flush()
+			return
+
+		case now := <-t.C:
+			m.flushBefore(now.Add(-m.window))
+		}
+	}
+}
+
+// Flush emits all buffered lines.
+func (m *LogMerger) Flush() {
+	m.flushBefore(time.Time{})
+}
+
+// flushBefore emits lines that arrived before `cutoff`; or all lines if the
+// cutoff is zero.
+func (m *LogMerger) flushBefore(cutoff time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var ready, rest []pendingLogLine
+	for _, p := range m.pending {
+		if cutoff.IsZero() || p.arrived.Before(cutoff) {
+			ready = append(ready, p)
+		} else {
+			rest = append(rest, p)
+		}
+	}
+
+	m.pending = rest
+
+	sort.SliceStable(ready, func(i, j int) bool {
+		if !ready[i].line.Timestamp.Equal(ready[j].line.Timestamp) {
+			return ready[i].line.Timestamp.Before(ready[j].line.Timestamp)
+		}
+		return ready[i].seq < ready[j].seq
+	})
+
+	for _, p := range ready {
+		m.emit(p.line)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package runtime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLogMerger(t *testing.T) {
+	var got []string
+	merger := NewLogMerger(time.Second, func(line ServerLogLine) {
+		got = append(got, string(line.LogLine))
+	})
+
+	base := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	line := func(offset time.Duration, text []byte) ServerLogLine {
+		return ServerLogLine{ContainerLogLine: ContainerLogLine{Event: ContainerLogLineEvent_LogLine, Timestamp: base.Add(offset), LogLine: text}}
+	}
+
+	buf := []byte("second")
+	merger.Add(line(2*time.Second, buf))
+	copy(buf, "reused") // Callers may reuse their buffers once lines are added.
+	merger.Add(line(time.Second, []byte("first")))
+	merger.Add(line(3*time.Second, []byte("third")))
+	merger.Flush()
+
+	if d := cmp.Diff([]string{"first", "second", "third"}, got); d != "" {
+		t.Errorf("mismatch (-want +got):\n%s", d)
+	}
+}
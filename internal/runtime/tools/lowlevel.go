@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"io/fs"
+	"strings"
 
 	"github.com/moby/buildkit/client/llb"
 	"golang.org/x/exp/slices"
@@ -24,6 +25,7 @@ import (
 	"namespacelabs.dev/foundation/internal/runtime/rtypes"
 	"namespacelabs.dev/foundation/schema"
 	"namespacelabs.dev/foundation/std/tasks"
+	"namespacelabs.dev/foundation/std/tasks/actiontracing"
 )
 
 type LowLevelInvokeOptions struct {
@@ -120,6 +122,17 @@ func makeState(c *buildkit.GatewayClient, pkg schema.PackageName, image compute.
 			runOpts = append(runOpts, llb.AddEnv(entry.Name, env.Value))
 		}
 
+		// The trace context is passed as optional secrets: their values are
+		// not part of the definition, so invocations remain cacheable.
+		traceValues := map[string][]byte{}
+		for name, value := range actiontracing.EnvironMap(ctx) {
+			traceValues[traceSecretID(name)] = []byte(value)
+		}
+
+		for _, name := range actiontracing.PropagatedEnv {
+			runOpts = append(runOpts, llb.AddSecret(name, llb.SecretAsEnv(true), llb.SecretID(traceSecretID(name)), llb.SecretOptional))
+		}
+
 		run := base.Run(runOpts...)
 
 		requestBytes, err := proto.Marshal(req)
@@ -131,10 +144,14 @@ func makeState(c *buildkit.GatewayClient, pkg schema.PackageName, image compute.
 
 		run.AddMount("/request", requestState, llb.Readonly)
 		out := run.AddMount("/out", llb.Scratch())
-		return &buildkit.Input{State: out, Secrets: secrets}, nil
+		return &buildkit.Input{State: out, Secrets: secrets, SecretValues: traceValues}, nil
 	})
 }
 
+func traceSecretID(name string) string {
+	return "ns.tracing." + strings.ToLower(name)
+}
+
 func EnsureCached(image compute.Computable[oci.Image]) compute.Computable[oci.Image] {
 	return compute.Transform("ensure-cached", image, oci.EnsureCached)
 }
@@ -0,0 +1,151 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package staticfiles
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/schema"
+)
+
+const DefaultNginxImage = "nginx:1.21.5-alpine"
+
+// Config is a validated static files server configuration. It determines
+// both the nginx configuration of production images, and how files are
+// served during development, so that both behave alike.
+type Config struct {
+	Dir         string
+	Port        int32
+	BasePath    string // Either empty, or starts with a slash and doesn't end with one.
+	SPAFallback bool
+	// Sorted by decreasing prefix length, so the first match is the longest.
+	CacheControl []CacheRule
+	Gzip, Brotli bool
+	Headers      []Header // Sorted by name.
+	ErrorPages   map[int]string
+	Image        string
+}
+
+type CacheRule struct {
+	PathPrefix string
+	Value      string
+}
+
+type Header struct {
+	Name, Value string
+}
+
+func Parse(src *schema.ImageBuildPlan_StaticFilesServer) (*Config, error) {
+	c := &Config{
+		Dir:         path.Clean("/" + src.Dir),
+		Port:        src.Port,
+		SPAFallback: !src.DisableSpaFallback,
+		ErrorPages:  map[int]string{},
+		Image:       src.Image,
+	}
+
+	if src.BasePath != "" {
+		if !isSafePath(src.BasePath) {
+			return nil, fnerrors.BadInputError("static_files_server: invalid base_path %q", src.BasePath)
+		}
+
+		if c.BasePath = path.Clean("/" + src.BasePath); c.BasePath == "/" {
+			c.BasePath = ""
+		}
+	}
+
+	for _, rule := range src.CacheControl {
+		if !isSafePath(rule.PathPrefix) || !isSafeValue(rule.Value) {
+			return nil, fnerrors.BadInputError("static_files_server: invalid cache_control for %q", rule.PathPrefix)
+		}
+
+		prefix := rule.PathPrefix
+		if !strings.HasPrefix(prefix, "/") {
+			prefix = "/" + prefix
+		}
+
+		c.CacheControl = append(c.CacheControl, CacheRule{PathPrefix: prefix, Value: rule.Value})
+	}
+
+	sort.SliceStable(c.CacheControl, func(i, j int) bool {
+		return len(c.CacheControl[i].PathPrefix) > len(c.CacheControl[j].PathPrefix)
+	})
+
+	for _, enc := range src.Precompressed {
+		switch enc {
+		case "gzip":
+			c.Gzip = true
+		case "br":
+			c.Brotli = true
+		default:
+			return nil, fnerrors.BadInputError("static_files_server: unsupported precompressed encoding %q, expected one of \"gzip\" or \"br\"", enc)
+		}
+	}
+
+	if c.Brotli && c.Image == "" {
+		return nil, fnerrors.BadInputError("static_files_server: serving precompressed brotli requires an nginx image with ngx_brotli, set `image`")
+	}
+
+	for name, value := range src.Headers {
+		if name == "" || strings.ContainsAny(name, " :\"$;{}\n\r") || !isSafeValue(value) {
+			return nil, fnerrors.BadInputError("static_files_server: invalid header %q", name)
+		}
+
+		c.Headers = append(c.Headers, Header{Name: name, Value: value})
+	}
+
+	sort.Slice(c.Headers, func(i, j int) bool {
+		return c.Headers[i].Name < c.Headers[j].Name
+	})
+
+	for code, page := range src.ErrorPages {
+		if code < 300 || code > 599 || !isSafePath(page) {
+			return nil, fnerrors.BadInputError("static_files_server: invalid error page %d: %q", code, page)
+		}
+
+		c.ErrorPages[int(code)] = path.Clean("/" + page)
+	}
+
+	if c.Image == "" {
+		c.Image = DefaultNginxImage
+	}
+
+	return c, nil
+}
+
+// CacheControlFor returns the Cache-Control value for a path, relative to the
+// base path; or an empty string if no rule applies.
+func (c *Config) CacheControlFor(p string) string {
+	for _, rule := range c.CacheControl {
+		if strings.HasPrefix(p, rule.PathPrefix) {
+			return rule.Value
+		}
+	}
+
+	return ""
+}
+
+// Paths end up in nginx locations and regular expressions.
+func isSafePath(p string) bool {
+	for _, r := range p {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune("/-_.~", r):
+		default:
+			return false
+		}
+	}
+
+	return !strings.Contains(p, "..")
+}
+
+// Values end up in double-quoted nginx strings, where variables are
+// interpolated.
+func isSafeValue(v string) bool {
+	return !strings.ContainsAny(v, "$\n\r")
+}
@@ -0,0 +1,182 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package staticfiles
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type handler struct {
+	config  *Config
+	fsys    fs.FS
+	etag    string
+	modTime time.Time
+}
+
+// NewHandler returns a handler which serves the files under the configured
+// directory of fsys the way the nginx configuration would. Files are read as
+// they're requested, so fsys should be cheap to read from, e.g. a directory.
+// If set, etag identifies the contents of fsys, e.g. with an image digest.
+func NewHandler(config *Config, fsys fs.FS, etag string, modTime time.Time) http.Handler {
+	return &handler{config: config, fsys: fsys, etag: etag, modTime: modTime}
+}
+
+func (h *handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	p := req.URL.Path
+
+	if base := h.config.BasePath; base != "" {
+		if p == base {
+			http.Redirect(rw, req, base+"/", http.StatusMovedPermanently)
+			return
+		}
+
+		if !strings.HasPrefix(p, base+"/") {
+			h.serveError(rw, req, http.StatusNotFound)
+			return
+		}
+
+		p = strings.TrimPrefix(p, base)
+	}
+
+	p = path.Clean(p)
+
+	for _, hdr := range h.config.Headers {
+		rw.Header().Set(hdr.Name, hdr.Value)
+	}
+
+	if cc := h.config.CacheControlFor(p); cc != "" {
+		rw.Header().Set("Cache-Control", cc)
+	}
+
+	name, ok := h.lookup(p)
+	if !ok {
+		if !h.config.SPAFallback {
+			h.serveError(rw, req, http.StatusNotFound)
+			return
+		}
+
+		if name, ok = h.lookup("/index.html"); !ok {
+			h.serveError(rw, req, http.StatusNotFound)
+			return
+		}
+	}
+
+	h.serveFile(rw, req, name)
+}
+
+// lookup resolves p into a file, or a directory's index.html.
+func (h *handler) lookup(p string) (string, bool) {
+	if h.isFile(p) {
+		return p, true
+	}
+
+	index := path.Join(p, "index.html")
+	if h.isFile(index) {
+		return index, true
+	}
+
+	return "", false
+}
+
+// name returns the name of p, relative to config.Dir, in fsys.
+func (h *handler) name(p string) string {
+	return strings.TrimPrefix(path.Join(h.config.Dir, p), "/")
+}
+
+func (h *handler) isFile(p string) bool {
+	fi, err := fs.Stat(h.fsys, h.name(p))
+	return err == nil && fi.Mode().IsRegular()
+}
+
+func (h *handler) open(p string) (io.ReadSeeker, func(), error) {
+	f, err := h.fsys.Open(h.name(p))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if rs, ok := f.(io.ReadSeeker); ok {
+		return rs, func() { _ = f.Close() }, nil
+	}
+
+	defer f.Close()
+
+	contents, err := io.ReadAll(f)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return bytes.NewReader(contents), func() {}, nil
+}
+
+func (h *handler) serveFile(rw http.ResponseWriter, req *http.Request, name string) {
+	served, etag := name, h.etag
+
+	if h.config.Gzip || h.config.Brotli {
+		rw.Header().Add("Vary", "Accept-Encoding")
+
+		accepted := req.Header.Get("Accept-Encoding")
+		for _, enc := range []struct {
+			enabled           bool
+			encoding, postfix string
+		}{
+			{h.config.Brotli, "br", ".br"},
+			{h.config.Gzip, "gzip", ".gz"},
+		} {
+			if !enc.enabled || !strings.Contains(accepted, enc.encoding) {
+				continue
+			}
+
+			if h.isFile(name + enc.postfix) {
+				if ct := mime.TypeByExtension(path.Ext(name)); ct != "" {
+					rw.Header().Set("Content-Type", ct)
+				}
+				rw.Header().Set("Content-Encoding", enc.encoding)
+				served = name + enc.postfix
+				// Each encoding is a different representation.
+				if etag != "" {
+					etag += "-" + enc.encoding
+				}
+				break
+			}
+		}
+	}
+
+	contents, done, err := h.open(served)
+	if err != nil {
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	defer done()
+
+	// ServeContent only matches quoted entity tags.
+	if etag != "" {
+		rw.Header().Set("ETag", strconv.Quote(etag))
+	}
+
+	http.ServeContent(rw, req, name, h.modTime, contents)
+}
+
+func (h *handler) serveError(rw http.ResponseWriter, req *http.Request, code int) {
+	if page, ok := h.config.ErrorPages[code]; ok && h.isFile(page) {
+		if contents, err := fs.ReadFile(h.fsys, h.name(page)); err == nil {
+			if ct := mime.TypeByExtension(path.Ext(page)); ct != "" {
+				rw.Header().Set("Content-Type", ct)
+			}
+			rw.WriteHeader(code)
+			_, _ = rw.Write(contents)
+			return
+		}
+	}
+
+	http.Error(rw, http.StatusText(code), code)
+}
@@ -0,0 +1,95 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package staticfiles
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// NginxConf returns the contents of nginx's default.conf.
+func (c *Config) NginxConf() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "server {\n")
+	fmt.Fprintf(&b, "\tlisten %d;\n", c.Port)
+	fmt.Fprintf(&b, "\tserver_name localhost;\n")
+	fmt.Fprintf(&b, "\troot %s;\n", c.Dir)
+
+	if c.Gzip {
+		fmt.Fprintf(&b, "\tgzip_static on;\n")
+		fmt.Fprintf(&b, "\tgzip_vary on;\n")
+	}
+
+	if c.Brotli {
+		fmt.Fprintf(&b, "\tbrotli_static on;\n")
+	}
+
+	var codes []int
+	for code := range c.ErrorPages {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+
+	for _, code := range codes {
+		fmt.Fprintf(&b, "\terror_page %d %s;\n", code, c.BasePath+c.ErrorPages[code])
+	}
+
+	if _, ok := c.ErrorPages[500]; !ok {
+		fmt.Fprintf(&b, "\n\terror_page 500 502 503 504 /50x.html;\n")
+		fmt.Fprintf(&b, "\tlocation = /50x.html {\n\t\troot /usr/share/nginx/html;\n\t}\n")
+	}
+
+	if c.BasePath != "" {
+		fmt.Fprintf(&b, "\n\tlocation / {\n\t\treturn 404;\n\t}\n")
+		fmt.Fprintf(&b, "\n\tlocation = %s {\n\t\treturn 301 %s/;\n\t}\n", c.BasePath, c.BasePath)
+	}
+
+	// Every location repeats the headers, as nginx doesn't inherit add_header
+	// into locations which have their own.
+	c.writeLocation(&b, "/", c.CacheControlFor("/"))
+
+	for k := len(c.CacheControl) - 1; k >= 0; k-- {
+		if rule := c.CacheControl[k]; rule.PathPrefix != "/" {
+			c.writeLocation(&b, rule.PathPrefix, rule.Value)
+		}
+	}
+
+	fmt.Fprintf(&b, "}\n")
+
+	return b.String()
+}
+
+func (c *Config) writeLocation(b *strings.Builder, prefix, cacheControl string) {
+	fmt.Fprintf(b, "\n\tlocation %s {\n", c.BasePath+prefix)
+
+	if c.BasePath != "" {
+		fmt.Fprintf(b, "\t\trewrite ^%s(/.*)$ $1 break;\n", regexp.QuoteMeta(c.BasePath))
+	}
+
+	for _, h := range c.Headers {
+		fmt.Fprintf(b, "\t\tadd_header %s %s always;\n", h.Name, quote(h.Value))
+	}
+
+	if cacheControl != "" {
+		fmt.Fprintf(b, "\t\tadd_header Cache-Control %s always;\n", quote(cacheControl))
+	}
+
+	// Directories are served their index.html directly, rather than through
+	// the index module, which would redirect outside of the base path.
+	fallback := "=404"
+	if c.SPAFallback {
+		fallback = c.BasePath + "/index.html"
+	}
+
+	fmt.Fprintf(b, "\t\ttry_files $uri $uri/index.html %s;\n", fallback)
+	fmt.Fprintf(b, "\t}\n")
+}
+
+func quote(v string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(v) + `"`
+}
@@ -0,0 +1,108 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package staticfiles
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"namespacelabs.dev/foundation/internal/fnfs/memfs"
+	"namespacelabs.dev/foundation/schema"
+)
+
+func TestServing(t *testing.T) {
+	config, err := Parse(&schema.ImageBuildPlan_StaticFilesServer{
+		Dir:           "/app",
+		Port:          8080,
+		BasePath:      "/web/",
+		CacheControl:  []*schema.ImageBuildPlan_StaticFilesServer_CacheControl{{PathPrefix: "/assets/", Value: "max-age=31536000, immutable"}},
+		Precompressed: []string{"gzip"},
+		Headers:       map[string]string{"Strict-Transport-Security": "max-age=63072000"},
+		ErrorPages:    map[int32]string{404: "/404.html"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fsys memfs.FS
+	fsys.Add("app/index.html", []byte("index"))
+	fsys.Add("app/404.html", []byte("not found"))
+	fsys.Add("app/assets/main.js", []byte("js"))
+	fsys.Add("app/assets/main.js.gz", []byte("gzipped js"))
+	fsys.Add("other/file", []byte("not served"))
+
+	h := NewHandler(config, &fsys, "sha256:abc", time.Unix(1, 0))
+
+	get := func(path, acceptEncoding, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, test := range []struct {
+		path, acceptEncoding, ifNoneMatch string
+		code                              int
+		body                              string
+		headers                           map[string]string
+	}{
+		{path: "/web", code: http.StatusMovedPermanently},
+		{path: "/web/", code: http.StatusOK, body: "index", headers: map[string]string{"Strict-Transport-Security": "max-age=63072000", "ETag": `"sha256:abc"`}},
+		{path: "/web/", ifNoneMatch: `"sha256:abc"`, code: http.StatusNotModified},
+		{path: "/web/", ifNoneMatch: `"sha256:def"`, code: http.StatusOK, body: "index"},
+		{path: "/web/some/route", code: http.StatusOK, body: "index"},
+		{path: "/web/assets/main.js", code: http.StatusOK, body: "js", headers: map[string]string{"Cache-Control": "max-age=31536000, immutable"}},
+		{path: "/web/assets/main.js", acceptEncoding: "gzip, br", code: http.StatusOK, body: "gzipped js", headers: map[string]string{"Content-Encoding": "gzip", "Content-Type": "text/javascript; charset=utf-8", "ETag": `"sha256:abc-gzip"`}},
+		{path: "/web/assets/main.js", acceptEncoding: "gzip", ifNoneMatch: `"sha256:abc"`, code: http.StatusOK, body: "gzipped js"},
+		{path: "/other/file", code: http.StatusNotFound, body: "not found"},
+	} {
+		rec := get(test.path, test.acceptEncoding, test.ifNoneMatch)
+		if rec.Code != test.code {
+			t.Errorf("%s: expected %d, got %d", test.path, test.code, rec.Code)
+		}
+		if test.body != "" && rec.Body.String() != test.body {
+			t.Errorf("%s: expected %q, got %q", test.path, test.body, rec.Body.String())
+		}
+		for k, v := range test.headers {
+			if got := rec.Header().Get(k); got != v {
+				t.Errorf("%s: expected %s: %q, got %q", test.path, k, v, got)
+			}
+		}
+	}
+
+	conf := config.NginxConf()
+	for _, expected := range []string{
+		"listen 8080;",
+		"gzip_static on;",
+		"error_page 404 /web/404.html;",
+		"location /web/assets/ {",
+		`add_header Cache-Control "max-age=31536000, immutable" always;`,
+		"rewrite ^/web(/.*)$ $1 break;",
+		"try_files $uri $uri/index.html /web/index.html;",
+	} {
+		if !strings.Contains(conf, expected) {
+			t.Errorf("expected nginx configuration to include %q, got:\n%s", expected, conf)
+		}
+	}
+}
+
+func TestParseRejectsUnsafeValues(t *testing.T) {
+	if _, err := Parse(&schema.ImageBuildPlan_StaticFilesServer{Headers: map[string]string{"X-Foo": "$host"}}); err == nil {
+		t.Error("expected header values with variables to be rejected")
+	}
+
+	if _, err := Parse(&schema.ImageBuildPlan_StaticFilesServer{Precompressed: []string{"br"}}); err == nil {
+		t.Error("expected brotli to require an image")
+	}
+}
@@ -127,6 +127,7 @@ func (s *Run) Output(ctx context.Context, execErr error) error {
 
 func nsErrorToStatus(err error) *status.Status {
 	st, _ := status.FromError(err)
+	st = fnerrors.WithCodeDetail(st, err)
 
 	// Find the deepest ActionError to provide the action trace for the root cause.
 	var actionErr *fnerrors.ActionError
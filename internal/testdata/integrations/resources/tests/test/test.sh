@@ -0,0 +1,9 @@
+#!/bin/bash
+
+RESOURCE="namespacelabs.dev/foundation/internal/testdata/integrations/resources/instances:test1"
+
+if ! grep -q "\"$RESOURCE\"" /namespace/config/resources.json; then
+    echo "Expected $RESOURCE to be available to the test, got:"
+    cat /namespace/config/resources.json
+    exit 1
+fi
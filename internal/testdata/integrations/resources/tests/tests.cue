@@ -0,0 +1,10 @@
+tests: {
+	// Declares the same resource instance that the server under test uses.
+	resources: {
+		serversUnderTest: ["namespacelabs.dev/foundation/internal/testdata/integrations/dockerfile/complex"]
+		integration: shellscript: "test/test.sh"
+		resources: [
+			"namespacelabs.dev/foundation/internal/testdata/integrations/resources/instances:test1",
+		]
+	}
+}
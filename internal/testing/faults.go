@@ -0,0 +1,150 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package testing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+
+	"namespacelabs.dev/foundation/internal/console"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/internal/runtime"
+	"namespacelabs.dev/foundation/internal/testing/testboot"
+	"namespacelabs.dev/foundation/schema"
+)
+
+const (
+	revertFaultsTimeout = 30 * time.Second
+
+	// The driver waits for up to a minute for the runner to connect, when the
+	// first fault is requested.
+	minFaultsBackoff = time.Second
+	maxFaultsBackoff = 15 * time.Second
+)
+
+// serveFaults injects the faults which the test driver requests into the
+// servers of the stack, through the runtime, until the context is canceled.
+// Faults which are still in place are then reverted.
+func serveFaults(ctx context.Context, cluster runtime.ClusterNamespace, driver runtime.Deployable, stack *schema.Stack) {
+	reverts := map[int64]func(context.Context) error{}
+
+	defer func() {
+		revertCtx, done := context.WithTimeout(context.WithoutCancel(ctx), revertFaultsTimeout)
+		defer done()
+
+		for id, revert := range reverts {
+			if err := revert(revertCtx); err != nil {
+				fmt.Fprintf(console.Warnings(ctx), "Failed to revert fault #%d: %v\n", id, err)
+			}
+		}
+	}()
+
+	// Most drivers never request a fault, and shell-script drivers don't
+	// listen at all; so back off, up to maxFaultsBackoff, between attempts.
+	backoff := minFaultsBackoff
+	for {
+		// The driver may not be listening yet; and reconnections are expected
+		// if it drops a connection it considers broken.
+		conn, err := cluster.DialServer(ctx, driver, &schema.Endpoint_Port{ContainerPort: testboot.FaultsPort})
+		if err == nil {
+			var served bool
+			served, err = handleFaults(ctx, conn, cluster, stack, reverts)
+			if served {
+				backoff = minFaultsBackoff
+			}
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if backoff == minFaultsBackoff {
+			fmt.Fprintf(console.Debug(ctx), "testing: fault channel: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff = min(2*backoff, maxFaultsBackoff)
+	}
+}
+
+// handleFaults serves fault requests until the connection is closed, and
+// returns whether any request was served.
+func handleFaults(ctx context.Context, conn net.Conn, cluster runtime.ClusterNamespace, stack *schema.Stack, reverts map[int64]func(context.Context) error) (bool, error) {
+	defer conn.Close()
+
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	dec := json.NewDecoder(conn)
+	enc := json.NewEncoder(conn)
+
+	for served := false; ; served = true {
+		var req testboot.FaultRequest
+		if err := dec.Decode(&req); err != nil {
+			return served, err
+		}
+
+		resp := testboot.FaultResponse{ID: req.ID}
+
+		revert, err := injectFault(ctx, cluster, stack, req, reverts)
+		if err != nil {
+			resp.Error = err.Error()
+		} else if revert != nil {
+			reverts[req.ID] = revert
+			resp.Revertible = true
+		}
+
+		if err := enc.Encode(resp); err != nil {
+			return served, err
+		}
+	}
+}
+
+func injectFault(ctx context.Context, cluster runtime.ClusterNamespace, stack *schema.Stack, req testboot.FaultRequest, reverts map[int64]func(context.Context) error) (func(context.Context) error, error) {
+	if req.Kind == testboot.FaultRevert {
+		revert, ok := reverts[req.Revert]
+		if !ok {
+			return nil, fnerrors.BadInputError("no fault #%d to revert", req.Revert)
+		}
+
+		if err := revert(ctx); err != nil {
+			return nil, err
+		}
+
+		delete(reverts, req.Revert)
+		return nil, nil
+	}
+
+	server, err := stackServer(stack, req.Server)
+	if err != nil {
+		return nil, err
+	}
+
+	fault := runtime.Fault{Kind: runtime.FaultKind(req.Kind), AdminPort: req.AdminPort}
+	if req.Source != "" {
+		if fault.Source, err = stackServer(stack, req.Source); err != nil {
+			return nil, err
+		}
+	}
+
+	return cluster.InjectFault(ctx, server, fault)
+}
+
+func stackServer(stack *schema.Stack, pkg string) (runtime.Deployable, error) {
+	entry := stack.GetServer(schema.PackageName(pkg))
+	if entry == nil {
+		return nil, fnerrors.BadInputError("%s: not a server in the stack", pkg)
+	}
+
+	return entry.Server, nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package testing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"namespacelabs.dev/foundation/internal/runtime"
+	"namespacelabs.dev/foundation/internal/testing/testboot"
+	"namespacelabs.dev/foundation/schema"
+)
+
+type fakeCluster struct {
+	runtime.ClusterNamespace // Unimplemented methods panic.
+
+	injected []runtime.Fault
+	reverted int
+}
+
+func (f *fakeCluster) InjectFault(ctx context.Context, server runtime.Deployable, fault runtime.Fault) (func(context.Context) error, error) {
+	f.injected = append(f.injected, fault)
+
+	if fault.Kind != runtime.FaultPause {
+		return nil, nil
+	}
+
+	return func(context.Context) error {
+		f.reverted++
+		return nil
+	}, nil
+}
+
+func TestHandleFaults(t *testing.T) {
+	stack := &schema.Stack{Entry: []*schema.Stack_Entry{
+		{Server: &schema.Server{Id: "a", PackageName: "example.com/a"}},
+		{Server: &schema.Server{Id: "b", PackageName: "example.com/b"}},
+	}}
+
+	cluster := &fakeCluster{}
+	reverts := map[int64]func(context.Context) error{}
+
+	runner, driver := net.Pipe()
+	defer driver.Close()
+
+	type result struct {
+		served bool
+		err    error
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		served, err := handleFaults(context.Background(), runner, cluster, stack, reverts)
+		done <- result{served, err}
+	}()
+
+	enc := json.NewEncoder(driver)
+	dec := json.NewDecoder(driver)
+
+	for _, tc := range []struct {
+		req  testboot.FaultRequest
+		want testboot.FaultResponse
+	}{
+		{
+			testboot.FaultRequest{ID: 1, Kind: testboot.FaultRestart, Server: "example.com/a"},
+			testboot.FaultResponse{ID: 1},
+		},
+		{
+			testboot.FaultRequest{ID: 2, Kind: testboot.FaultPause, Server: "example.com/b"},
+			testboot.FaultResponse{ID: 2, Revertible: true},
+		},
+		{
+			testboot.FaultRequest{ID: 3, Kind: testboot.FaultBlockTraffic, Server: "example.com/a", Source: "example.com/c"},
+			testboot.FaultResponse{ID: 3, Error: "example.com/c: not a server in the stack"},
+		},
+		{
+			testboot.FaultRequest{ID: 4, Kind: testboot.FaultRevert, Revert: 2},
+			testboot.FaultResponse{ID: 4},
+		},
+		{
+			testboot.FaultRequest{ID: 5, Kind: testboot.FaultRevert, Revert: 2},
+			testboot.FaultResponse{ID: 5, Error: "no fault #2 to revert"},
+		},
+	} {
+		if err := enc.Encode(tc.req); err != nil {
+			t.Fatal(err)
+		}
+
+		var got testboot.FaultResponse
+		if err := dec.Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+
+		if got != tc.want {
+			t.Errorf("request #%d: got %+v, want %+v", tc.req.ID, got, tc.want)
+		}
+	}
+
+	driver.Close()
+
+	res := <-done
+	if !res.served || res.err == nil {
+		t.Errorf("expected the channel to fail after serving requests, got served=%v err=%v", res.served, res.err)
+	}
+
+	if len(cluster.injected) != 2 {
+		t.Fatalf("expected 2 faults to be injected, got %d", len(cluster.injected))
+	}
+
+	if got := cluster.injected[0]; got.Kind != runtime.FaultRestart || got.Source != nil {
+		t.Errorf("unexpected fault: %+v", got)
+	}
+
+	if cluster.reverted != 1 {
+		t.Errorf("expected the pause to be reverted once, got %d", cluster.reverted)
+	}
+
+	if len(reverts) != 0 {
+		t.Errorf("expected no faults left to revert, got %d", len(reverts))
+	}
+}
+
+func TestHandleFaultsNothingServed(t *testing.T) {
+	runner, driver := net.Pipe()
+	driver.Close()
+
+	served, err := handleFaults(context.Background(), runner, &fakeCluster{}, &schema.Stack{}, nil)
+	if served || !errors.Is(err, io.EOF) {
+		t.Errorf("got served=%v err=%v, want served=false err=EOF", served, err)
+	}
+}
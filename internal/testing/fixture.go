@@ -79,6 +79,13 @@ func PrepareTest(ctx context.Context, pl *parsing.PackageLoader, env cfg.Context
 	}
 
 	pack := &schema.ResourcePack{}
+	if testPack := testDef.ResourcePack; testPack != nil {
+		// Resource references resolve to the same instances the servers
+		// under test use; both are deployed into the test's environment.
+		pack.ResourceRef = append(pack.ResourceRef, testPack.ResourceRef...)
+		pack.ResourceInstance = append(pack.ResourceInstance, testPack.ResourceInstance...)
+	}
+
 	if err := parsing.AddServersAsResourcesToPack(ctx, pl, testRef, stack.Focus.PackageNames(), pack); err != nil {
 		return nil, err
 	}
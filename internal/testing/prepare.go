@@ -9,8 +9,8 @@ import (
 	"fmt"
 
 	"namespacelabs.dev/foundation/framework/kubernetes/kubenaming"
-	"namespacelabs.dev/foundation/internal/artifacts/oci"
 	"namespacelabs.dev/foundation/internal/build/assets"
+	"namespacelabs.dev/foundation/internal/build/attest"
 	"namespacelabs.dev/foundation/internal/build/binary"
 	"namespacelabs.dev/foundation/internal/build/multiplatform"
 	"namespacelabs.dev/foundation/internal/compute"
@@ -100,7 +100,7 @@ func (driver *testDriver) Compute(ctx context.Context, r compute.Resolved) (depl
 
 	testBinTag := registry.AllocateName(driver.Location.String(), "")
 
-	driverImage, err := compute.GetValue(ctx, oci.PublishResolvable(testBinTag, bin, testBin.Plan))
+	driverImage, err := compute.GetValue(ctx, attest.Publish(testBinTag, bin, testBin.Plan))
 	if err != nil {
 		return deploy.PreparedDeployable{}, err
 	}
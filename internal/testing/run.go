@@ -165,6 +165,12 @@ func (test *testRun) compute(ctx context.Context, r compute.Resolved) (*storage.
 			return nil
 		})
 
+		ex.Go(func(ctx context.Context) error {
+			// Runs until the test is done.
+			serveFaults(ctx, cluster, d.Template, test.Stack)
+			return nil
+		})
+
 		waitErr = ex.Wait()
 	}
 
@@ -0,0 +1,45 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package testboot
+
+// Test drivers don't inject faults themselves: they accept a connection from
+// the test runner on FaultsPort, which is only bound to the loopback interface
+// and reached through the runtime's port forwarding. Requests and responses
+// are exchanged over it as newline-delimited JSON, one at a time, and the
+// runner injects the requested faults through the runtime.
+const FaultsPort = 15400
+
+// Match the runtime's fault kinds.
+const (
+	FaultRestart      = "restart"
+	FaultKill         = "kill"
+	FaultPause        = "pause"
+	FaultBlockTraffic = "block-traffic"
+	FaultLameduck     = "lameduck"
+
+	// Reverts a previously injected fault.
+	FaultRevert = "revert"
+)
+
+type FaultRequest struct {
+	ID   int64  `json:"id"`
+	Kind string `json:"kind"`
+	// The package name of the target server.
+	Server string `json:"server,omitempty"`
+	// The package name of the source server, for faults which require one.
+	Source string `json:"source,omitempty"`
+	// The port of the target server's internal HTTP endpoint, for faults
+	// which require one.
+	AdminPort int32 `json:"admin_port,omitempty"`
+	// The ID of the request whose fault is reverted, if Kind is FaultRevert.
+	Revert int64 `json:"revert,omitempty"`
+}
+
+type FaultResponse struct {
+	ID    int64  `json:"id"`
+	Error string `json:"error,omitempty"`
+	// Set if the fault persists until it's reverted.
+	Revertible bool `json:"revertible,omitempty"`
+}
@@ -0,0 +1,16 @@
+resourceClasses: {
+	"Database": {
+		description: "MySQL Database"
+		produces: {
+			type:   "library.database.mysql.DatabaseInstance"
+			source: "./types.proto"
+		}
+	}
+	"Cluster": {
+		description: "MySQL Database Cluster"
+		produces: {
+			type:   "library.database.mysql.ClusterInstance"
+			source: "./types.proto"
+		}
+	}
+}
@@ -0,0 +1,23 @@
+resourceClasses: {
+	"Cluster": {
+		description: "NATS Cluster"
+		produces: {
+			type:   "library.messaging.nats.ClusterInstance"
+			source: "./types.proto"
+		}
+	}
+	"Stream": {
+		description: "NATS JetStream Stream"
+		produces: {
+			type:   "library.messaging.nats.StreamInstance"
+			source: "./types.proto"
+		}
+	}
+	"Consumer": {
+		description: "NATS JetStream Durable Consumer"
+		produces: {
+			type:   "library.messaging.nats.ConsumerInstance"
+			source: "./types.proto"
+		}
+	}
+}
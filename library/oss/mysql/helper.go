@@ -0,0 +1,31 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package mysql
+
+import (
+	"github.com/go-sql-driver/mysql"
+)
+
+type ClusterInstance interface {
+	GetUser() string
+	GetPassword() string
+	GetAddress() string
+}
+
+func DSN(cluster ClusterInstance, db string) string {
+	return Config(cluster, db).FormatDSN()
+}
+
+// Config returns a driver configuration which can be further customized
+// before being formatted as a DSN.
+func Config(cluster ClusterInstance, db string) *mysql.Config {
+	cfg := mysql.NewConfig()
+	cfg.User = cluster.GetUser()
+	cfg.Passwd = cluster.GetPassword()
+	cfg.Net = "tcp"
+	cfg.Addr = cluster.GetAddress()
+	cfg.DBName = db
+	return cfg
+}
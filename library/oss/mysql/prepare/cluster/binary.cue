@@ -0,0 +1,4 @@
+binary: {
+	name: "mysql-prepare-cluster"
+	from: go_package: "."
+}
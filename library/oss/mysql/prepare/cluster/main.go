@@ -0,0 +1,50 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"net"
+
+	"namespacelabs.dev/foundation/framework/resources"
+	"namespacelabs.dev/foundation/framework/resources/provider"
+	mysqlclass "namespacelabs.dev/foundation/library/database/mysql"
+	"namespacelabs.dev/foundation/library/oss/mysql"
+)
+
+const (
+	providerPkg = "namespacelabs.dev/foundation/library/oss/mysql"
+	user        = "root"
+)
+
+func main() {
+	_, p := provider.MustPrepare[*mysql.ClusterIntent]()
+
+	endpoint, err := resources.LookupServerEndpoint(p.Resources, fmt.Sprintf("%s:server", providerPkg), "mysql")
+	if err != nil {
+		log.Fatalf("failed to get MySQL server endpoint: %v", err)
+	}
+
+	password, err := resources.ReadSecret(p.Resources, fmt.Sprintf("%s:password", providerPkg))
+	if err != nil {
+		log.Fatalf("failed to read MySQL password: %v", err)
+	}
+
+	host, port, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		log.Fatalf("invalid MySQL endpoint %q: %v", endpoint, err)
+	}
+
+	instance := &mysqlclass.ClusterInstance{
+		Address:  endpoint,
+		User:     user,
+		Password: string(password),
+		Host:     host,
+		Port:     port,
+	}
+
+	p.EmitResult(instance)
+}
@@ -0,0 +1,4 @@
+binary: {
+	name: "mysql-prepare-clusterinstance"
+	from: go_package: "."
+}
@@ -0,0 +1,4 @@
+binary: {
+	name: "mysql-prepare-database"
+	from: go_package: "."
+}
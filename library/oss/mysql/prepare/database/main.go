@@ -0,0 +1,165 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"regexp"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"namespacelabs.dev/foundation/framework/resources/provider"
+	mysqlclass "namespacelabs.dev/foundation/library/database/mysql"
+	"namespacelabs.dev/foundation/library/oss/mysql"
+)
+
+const (
+	providerPkg = "namespacelabs.dev/foundation/library/oss/mysql"
+	connBackoff = 1500 * time.Millisecond
+	connTimeout = 5 * time.Minute
+
+	// schemaApplyLockTimeout bounds how long any statement in a schema apply waits to acquire a lock
+	// (both metadata and row locks). With this, statements fail fast with ER_LOCK_WAIT_TIMEOUT (1205),
+	// which is not retried, so the apply fails loudly and the deploy can be re-run once any blocker clears.
+	schemaApplyLockTimeout = 10 * time.Second
+)
+
+// Unquoted MySQL identifiers; see https://dev.mysql.com/doc/refman/8.0/en/identifiers.html
+var validDatabaseName = regexp.MustCompile(`^[0-9A-Za-z_$]+$`)
+
+func main() {
+	ctx, p := provider.MustPrepare[*mysql.DatabaseIntent]()
+
+	if err := run(ctx, p); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, p *provider.Provider[*mysql.DatabaseIntent]) error {
+	cluster := &mysqlclass.ClusterInstance{}
+	if err := p.Resources.Unmarshal(fmt.Sprintf("%s:cluster", providerPkg), cluster); err != nil {
+		return fmt.Errorf("unable to read required resource \"cluster\": %w", err)
+	}
+
+	exists, err := ensureDatabase(ctx, cluster, p.Intent.Name)
+	if err != nil {
+		return fmt.Errorf("unable to create database %q: %w", p.Intent.Name, err)
+	}
+
+	instance := &mysqlclass.DatabaseInstance{
+		Name:           p.Intent.Name,
+		Dsn:            mysql.DSN(cluster, p.Intent.Name),
+		User:           cluster.User,
+		Password:       cluster.Password,
+		ClusterAddress: cluster.Address,
+		ClusterHost:    cluster.Host,
+		ClusterPort:    cluster.Port,
+	}
+
+	if !exists || !p.Intent.SkipSchemaInitializationIfExists {
+		if err := applySchema(ctx, cluster, p.Intent); err != nil {
+			return err
+		}
+	}
+
+	p.EmitResult(instance)
+	return nil
+}
+
+func ensureDatabase(ctx context.Context, cluster *mysqlclass.ClusterInstance, name string) (bool, error) {
+	// Database names can't be passed as SQL arguments, so we need to use Sprintf below.
+	if !validDatabaseName.MatchString(name) {
+		return false, fmt.Errorf("invalid database name: %s", name)
+	}
+
+	// Connect without selecting a database, as it may not exist yet.
+	db, err := connect(ctx, mysql.DSN(cluster, ""))
+	if err != nil {
+		return false, err
+	}
+	defer closeDB(db)
+
+	rows, err := db.QueryContext(ctx, "SELECT SCHEMA_NAME FROM INFORMATION_SCHEMA.SCHEMATA WHERE SCHEMA_NAME = ?", name)
+	if err != nil {
+		return false, fmt.Errorf("failed to check for database %q: %w", name, err)
+	}
+	exists := rows.Next()
+	rows.Close()
+
+	if !exists {
+		if _, err := db.ExecContext(ctx, fmt.Sprintf("CREATE DATABASE IF NOT EXISTS `%s`", name)); err != nil {
+			return false, fmt.Errorf("failed to create database %q: %w", name, err)
+		}
+	}
+
+	return exists, nil
+}
+
+func applySchema(ctx context.Context, cluster *mysqlclass.ClusterInstance, intent *mysql.DatabaseIntent) error {
+	if len(intent.Schema) == 0 {
+		return nil
+	}
+
+	cfg := mysql.Config(cluster, intent.Name)
+	// Schema files typically contain more than one statement.
+	cfg.MultiStatements = true
+	cfg.Params = map[string]string{
+		"lock_wait_timeout":        fmt.Sprintf("%d", int(schemaApplyLockTimeout.Seconds())),
+		"innodb_lock_wait_timeout": fmt.Sprintf("%d", int(schemaApplyLockTimeout.Seconds())),
+	}
+
+	db, err := connect(ctx, cfg.FormatDSN())
+	if err != nil {
+		return fmt.Errorf("unable to open connection: %w", err)
+	}
+	defer closeDB(db)
+
+	// Schemas are not retried, not even on deadlocks: DDL statements commit implicitly, so the
+	// statements which preceded a failed one have already been applied, and re-running them would
+	// fail, e.g. on a CREATE TABLE without IF NOT EXISTS.
+	for _, schema := range intent.Schema {
+		if _, err := db.ExecContext(ctx, string(schema.Contents)); err != nil {
+			return fmt.Errorf("unable to apply schema %q: %w", schema.Path, err)
+		}
+	}
+
+	return nil
+}
+
+func connect(ctx context.Context, dsn string) (*sql.DB, error) {
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, connTimeout)
+	defer cancel()
+
+	// Retry until backend is ready.
+	if err := backoff.Retry(func() error {
+		pingCtx, cancel := context.WithTimeout(ctx, connBackoff)
+		defer cancel()
+
+		err := db.PingContext(pingCtx)
+		if err != nil {
+			log.Printf("failed to connect to mysql: %v\n", err)
+		}
+		return err
+	}, backoff.WithContext(backoff.NewConstantBackOff(connBackoff), ctx)); err != nil {
+		closeDB(db)
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func closeDB(db *sql.DB) {
+	if err := db.Close(); err != nil {
+		log.Printf("unable to close database connection: %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+providers: {
+	"namespacelabs.dev/foundation/library/database/mysql:Database": {
+		initializedWith: "namespacelabs.dev/foundation/library/oss/mysql/prepare/database"
+
+		intent: {
+			type:   "library.oss.mysql.DatabaseIntent"
+			source: "./types.proto"
+		}
+
+		inputs: {
+			cluster: {
+				class:   "namespacelabs.dev/foundation/library/database/mysql:Cluster"
+				default: ":colocated"
+			}
+		}
+	}
+
+	"namespacelabs.dev/foundation/library/database/mysql:Cluster": {
+		initializedWith: "namespacelabs.dev/foundation/library/oss/mysql/prepare/cluster"
+
+		intent: {
+			type:   "library.oss.mysql.ClusterIntent"
+			source: "./types.proto"
+		}
+
+		resourcesFrom: "namespacelabs.dev/foundation/library/oss/mysql/prepare/clusterinstance"
+
+		availableClasses: [
+			"namespacelabs.dev/foundation/library/runtime:Server",
+			"namespacelabs.dev/foundation/library/runtime:Secret",
+		]
+
+		availablePackages: [
+			"namespacelabs.dev/foundation/library/oss/mysql/server",
+		]
+	}
+}
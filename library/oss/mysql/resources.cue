@@ -0,0 +1,12 @@
+resources: {
+	// colocated represents a MySQL cluster that can be easily used by multiple users, within a single cluster.
+	colocated: {
+		class:    "namespacelabs.dev/foundation/library/database/mysql:Cluster"
+		provider: "namespacelabs.dev/foundation/library/oss/mysql"
+
+		intent: {
+			server:          "namespacelabs.dev/foundation/library/oss/mysql/server"
+			password_secret: "namespacelabs.dev/foundation/library/oss/mysql/server:password"
+		}
+	}
+}
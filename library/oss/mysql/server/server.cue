@@ -0,0 +1,14 @@
+import "namespacelabs.dev/foundation/library/oss/mysql/templates"
+
+server: templates.#Server
+
+secrets: {
+	"password": {
+		description: "MySQL root password"
+		generate: {
+			uniqueId:        "mysql-password"
+			randomByteCount: 32
+			format:          "FORMAT_BASE32"
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package templates
+
+#Server: {
+	spec: {
+		image:          *"mysql:8.0.36" | string
+		dataVolumeSize: *"10GiB" | string
+		dataVolume: *{
+			id:   "mysql-server-data"
+			size: dataVolumeSize
+		} | {
+			id:   string
+			size: string
+		}
+		passwordSecret: *"namespacelabs.dev/foundation/library/oss/mysql/server:password" | string
+	}
+
+	name: "mysql-server"
+
+	image: spec.image
+
+	// MySQL mounts a persistent volume which requires a stateful deployment (more conservative update strategy).
+	class: "stateful"
+
+	// The data directory must be empty on first start, so it's kept in a subdirectory of the mount.
+	args: ["--datadir=/mysql/data/mysqldata"]
+
+	env: {
+		MYSQL_ROOT_PASSWORD_FILE: "/mysql/secrets/password"
+	}
+
+	services: "mysql": {
+		port: 3306
+		kind: "tcp"
+	}
+
+	mounts: {
+		"/mysql/data": persistent: spec.dataVolume
+
+		"/mysql/secrets": configurable: {
+			contents: {
+				"password": fromSecret: spec.passwordSecret
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package nats
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// StreamConfig translates a stream intent into the configuration that is
+// applied to the cluster. Applying the same configuration more than once is a
+// no-op.
+func StreamConfig(intent *StreamIntent) (jetstream.StreamConfig, error) {
+	if intent.Name == "" {
+		return jetstream.StreamConfig{}, fmt.Errorf("stream name is required")
+	}
+
+	if len(intent.Subjects) == 0 {
+		return jetstream.StreamConfig{}, fmt.Errorf("%s: at least one subject is required", intent.Name)
+	}
+
+	// The provider deploys a single-node server, which can't hold more than
+	// one replica.
+	if intent.Replicas > 1 {
+		return jetstream.StreamConfig{}, fmt.Errorf("%s: %d replicas requested, but the server is single-node", intent.Name, intent.Replicas)
+	}
+
+	config := jetstream.StreamConfig{
+		Name:     intent.Name,
+		Subjects: intent.Subjects,
+		Replicas: 1,
+	}
+
+	switch intent.Retention {
+	case "", "limits":
+		config.Retention = jetstream.LimitsPolicy
+	case "interest":
+		config.Retention = jetstream.InterestPolicy
+	case "workqueue":
+		config.Retention = jetstream.WorkQueuePolicy
+	default:
+		return jetstream.StreamConfig{}, fmt.Errorf("%s: unsupported retention %q", intent.Name, intent.Retention)
+	}
+
+	switch intent.Storage {
+	case "", "file":
+		config.Storage = jetstream.FileStorage
+	case "memory":
+		config.Storage = jetstream.MemoryStorage
+	default:
+		return jetstream.StreamConfig{}, fmt.Errorf("%s: unsupported storage %q", intent.Name, intent.Storage)
+	}
+
+	if intent.MaxAge != "" {
+		d, err := time.ParseDuration(intent.MaxAge)
+		if err != nil {
+			return jetstream.StreamConfig{}, fmt.Errorf("%s: invalid max_age: %w", intent.Name, err)
+		}
+		config.MaxAge = d
+	}
+
+	return config, nil
+}
+
+// ConsumerConfig translates a consumer intent into a durable consumer
+// configuration. Applying the same configuration more than once is a no-op.
+func ConsumerConfig(intent *ConsumerIntent) (jetstream.ConsumerConfig, error) {
+	if intent.Name == "" {
+		return jetstream.ConsumerConfig{}, fmt.Errorf("consumer name is required")
+	}
+
+	config := jetstream.ConsumerConfig{
+		Durable:       intent.Name,
+		FilterSubject: intent.FilterSubject,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		MaxDeliver:    int(intent.MaxDeliver),
+	}
+
+	switch intent.DeliverPolicy {
+	case "", "all":
+		config.DeliverPolicy = jetstream.DeliverAllPolicy
+	case "new":
+		config.DeliverPolicy = jetstream.DeliverNewPolicy
+	case "last":
+		config.DeliverPolicy = jetstream.DeliverLastPolicy
+	default:
+		return jetstream.ConsumerConfig{}, fmt.Errorf("%s: unsupported deliver_policy %q", intent.Name, intent.DeliverPolicy)
+	}
+
+	if intent.AckWait != "" {
+		d, err := time.ParseDuration(intent.AckWait)
+		if err != nil {
+			return jetstream.ConsumerConfig{}, fmt.Errorf("%s: invalid ack_wait: %w", intent.Name, err)
+		}
+		config.AckWait = d
+	}
+
+	return config, nil
+}
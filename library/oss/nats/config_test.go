@@ -0,0 +1,76 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package nats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+func TestStreamConfig(t *testing.T) {
+	config, err := StreamConfig(&StreamIntent{
+		Name:      "orders",
+		Subjects:  []string{"orders.>"},
+		Retention: "workqueue",
+		MaxAge:    "24h",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Retention != jetstream.WorkQueuePolicy {
+		t.Errorf("expected workqueue retention, got %v", config.Retention)
+	}
+
+	if config.Storage != jetstream.FileStorage {
+		t.Errorf("expected file storage, got %v", config.Storage)
+	}
+
+	if config.Replicas != 1 {
+		t.Errorf("expected 1 replica, got %d", config.Replicas)
+	}
+
+	if config.MaxAge != 24*time.Hour {
+		t.Errorf("expected max age of 24h, got %v", config.MaxAge)
+	}
+
+	for _, intent := range []*StreamIntent{
+		{Subjects: []string{"orders.>"}},
+		{Name: "orders"},
+		{Name: "orders", Subjects: []string{"orders.>"}, Retention: "forever"},
+		{Name: "orders", Subjects: []string{"orders.>"}, MaxAge: "1 day"},
+		{Name: "orders", Subjects: []string{"orders.>"}, Replicas: 3},
+	} {
+		if _, err := StreamConfig(intent); err == nil {
+			t.Errorf("expected %v to be rejected", intent)
+		}
+	}
+}
+
+func TestConsumerConfig(t *testing.T) {
+	config, err := ConsumerConfig(&ConsumerIntent{
+		Name:          "billing",
+		FilterSubject: "orders.created",
+		DeliverPolicy: "new",
+		AckWait:       "30s",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Durable != "billing" || config.FilterSubject != "orders.created" {
+		t.Errorf("unexpected consumer config: %+v", config)
+	}
+
+	if config.DeliverPolicy != jetstream.DeliverNewPolicy {
+		t.Errorf("expected new deliver policy, got %v", config.DeliverPolicy)
+	}
+
+	if config.AckWait != 30*time.Second {
+		t.Errorf("expected ack wait of 30s, got %v", config.AckWait)
+	}
+}
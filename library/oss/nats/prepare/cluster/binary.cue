@@ -0,0 +1,4 @@
+binary: {
+	name: "nats-prepare-cluster"
+	from: go_package: "."
+}
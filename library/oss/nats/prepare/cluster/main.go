@@ -0,0 +1,39 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"namespacelabs.dev/foundation/framework/resources"
+	"namespacelabs.dev/foundation/framework/resources/provider"
+	natsclass "namespacelabs.dev/foundation/library/messaging/nats"
+	natsprovider "namespacelabs.dev/foundation/library/oss/nats"
+)
+
+const providerPkg = "namespacelabs.dev/foundation/library/oss/nats"
+
+func main() {
+	_, p := provider.MustPrepare[*natsprovider.ClusterIntent]()
+
+	endpoint, err := resources.LookupServerEndpoint(p.Resources, fmt.Sprintf("%s:server", providerPkg), "nats")
+	if err != nil {
+		log.Fatalf("failed to get nats server endpoint: %v", err)
+	}
+
+	token, err := resources.ReadSecret(p.Resources, fmt.Sprintf("%s:authToken", providerPkg))
+	if err != nil {
+		log.Fatalf("failed to read nats auth token: %v", err)
+	}
+
+	instance := &natsclass.ClusterInstance{
+		Address:   endpoint,
+		AuthToken: string(token),
+		Url:       fmt.Sprintf("nats://%s", endpoint),
+	}
+
+	p.EmitResult(instance)
+}
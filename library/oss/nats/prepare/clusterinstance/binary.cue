@@ -0,0 +1,4 @@
+binary: {
+	name: "nats-prepare-clusterinstance"
+	from: go_package: "."
+}
@@ -0,0 +1,4 @@
+binary: {
+	name: "nats-prepare-consumer"
+	from: go_package: "."
+}
@@ -0,0 +1,62 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"namespacelabs.dev/foundation/framework/resources/provider"
+	natsclass "namespacelabs.dev/foundation/library/messaging/nats"
+	natsprovider "namespacelabs.dev/foundation/library/oss/nats"
+)
+
+const (
+	providerPkg    = "namespacelabs.dev/foundation/library/oss/nats"
+	connectTimeout = 30 * time.Second
+)
+
+func main() {
+	ctx, p := provider.MustPrepare[*natsprovider.ConsumerIntent]()
+
+	stream := &natsclass.StreamInstance{}
+	if err := p.Resources.Unmarshal(fmt.Sprintf("%s:stream", providerPkg), stream); err != nil {
+		log.Fatalf("unable to read required resource \"stream\": %v", err)
+	}
+
+	config, err := natsprovider.ConsumerConfig(p.Intent)
+	if err != nil {
+		log.Fatalf("invalid consumer intent: %v", err)
+	}
+
+	nc, err := nats.Connect(stream.Url, nats.Token(stream.AuthToken), nats.Timeout(connectTimeout))
+	if err != nil {
+		log.Fatalf("failed to connect to nats: %v", err)
+	}
+	defer nc.Close()
+
+	js, err := jetstream.New(nc)
+	if err != nil {
+		log.Fatalf("failed to create jetstream client: %v", err)
+	}
+
+	if _, err := js.CreateOrUpdateConsumer(ctx, stream.Name, config); err != nil {
+		log.Fatalf("failed to create consumer %q on stream %q: %v", config.Durable, stream.Name, err)
+	}
+
+	instance := &natsclass.ConsumerInstance{
+		Name:           config.Durable,
+		Stream:         stream.Name,
+		FilterSubject:  config.FilterSubject,
+		ClusterAddress: stream.ClusterAddress,
+		AuthToken:      stream.AuthToken,
+		Url:            stream.Url,
+	}
+
+	p.EmitResult(instance)
+}
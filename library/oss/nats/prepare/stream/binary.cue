@@ -0,0 +1,4 @@
+binary: {
+	name: "nats-prepare-stream"
+	from: go_package: "."
+}
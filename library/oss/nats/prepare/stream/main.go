@@ -0,0 +1,61 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"namespacelabs.dev/foundation/framework/resources/provider"
+	natsclass "namespacelabs.dev/foundation/library/messaging/nats"
+	natsprovider "namespacelabs.dev/foundation/library/oss/nats"
+)
+
+const (
+	providerPkg    = "namespacelabs.dev/foundation/library/oss/nats"
+	connectTimeout = 30 * time.Second
+)
+
+func main() {
+	ctx, p := provider.MustPrepare[*natsprovider.StreamIntent]()
+
+	cluster := &natsclass.ClusterInstance{}
+	if err := p.Resources.Unmarshal(fmt.Sprintf("%s:cluster", providerPkg), cluster); err != nil {
+		log.Fatalf("unable to read required resource \"cluster\": %v", err)
+	}
+
+	config, err := natsprovider.StreamConfig(p.Intent)
+	if err != nil {
+		log.Fatalf("invalid stream intent: %v", err)
+	}
+
+	nc, err := nats.Connect(cluster.Url, nats.Token(cluster.AuthToken), nats.Timeout(connectTimeout))
+	if err != nil {
+		log.Fatalf("failed to connect to nats: %v", err)
+	}
+	defer nc.Close()
+
+	js, err := jetstream.New(nc)
+	if err != nil {
+		log.Fatalf("failed to create jetstream client: %v", err)
+	}
+
+	if _, err := js.CreateOrUpdateStream(ctx, config); err != nil {
+		log.Fatalf("failed to create stream %q: %v", config.Name, err)
+	}
+
+	instance := &natsclass.StreamInstance{
+		Name:           config.Name,
+		Subjects:       config.Subjects,
+		ClusterAddress: cluster.Address,
+		AuthToken:      cluster.AuthToken,
+		Url:            cluster.Url,
+	}
+
+	p.EmitResult(instance)
+}
@@ -0,0 +1,53 @@
+providers: {
+	"namespacelabs.dev/foundation/library/messaging/nats:Stream": {
+		initializedWith: "namespacelabs.dev/foundation/library/oss/nats/prepare/stream"
+
+		intent: {
+			type:   "library.oss.nats.StreamIntent"
+			source: "./types.proto"
+		}
+
+		inputs: {
+			cluster: {
+				class:   "namespacelabs.dev/foundation/library/messaging/nats:Cluster"
+				default: ":colocated"
+			}
+		}
+	}
+
+	"namespacelabs.dev/foundation/library/messaging/nats:Consumer": {
+		initializedWith: "namespacelabs.dev/foundation/library/oss/nats/prepare/consumer"
+
+		intent: {
+			type:   "library.oss.nats.ConsumerIntent"
+			source: "./types.proto"
+		}
+
+		inputs: {
+			// The stream to consume from, e.g. `resources: stream: ":orders"`.
+			stream: {
+				class: "namespacelabs.dev/foundation/library/messaging/nats:Stream"
+			}
+		}
+	}
+
+	"namespacelabs.dev/foundation/library/messaging/nats:Cluster": {
+		initializedWith: "namespacelabs.dev/foundation/library/oss/nats/prepare/cluster"
+
+		intent: {
+			type:   "library.oss.nats.ClusterIntent"
+			source: "./types.proto"
+		}
+
+		resourcesFrom: "namespacelabs.dev/foundation/library/oss/nats/prepare/clusterinstance"
+
+		availableClasses: [
+			"namespacelabs.dev/foundation/library/runtime:Server",
+			"namespacelabs.dev/foundation/library/runtime:Secret",
+		]
+
+		availablePackages: [
+			"namespacelabs.dev/foundation/library/oss/nats/server",
+		]
+	}
+}
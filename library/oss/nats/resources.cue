@@ -0,0 +1,12 @@
+resources: {
+	// colocated represents a NATS cluster with JetStream enabled, that can be easily used by multiple users, within a single cluster.
+	colocated: {
+		class:    "namespacelabs.dev/foundation/library/messaging/nats:Cluster"
+		provider: "namespacelabs.dev/foundation/library/oss/nats"
+
+		intent: {
+			server:            "namespacelabs.dev/foundation/library/oss/nats/server"
+			auth_token_secret: "namespacelabs.dev/foundation/library/oss/nats/server:authToken"
+		}
+	}
+}
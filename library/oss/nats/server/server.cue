@@ -0,0 +1,14 @@
+import "namespacelabs.dev/foundation/library/oss/nats/templates"
+
+server: templates.#Server
+
+secrets: {
+	"authToken": {
+		description: "NATS authentication token"
+		generate: {
+			uniqueId:        "nats-auth-token"
+			randomByteCount: 32
+			format:          "FORMAT_BASE32"
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package templates
+
+#Server: {
+	spec: {
+		image:          *"nats:2.9.14@sha256:f14772ef64c223208b81b1e8ce213f3adc2260dd30517a35a3c0a3534074ac9a" | string
+		dataVolumeSize: *"10GiB" | string
+		dataVolume: *{
+			id:   "nats-server-data"
+			size: dataVolumeSize
+		} | {
+			id:   string
+			size: string
+		}
+		authSecret: *"namespacelabs.dev/foundation/library/oss/nats/server:authToken" | string
+	}
+
+	name: "nats-server"
+
+	image: spec.image
+
+	// NATS mounts a persistent volume which requires a stateful deployment (more conservative update strategy).
+	class: "stateful"
+
+	args: [
+		// Enable JetStream, persisting streams to the data volume.
+		"-js", "-sd", "/data",
+		"-m", "8222",
+
+		// Token value injected from environment variable by Kubernetes.
+		// https://kubernetes.io/docs/tasks/inject-data-application/define-command-argument-container/#use-environment-variables-to-define-arguments
+		"--auth", "$(NATS_AUTH_TOKEN)",
+	]
+
+	env: {
+		NATS_AUTH_TOKEN: fromSecret: spec.authSecret
+	}
+
+	services: {
+		nats: {
+			port: 4222
+			kind: "tcp"
+		}
+		monitoring: {
+			port: 8222
+			kind: "http"
+			probe: http: "/healthz?js-enabled-only=true"
+		}
+	}
+
+	mounts: {
+		// JetStream defaults to file storage, so streams must survive restarts.
+		"/data": persistent: spec.dataVolume
+	}
+}
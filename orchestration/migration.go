@@ -7,14 +7,18 @@ package orchestration
 import (
 	"context"
 	"fmt"
+	"os"
+	"os/user"
 	"time"
 
-	"github.com/slack-go/slack"
 	"namespacelabs.dev/foundation/internal/console"
 	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/internal/notifiers"
+	"namespacelabs.dev/foundation/internal/notifiers/slack"
 	"namespacelabs.dev/foundation/internal/planning/deploy"
 	"namespacelabs.dev/foundation/internal/runtime"
 	"namespacelabs.dev/foundation/schema"
+	orchpb "namespacelabs.dev/foundation/schema/orchestration"
 	"namespacelabs.dev/foundation/std/cfg"
 	"namespacelabs.dev/foundation/std/execution"
 )
@@ -23,6 +27,9 @@ import (
 // This is not related to the orchestrator service version.
 const executionVersion = 25
 
+// How long a deployment lock survives if it's not renewed, e.g. if ns exits unexpectedly.
+const deployLockTTL = time.Minute
+
 var DeployUpdateSlackChannel, SlackToken string
 
 func ExecuteOpts() execution.ExecuteOpts {
@@ -33,40 +40,169 @@ func ExecuteOpts() execution.ExecuteOpts {
 }
 
 func Deploy(ctx context.Context, env cfg.Context, cluster runtime.ClusterNamespace, plan *schema.DeployPlan, reason string, wait, outputProgress bool) error {
+	return DeployExt(ctx, env, cluster, plan, reason, wait, outputProgress, nil)
+}
+
+// DeployExt is like Deploy, but also calls onDeployed after a successful
+// deployment, while the deployment lock is still held.
+func DeployExt(ctx context.Context, env cfg.Context, cluster runtime.ClusterNamespace, plan *schema.DeployPlan, reason string, wait, outputProgress bool, onDeployed func(context.Context) error) error {
 	if !wait {
 		return fnerrors.BadInputError("waiting is mandatory")
 	}
 
-	observeError := func(context.Context, error) {}
-	if token, channel, err := resolveSlackTokenAndChannel(ctx, env); err != nil {
+	ns, err := prepareNotifiers(ctx, env)
+	if err != nil {
 		return err
-	} else if channel != "" {
-		if token == "" {
-			return fnerrors.BadInputError("a slack token is required to be able to update a channel")
-		}
+	}
 
-		start := time.Now()
-		slackcli := slack.New(token)
-		chid, ts, err := slackcli.PostMessageContext(ctx, channel, slack.MsgOptionBlocks(renderSlackMessage(plan, start, time.Time{}, reason, nil)...))
+	// Execution is aborted if the deployment lock is lost.
+	execCtx := ctx
+	release := func(context.Context) error { return nil }
+
+	if !env.Environment().GetEphemeral() {
+		lockCtx, releaseLock, err := cluster.LockDeployments(ctx, runtime.DeployLock{Holder: DeployAuthor(), Reason: reason}, deployLockTTL)
 		if err != nil {
-			fmt.Fprintf(console.Warnings(ctx), "Failed to post to Slack: %v\n", err)
-		} else {
-			observeError = func(ctx context.Context, err error) {
-				if _, _, _, err := slackcli.UpdateMessageContext(ctx, chid, ts, slack.MsgOptionBlocks(renderSlackMessage(plan, start, time.Now(), reason, err)...)); err != nil {
-					fmt.Fprintf(console.Warnings(ctx), "Failed to update Slack: %v\n", err)
-				}
-			}
+			return err
 		}
+
+		execCtx, release = lockCtx, releaseLock
 	}
 
+	ev := notifiers.Event{Plan: plan, Reason: reason, Started: time.Now()}
+	ns.Notify(ctx, withStage(ev, notifiers.StageStarted))
+
 	p := execution.NewPlan(plan.Program.Invocation...)
 
 	// Make sure that the cluster is accessible to a serialized invocation implementation.
-	execErr := execution.ExecuteExt(ctx, "deployment.execute", p,
-		deploy.MaybeRenderBlock(env, cluster, outputProgress),
+	execErr := execution.ExecuteExt(execCtx, "deployment.execute", p,
+		notifyProgress(deploy.MaybeRenderBlock(env, cluster, outputProgress), ns, ev),
 		ExecuteOpts(),
 		execution.FromContext(env),
 		runtime.InjectCluster(cluster))
-	observeError(ctx, execErr)
+
+	if execErr == nil && onDeployed != nil {
+		execErr = onDeployed(execCtx)
+	}
+
+	if execCtx.Err() != nil && ctx.Err() == nil {
+		execErr = context.Cause(execCtx)
+	}
+
+	if err := release(ctx); err != nil {
+		fmt.Fprintf(console.Warnings(ctx), "%v\n", err)
+	}
+
+	done := withStage(ev, notifiers.StageFinished)
+	done.Completed = time.Now()
+	done.Err = execErr
+	ns.Notify(ctx, done)
+
 	return execErr
 }
+
+func prepareNotifiers(ctx context.Context, env cfg.Context) (notifiers.Notifiers, error) {
+	var slackOverride notifiers.Notifier
+	if DeployUpdateSlackChannel != "" {
+		token := os.ExpandEnv(SlackToken)
+		if token == "" {
+			return nil, fnerrors.BadInputError("a slack token is required to be able to update a channel")
+		}
+
+		slackOverride = slack.New(token, os.ExpandEnv(DeployUpdateSlackChannel))
+	}
+
+	return notifiers.Prepare(ctx, env, slackOverride)
+}
+
+// How many progress notifications may be pending before new ones are dropped.
+const maxPendingProgress = 16
+
+// notifyProgress wraps a WaitHandler, and notifies whenever a resource becomes ready.
+// Notifications are sent from a separate goroutine, so that slow notifiers
+// don't stall the event stream.
+func notifyProgress(handler execution.WaitHandler, ns notifiers.Notifiers, ev notifiers.Event) execution.WaitHandler {
+	if len(ns) == 0 {
+		return handler
+	}
+
+	return func(ctx context.Context) (chan *orchpb.Event, func(context.Context) error) {
+		parent, wait := handler(ctx)
+
+		pending := make(chan notifiers.Event, maxPendingProgress)
+		notified := make(chan struct{})
+		go func() {
+			defer close(notified)
+
+			for progress := range pending {
+				ns.Notify(ctx, progress)
+			}
+		}()
+
+		ch := make(chan *orchpb.Event)
+		go func() {
+			defer close(parent)
+			defer close(pending)
+
+			ready := map[string]bool{}
+			for e := range ch {
+				parent <- e
+
+				if e.Ready == orchpb.Event_READY && !ready[e.ResourceId] {
+					ready[e.ResourceId] = true
+
+					label := e.ResourceLabel
+					if label == "" {
+						label = e.ResourceId
+					}
+
+					progress := withStage(ev, notifiers.StageProgress)
+					progress.Message = fmt.Sprintf("%s is ready (%d ready so far).", label, len(ready))
+
+					select {
+					case pending <- progress:
+					default:
+						fmt.Fprintf(console.Debug(ctx), "notifiers: dropped progress update: %s\n", progress.Message)
+					}
+				}
+			}
+		}()
+
+		return ch, func(ctx context.Context) error {
+			err := wait(ctx)
+
+			// Pending progress is delivered before the deployment is reported
+			// as finished.
+			select {
+			case <-notified:
+			case <-ctx.Done():
+			}
+
+			return err
+		}
+	}
+}
+
+// DeployAuthor returns who is triggering a deployment, for display purposes.
+func DeployAuthor() string {
+	for _, key := range []string{"BUILDKITE_BUILD_CREATOR", "GITHUB_ACTOR"} {
+		if v := os.Getenv(key); v != "" {
+			return v
+		}
+	}
+
+	var author string
+	if u, err := user.Current(); err == nil {
+		author = u.Username
+	}
+
+	if host, err := os.Hostname(); err == nil {
+		author += "@" + host
+	}
+
+	return author
+}
+
+func withStage(ev notifiers.Event, stage notifiers.Stage) notifiers.Event {
+	ev.Stage = stage
+	return ev
+}
@@ -583,6 +583,24 @@ type ImageBuildPlan_StaticFilesServer struct {
 	// Path to serve files from.
 	Dir  string `protobuf:"bytes,1,opt,name=dir,proto3" json:"dir,omitempty"`
 	Port int32  `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	// If set, files are served under this path (e.g. "/app"), rather than
+	// at the root.
+	BasePath string `protobuf:"bytes,3,opt,name=base_path,json=basePath,proto3" json:"base_path,omitempty"`
+	// By default, index.html is served for paths that don't exist, as
+	// single page apps expect. If set, a 404 is returned instead.
+	DisableSpaFallback bool `protobuf:"varint,4,opt,name=disable_spa_fallback,json=disableSpaFallback,proto3" json:"disable_spa_fallback,omitempty"`
+	// The longest matching path prefix determines a file's Cache-Control.
+	CacheControl []*ImageBuildPlan_StaticFilesServer_CacheControl `protobuf:"bytes,5,rep,name=cache_control,json=cacheControl,proto3" json:"cache_control,omitempty"`
+	// Precompressed variants which are served instead of a file when the
+	// client accepts them: "gzip" (file.gz) and "br" (file.br). Serving
+	// brotli requires an image built with ngx_brotli.
+	Precompressed []string `protobuf:"bytes,6,rep,name=precompressed,proto3" json:"precompressed,omitempty"`
+	// Headers added to every response, e.g. Content-Security-Policy.
+	Headers map[string]string `protobuf:"bytes,7,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Page to serve, by HTTP status code; e.g. 404: "/404.html".
+	ErrorPages map[int32]string `protobuf:"bytes,8,rep,name=error_pages,json=errorPages,proto3" json:"error_pages,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The nginx image to serve files with. Defaults to a pinned version.
+	Image string `protobuf:"bytes,9,opt,name=image,proto3" json:"image,omitempty"`
 }
 
 func (x *ImageBuildPlan_StaticFilesServer) Reset() {
@@ -631,6 +649,55 @@ func (x *ImageBuildPlan_StaticFilesServer) GetPort() int32 {
 	return 0
 }
 
+func (x *ImageBuildPlan_StaticFilesServer) GetBasePath() string {
+	if x != nil {
+		return x.BasePath
+	}
+	return ""
+}
+
+func (x *ImageBuildPlan_StaticFilesServer) GetDisableSpaFallback() bool {
+	if x != nil {
+		return x.DisableSpaFallback
+	}
+	return false
+}
+
+func (x *ImageBuildPlan_StaticFilesServer) GetCacheControl() []*ImageBuildPlan_StaticFilesServer_CacheControl {
+	if x != nil {
+		return x.CacheControl
+	}
+	return nil
+}
+
+func (x *ImageBuildPlan_StaticFilesServer) GetPrecompressed() []string {
+	if x != nil {
+		return x.Precompressed
+	}
+	return nil
+}
+
+func (x *ImageBuildPlan_StaticFilesServer) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *ImageBuildPlan_StaticFilesServer) GetErrorPages() map[int32]string {
+	if x != nil {
+		return x.ErrorPages
+	}
+	return nil
+}
+
+func (x *ImageBuildPlan_StaticFilesServer) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
 type ImageBuildPlan_FilesFrom struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -954,6 +1021,62 @@ func (x *ImageBuildPlan_MelangeBuild) GetPlatforms() []string {
 	return nil
 }
 
+type ImageBuildPlan_StaticFilesServer_CacheControl struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Relative to base_path, e.g. "/assets/".
+	PathPrefix string `protobuf:"bytes,1,opt,name=path_prefix,json=pathPrefix,proto3" json:"path_prefix,omitempty"`
+	Value      string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *ImageBuildPlan_StaticFilesServer_CacheControl) Reset() {
+	*x = ImageBuildPlan_StaticFilesServer_CacheControl{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_schema_binary_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImageBuildPlan_StaticFilesServer_CacheControl) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImageBuildPlan_StaticFilesServer_CacheControl) ProtoMessage() {}
+
+func (x *ImageBuildPlan_StaticFilesServer_CacheControl) ProtoReflect() protoreflect.Message {
+	mi := &file_schema_binary_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImageBuildPlan_StaticFilesServer_CacheControl.ProtoReflect.Descriptor instead.
+func (*ImageBuildPlan_StaticFilesServer_CacheControl) Descriptor() ([]byte, []int) {
+	return file_schema_binary_proto_rawDescGZIP(), []int{1, 3, 2}
+}
+
+func (x *ImageBuildPlan_StaticFilesServer_CacheControl) GetPathPrefix() string {
+	if x != nil {
+		return x.PathPrefix
+	}
+	return ""
+}
+
+func (x *ImageBuildPlan_StaticFilesServer_CacheControl) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
 type ImageBuildPlan_DockerBuild_AttrsByPlatform struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -966,7 +1089,7 @@ type ImageBuildPlan_DockerBuild_AttrsByPlatform struct {
 func (x *ImageBuildPlan_DockerBuild_AttrsByPlatform) Reset() {
 	*x = ImageBuildPlan_DockerBuild_AttrsByPlatform{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_schema_binary_proto_msgTypes[14]
+		mi := &file_schema_binary_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -979,7 +1102,7 @@ func (x *ImageBuildPlan_DockerBuild_AttrsByPlatform) String() string {
 func (*ImageBuildPlan_DockerBuild_AttrsByPlatform) ProtoMessage() {}
 
 func (x *ImageBuildPlan_DockerBuild_AttrsByPlatform) ProtoReflect() protoreflect.Message {
-	mi := &file_schema_binary_proto_msgTypes[14]
+	mi := &file_schema_binary_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1021,7 +1144,7 @@ type BinaryConfig_EnvEntry struct {
 func (x *BinaryConfig_EnvEntry) Reset() {
 	*x = BinaryConfig_EnvEntry{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_schema_binary_proto_msgTypes[16]
+		mi := &file_schema_binary_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1034,7 +1157,7 @@ func (x *BinaryConfig_EnvEntry) String() string {
 func (*BinaryConfig_EnvEntry) ProtoMessage() {}
 
 func (x *BinaryConfig_EnvEntry) ProtoReflect() protoreflect.Message {
-	mi := &file_schema_binary_proto_msgTypes[16]
+	mi := &file_schema_binary_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1092,7 +1215,7 @@ var file_schema_binary_proto_rawDesc = []byte{
 	0x18, 0x2e, 0x66, 0x6f, 0x75, 0x6e, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x73, 0x63, 0x68,
 	0x65, 0x6d, 0x61, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c,
 	0x73, 0x4a, 0x04, 0x08, 0x03, 0x10, 0x04, 0x4a, 0x04, 0x08, 0x04, 0x10, 0x05, 0x4a, 0x04, 0x08,
-	0x05, 0x10, 0x06, 0x4a, 0x04, 0x08, 0x07, 0x10, 0x08, 0x22, 0x83, 0x12, 0x0a, 0x0e, 0x49, 0x6d,
+	0x05, 0x10, 0x06, 0x4a, 0x04, 0x08, 0x07, 0x10, 0x08, 0x22, 0xfa, 0x16, 0x0a, 0x0e, 0x49, 0x6d,
 	0x61, 0x67, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x50, 0x6c, 0x61, 0x6e, 0x12, 0x20, 0x0a, 0x0b,
 	0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0c, 0x20, 0x01, 0x28,
 	0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d,
@@ -1171,100 +1294,139 @@ var file_schema_binary_proto_rawDesc = []byte{
 	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65,
 	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65,
 	0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x1a,
-	0x39, 0x0a, 0x11, 0x53, 0x74, 0x61, 0x74, 0x69, 0x63, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x53, 0x65,
-	0x72, 0x76, 0x65, 0x72, 0x12, 0x10, 0x0a, 0x03, 0x64, 0x69, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x03, 0x64, 0x69, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x1a, 0x77, 0x0a, 0x09, 0x46, 0x69,
-	0x6c, 0x65, 0x73, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x35, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x66, 0x6f, 0x75, 0x6e, 0x64, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x42,
-	0x75, 0x69, 0x6c, 0x64, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x14,
-	0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x66,
-	0x69, 0x6c, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x64,
-	0x69, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74,
-	0x44, 0x69, 0x72, 0x1a, 0x5d, 0x0a, 0x0c, 0x4d, 0x61, 0x6b, 0x65, 0x53, 0x71, 0x75, 0x61, 0x73,
-	0x68, 0x46, 0x53, 0x12, 0x35, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x21, 0x2e, 0x66, 0x6f, 0x75, 0x6e, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x73,
+	0xaf, 0x05, 0x0a, 0x11, 0x53, 0x74, 0x61, 0x74, 0x69, 0x63, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x53,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x12, 0x10, 0x0a, 0x03, 0x64, 0x69, 0x72, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x64, 0x69, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x62,
+	0x61, 0x73, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x62, 0x61, 0x73, 0x65, 0x50, 0x61, 0x74, 0x68, 0x12, 0x30, 0x0a, 0x14, 0x64, 0x69, 0x73, 0x61,
+	0x62, 0x6c, 0x65, 0x5f, 0x73, 0x70, 0x61, 0x5f, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x12, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x53,
+	0x70, 0x61, 0x46, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x12, 0x65, 0x0a, 0x0d, 0x63, 0x61,
+	0x63, 0x68, 0x65, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x18, 0x05, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x40, 0x2e, 0x66, 0x6f, 0x75, 0x6e, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x73,
 	0x63, 0x68, 0x65, 0x6d, 0x61, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64,
-	0x50, 0x6c, 0x61, 0x6e, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x61,
-	0x72, 0x67, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67,
-	0x65, 0x74, 0x1a, 0x9e, 0x01, 0x0a, 0x13, 0x4d, 0x61, 0x6b, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x73,
-	0x79, 0x73, 0x74, 0x65, 0x6d, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x35, 0x0a, 0x04, 0x66, 0x72,
+	0x50, 0x6c, 0x61, 0x6e, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x69, 0x63, 0x46, 0x69, 0x6c, 0x65, 0x73,
+	0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x43, 0x61, 0x63, 0x68, 0x65, 0x43, 0x6f, 0x6e, 0x74,
+	0x72, 0x6f, 0x6c, 0x52, 0x0c, 0x63, 0x61, 0x63, 0x68, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f,
+	0x6c, 0x12, 0x24, 0x0a, 0x0d, 0x70, 0x72, 0x65, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73,
+	0x65, 0x64, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x72, 0x65, 0x63, 0x6f, 0x6d,
+	0x70, 0x72, 0x65, 0x73, 0x73, 0x65, 0x64, 0x12, 0x5a, 0x0a, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x40, 0x2e, 0x66, 0x6f, 0x75, 0x6e, 0x64,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x2e, 0x49, 0x6d, 0x61,
+	0x67, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x50, 0x6c, 0x61, 0x6e, 0x2e, 0x53, 0x74, 0x61, 0x74,
+	0x69, 0x63, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x48, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x68, 0x65, 0x61, 0x64,
+	0x65, 0x72, 0x73, 0x12, 0x64, 0x0a, 0x0b, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x70, 0x61, 0x67,
+	0x65, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x43, 0x2e, 0x66, 0x6f, 0x75, 0x6e, 0x64,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x2e, 0x49, 0x6d, 0x61,
+	0x67, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x50, 0x6c, 0x61, 0x6e, 0x2e, 0x53, 0x74, 0x61, 0x74,
+	0x69, 0x63, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x45, 0x72,
+	0x72, 0x6f, 0x72, 0x50, 0x61, 0x67, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0a, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x50, 0x61, 0x67, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6d, 0x61,
+	0x67, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x1a,
+	0x3a, 0x0a, 0x0c, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3d, 0x0a, 0x0f, 0x45,
+	0x72, 0x72, 0x6f, 0x72, 0x50, 0x61, 0x67, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x45, 0x0a, 0x0c, 0x43, 0x61,
+	0x63, 0x68, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x61,
+	0x74, 0x68, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0a, 0x70, 0x61, 0x74, 0x68, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x1a, 0x77, 0x0a, 0x09, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x35,
+	0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x66,
+	0x6f, 0x75, 0x6e, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61,
+	0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x50, 0x6c, 0x61, 0x6e, 0x52,
+	0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x44, 0x69, 0x72, 0x1a, 0x5d, 0x0a, 0x0c, 0x4d, 0x61,
+	0x6b, 0x65, 0x53, 0x71, 0x75, 0x61, 0x73, 0x68, 0x46, 0x53, 0x12, 0x35, 0x0a, 0x04, 0x66, 0x72,
 	0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x66, 0x6f, 0x75, 0x6e, 0x64,
 	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x2e, 0x49, 0x6d, 0x61,
 	0x67, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x04, 0x66, 0x72, 0x6f,
 	0x6d, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e,
-	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x12, 0x0a,
-	0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x73, 0x69, 0x7a,
-	0x65, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x61, 0x77, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x03,
-	0x72, 0x61, 0x77, 0x1a, 0x8d, 0x04, 0x0a, 0x0b, 0x44, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x42, 0x75,
-	0x69, 0x6c, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x66, 0x69, 0x6c,
-	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x66,
-	0x69, 0x6c, 0x65, 0x12, 0x69, 0x0a, 0x11, 0x61, 0x74, 0x74, 0x72, 0x73, 0x5f, 0x62, 0x79, 0x5f,
-	0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x3d,
-	0x2e, 0x66, 0x6f, 0x75, 0x6e, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x73, 0x63, 0x68, 0x65,
-	0x6d, 0x61, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x50, 0x6c, 0x61,
-	0x6e, 0x2e, 0x44, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x41, 0x74,
-	0x74, 0x72, 0x73, 0x42, 0x79, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x52, 0x0f, 0x61,
-	0x74, 0x74, 0x72, 0x73, 0x42, 0x79, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x4e,
-	0x0a, 0x05, 0x61, 0x74, 0x74, 0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x38, 0x2e,
-	0x66, 0x6f, 0x75, 0x6e, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x6d,
-	0x61, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x50, 0x6c, 0x61, 0x6e,
-	0x2e, 0x44, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x41, 0x74, 0x74,
-	0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x61, 0x74, 0x74, 0x72, 0x73, 0x12, 0x1f,
-	0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x04, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x44, 0x69, 0x72, 0x1a,
-	0x38, 0x0a, 0x0a, 0x41, 0x74, 0x74, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
-	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
-	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0xc7, 0x01, 0x0a, 0x0f, 0x41, 0x74,
-	0x74, 0x72, 0x73, 0x42, 0x79, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x1a, 0x0a,
-	0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x5e, 0x0a, 0x05, 0x61, 0x74, 0x74,
-	0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x48, 0x2e, 0x66, 0x6f, 0x75, 0x6e, 0x64,
-	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x2e, 0x49, 0x6d, 0x61,
-	0x67, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x50, 0x6c, 0x61, 0x6e, 0x2e, 0x44, 0x6f, 0x63, 0x6b,
-	0x65, 0x72, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x41, 0x74, 0x74, 0x72, 0x73, 0x42, 0x79, 0x50,
-	0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x41, 0x74, 0x74, 0x72, 0x73, 0x45, 0x6e, 0x74,
-	0x72, 0x79, 0x52, 0x05, 0x61, 0x74, 0x74, 0x72, 0x73, 0x1a, 0x38, 0x0a, 0x0a, 0x41, 0x74, 0x74,
-	0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
-	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
-	0x02, 0x38, 0x01, 0x1a, 0x42, 0x0a, 0x0c, 0x4d, 0x65, 0x6c, 0x61, 0x6e, 0x67, 0x65, 0x42, 0x75,
-	0x69, 0x6c, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
-	0x28, 0x09, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x6c, 0x61,
-	0x74, 0x66, 0x6f, 0x72, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x70, 0x6c,
-	0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x73, 0x4a, 0x04, 0x08, 0x03, 0x10, 0x04, 0x4a, 0x04, 0x08,
-	0x09, 0x10, 0x0a, 0x4a, 0x04, 0x08, 0x0e, 0x10, 0x0f, 0x4a, 0x04, 0x08, 0x10, 0x10, 0x11, 0x22,
-	0x64, 0x0a, 0x15, 0x4c, 0x61, 0x79, 0x65, 0x72, 0x65, 0x64, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x42,
-	0x75, 0x69, 0x6c, 0x64, 0x50, 0x6c, 0x61, 0x6e, 0x12, 0x4b, 0x0a, 0x10, 0x6c, 0x61, 0x79, 0x65,
-	0x72, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x70, 0x6c, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x21, 0x2e, 0x66, 0x6f, 0x75, 0x6e, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e,
-	0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x42, 0x75, 0x69, 0x6c,
-	0x64, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x0e, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x42, 0x75, 0x69, 0x6c,
-	0x64, 0x50, 0x6c, 0x61, 0x6e, 0x22, 0x9f, 0x02, 0x0a, 0x0c, 0x42, 0x69, 0x6e, 0x61, 0x72, 0x79,
-	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
-	0x64, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
-	0x12, 0x12, 0x0a, 0x04, 0x61, 0x72, 0x67, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04,
-	0x61, 0x72, 0x67, 0x73, 0x12, 0x3a, 0x0a, 0x03, 0x65, 0x6e, 0x76, 0x18, 0x03, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x28, 0x2e, 0x66, 0x6f, 0x75, 0x6e, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x73,
-	0x63, 0x68, 0x65, 0x6d, 0x61, 0x2e, 0x42, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x43, 0x6f, 0x6e, 0x66,
-	0x69, 0x67, 0x2e, 0x45, 0x6e, 0x76, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x03, 0x65, 0x6e, 0x76,
-	0x12, 0x1f, 0x0a, 0x0b, 0x77, 0x6f, 0x72, 0x6b, 0x69, 0x6e, 0x67, 0x5f, 0x64, 0x69, 0x72, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x77, 0x6f, 0x72, 0x6b, 0x69, 0x6e, 0x67, 0x44, 0x69,
-	0x72, 0x1a, 0x83, 0x01, 0x0a, 0x08, 0x45, 0x6e, 0x76, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x12,
-	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
-	0x6d, 0x65, 0x12, 0x33, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x1d, 0x2e, 0x66, 0x6f, 0x75, 0x6e, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x73,
-	0x63, 0x68, 0x65, 0x6d, 0x61, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x61, 0x62, 0x6c, 0x65,
-	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x4a, 0x04, 0x08, 0x02, 0x10, 0x03, 0x4a, 0x04, 0x08,
-	0x03, 0x10, 0x04, 0x4a, 0x04, 0x08, 0x04, 0x10, 0x05, 0x4a, 0x04, 0x08, 0x05, 0x10, 0x06, 0x4a,
-	0x04, 0x08, 0x06, 0x10, 0x07, 0x4a, 0x04, 0x08, 0x07, 0x10, 0x08, 0x4a, 0x04, 0x08, 0x08, 0x10,
-	0x09, 0x4a, 0x04, 0x08, 0x09, 0x10, 0x0a, 0x42, 0x25, 0x5a, 0x23, 0x6e, 0x61, 0x6d, 0x65, 0x73,
-	0x70, 0x61, 0x63, 0x65, 0x6c, 0x61, 0x62, 0x73, 0x2e, 0x64, 0x65, 0x76, 0x2f, 0x66, 0x6f, 0x75,
-	0x6e, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x62, 0x06,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x09, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x1a, 0x9e, 0x01, 0x0a, 0x13, 0x4d, 0x61,
+	0x6b, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x49, 0x6d, 0x61, 0x67,
+	0x65, 0x12, 0x35, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x21, 0x2e, 0x66, 0x6f, 0x75, 0x6e, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x73, 0x63, 0x68,
+	0x65, 0x6d, 0x61, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x50, 0x6c,
+	0x61, 0x6e, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x61, 0x72, 0x67,
+	0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6b, 0x69, 0x6e, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x61, 0x77, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x03, 0x72, 0x61, 0x77, 0x1a, 0x8d, 0x04, 0x0a, 0x0b, 0x44,
+	0x6f, 0x63, 0x6b, 0x65, 0x72, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x6f,
+	0x63, 0x6b, 0x65, 0x72, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x69, 0x0a, 0x11, 0x61, 0x74,
+	0x74, 0x72, 0x73, 0x5f, 0x62, 0x79, 0x5f, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x3d, 0x2e, 0x66, 0x6f, 0x75, 0x6e, 0x64, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x42,
+	0x75, 0x69, 0x6c, 0x64, 0x50, 0x6c, 0x61, 0x6e, 0x2e, 0x44, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x42,
+	0x75, 0x69, 0x6c, 0x64, 0x2e, 0x41, 0x74, 0x74, 0x72, 0x73, 0x42, 0x79, 0x50, 0x6c, 0x61, 0x74,
+	0x66, 0x6f, 0x72, 0x6d, 0x52, 0x0f, 0x61, 0x74, 0x74, 0x72, 0x73, 0x42, 0x79, 0x50, 0x6c, 0x61,
+	0x74, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x4e, 0x0a, 0x05, 0x61, 0x74, 0x74, 0x72, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x38, 0x2e, 0x66, 0x6f, 0x75, 0x6e, 0x64, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x42, 0x75,
+	0x69, 0x6c, 0x64, 0x50, 0x6c, 0x61, 0x6e, 0x2e, 0x44, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x42, 0x75,
+	0x69, 0x6c, 0x64, 0x2e, 0x41, 0x74, 0x74, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05,
+	0x61, 0x74, 0x74, 0x72, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74,
+	0x5f, 0x64, 0x69, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x74,
+	0x65, 0x78, 0x74, 0x44, 0x69, 0x72, 0x1a, 0x38, 0x0a, 0x0a, 0x41, 0x74, 0x74, 0x72, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x1a, 0xc7, 0x01, 0x0a, 0x0f, 0x41, 0x74, 0x74, 0x72, 0x73, 0x42, 0x79, 0x50, 0x6c, 0x61, 0x74,
+	0x66, 0x6f, 0x72, 0x6d, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d,
+	0x12, 0x5e, 0x0a, 0x05, 0x61, 0x74, 0x74, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x48, 0x2e, 0x66, 0x6f, 0x75, 0x6e, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x73, 0x63, 0x68,
+	0x65, 0x6d, 0x61, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x50, 0x6c,
+	0x61, 0x6e, 0x2e, 0x44, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x41,
+	0x74, 0x74, 0x72, 0x73, 0x42, 0x79, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x41,
+	0x74, 0x74, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x61, 0x74, 0x74, 0x72, 0x73,
+	0x1a, 0x38, 0x0a, 0x0a, 0x41, 0x74, 0x74, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x42, 0x0a, 0x0c, 0x4d, 0x65,
+	0x6c, 0x61, 0x6e, 0x67, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69,
+	0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73,
+	0x12, 0x1c, 0x0a, 0x09, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x09, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x73, 0x4a, 0x04,
+	0x08, 0x03, 0x10, 0x04, 0x4a, 0x04, 0x08, 0x09, 0x10, 0x0a, 0x4a, 0x04, 0x08, 0x0e, 0x10, 0x0f,
+	0x4a, 0x04, 0x08, 0x10, 0x10, 0x11, 0x22, 0x64, 0x0a, 0x15, 0x4c, 0x61, 0x79, 0x65, 0x72, 0x65,
+	0x64, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x50, 0x6c, 0x61, 0x6e, 0x12,
+	0x4b, 0x0a, 0x10, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x70,
+	0x6c, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x66, 0x6f, 0x75, 0x6e,
+	0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x2e, 0x49, 0x6d,
+	0x61, 0x67, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x0e, 0x6c, 0x61,
+	0x79, 0x65, 0x72, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x50, 0x6c, 0x61, 0x6e, 0x22, 0x9f, 0x02, 0x0a,
+	0x0c, 0x42, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x18, 0x0a,
+	0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07,
+	0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x72, 0x67, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x61, 0x72, 0x67, 0x73, 0x12, 0x3a, 0x0a, 0x03, 0x65,
+	0x6e, 0x76, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x66, 0x6f, 0x75, 0x6e, 0x64,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x2e, 0x42, 0x69, 0x6e,
+	0x61, 0x72, 0x79, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x45, 0x6e, 0x76, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x52, 0x03, 0x65, 0x6e, 0x76, 0x12, 0x1f, 0x0a, 0x0b, 0x77, 0x6f, 0x72, 0x6b, 0x69,
+	0x6e, 0x67, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x77, 0x6f,
+	0x72, 0x6b, 0x69, 0x6e, 0x67, 0x44, 0x69, 0x72, 0x1a, 0x83, 0x01, 0x0a, 0x08, 0x45, 0x6e, 0x76,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x33, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x66, 0x6f, 0x75, 0x6e, 0x64,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x2e, 0x52, 0x65, 0x73,
+	0x6f, 0x6c, 0x76, 0x61, 0x62, 0x6c, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x4a, 0x04,
+	0x08, 0x02, 0x10, 0x03, 0x4a, 0x04, 0x08, 0x03, 0x10, 0x04, 0x4a, 0x04, 0x08, 0x04, 0x10, 0x05,
+	0x4a, 0x04, 0x08, 0x05, 0x10, 0x06, 0x4a, 0x04, 0x08, 0x06, 0x10, 0x07, 0x4a, 0x04, 0x08, 0x07,
+	0x10, 0x08, 0x4a, 0x04, 0x08, 0x08, 0x10, 0x09, 0x4a, 0x04, 0x08, 0x09, 0x10, 0x0a, 0x42, 0x25,
+	0x5a, 0x23, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x6c, 0x61, 0x62, 0x73, 0x2e,
+	0x64, 0x65, 0x76, 0x2f, 0x66, 0x6f, 0x75, 0x6e, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x73,
+	0x63, 0x68, 0x65, 0x6d, 0x61, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1279,57 +1441,63 @@ func file_schema_binary_proto_rawDescGZIP() []byte {
 	return file_schema_binary_proto_rawDescData
 }
 
-var file_schema_binary_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_schema_binary_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_schema_binary_proto_goTypes = []interface{}{
-	(*Binary)(nil),                                     // 0: foundation.schema.Binary
-	(*ImageBuildPlan)(nil),                             // 1: foundation.schema.ImageBuildPlan
-	(*LayeredImageBuildPlan)(nil),                      // 2: foundation.schema.LayeredImageBuildPlan
-	(*BinaryConfig)(nil),                               // 3: foundation.schema.BinaryConfig
-	(*ImageBuildPlan_LLBPlan)(nil),                     // 4: foundation.schema.ImageBuildPlan.LLBPlan
-	(*ImageBuildPlan_GoBuild)(nil),                     // 5: foundation.schema.ImageBuildPlan.GoBuild
-	(*ImageBuildPlan_AlpineBuild)(nil),                 // 6: foundation.schema.ImageBuildPlan.AlpineBuild
-	(*ImageBuildPlan_StaticFilesServer)(nil),           // 7: foundation.schema.ImageBuildPlan.StaticFilesServer
-	(*ImageBuildPlan_FilesFrom)(nil),                   // 8: foundation.schema.ImageBuildPlan.FilesFrom
-	(*ImageBuildPlan_MakeSquashFS)(nil),                // 9: foundation.schema.ImageBuildPlan.MakeSquashFS
-	(*ImageBuildPlan_MakeFilesystemImage)(nil),         // 10: foundation.schema.ImageBuildPlan.MakeFilesystemImage
-	(*ImageBuildPlan_DockerBuild)(nil),                 // 11: foundation.schema.ImageBuildPlan.DockerBuild
-	(*ImageBuildPlan_MelangeBuild)(nil),                // 12: foundation.schema.ImageBuildPlan.MelangeBuild
-	nil,                                                // 13: foundation.schema.ImageBuildPlan.DockerBuild.AttrsEntry
-	(*ImageBuildPlan_DockerBuild_AttrsByPlatform)(nil), // 14: foundation.schema.ImageBuildPlan.DockerBuild.AttrsByPlatform
-	nil,                           // 15: foundation.schema.ImageBuildPlan.DockerBuild.AttrsByPlatform.AttrsEntry
-	(*BinaryConfig_EnvEntry)(nil), // 16: foundation.schema.BinaryConfig.EnvEntry
-	(*Label)(nil),                 // 17: foundation.schema.Label
-	(*PackageRef)(nil),            // 18: foundation.schema.PackageRef
-	(*Resolvable)(nil),            // 19: foundation.schema.Resolvable
+	(*Binary)(nil),                                        // 0: foundation.schema.Binary
+	(*ImageBuildPlan)(nil),                                // 1: foundation.schema.ImageBuildPlan
+	(*LayeredImageBuildPlan)(nil),                         // 2: foundation.schema.LayeredImageBuildPlan
+	(*BinaryConfig)(nil),                                  // 3: foundation.schema.BinaryConfig
+	(*ImageBuildPlan_LLBPlan)(nil),                        // 4: foundation.schema.ImageBuildPlan.LLBPlan
+	(*ImageBuildPlan_GoBuild)(nil),                        // 5: foundation.schema.ImageBuildPlan.GoBuild
+	(*ImageBuildPlan_AlpineBuild)(nil),                    // 6: foundation.schema.ImageBuildPlan.AlpineBuild
+	(*ImageBuildPlan_StaticFilesServer)(nil),              // 7: foundation.schema.ImageBuildPlan.StaticFilesServer
+	(*ImageBuildPlan_FilesFrom)(nil),                      // 8: foundation.schema.ImageBuildPlan.FilesFrom
+	(*ImageBuildPlan_MakeSquashFS)(nil),                   // 9: foundation.schema.ImageBuildPlan.MakeSquashFS
+	(*ImageBuildPlan_MakeFilesystemImage)(nil),            // 10: foundation.schema.ImageBuildPlan.MakeFilesystemImage
+	(*ImageBuildPlan_DockerBuild)(nil),                    // 11: foundation.schema.ImageBuildPlan.DockerBuild
+	(*ImageBuildPlan_MelangeBuild)(nil),                   // 12: foundation.schema.ImageBuildPlan.MelangeBuild
+	nil,                                                   // 13: foundation.schema.ImageBuildPlan.StaticFilesServer.HeadersEntry
+	nil,                                                   // 14: foundation.schema.ImageBuildPlan.StaticFilesServer.ErrorPagesEntry
+	(*ImageBuildPlan_StaticFilesServer_CacheControl)(nil), // 15: foundation.schema.ImageBuildPlan.StaticFilesServer.CacheControl
+	nil, // 16: foundation.schema.ImageBuildPlan.DockerBuild.AttrsEntry
+	(*ImageBuildPlan_DockerBuild_AttrsByPlatform)(nil), // 17: foundation.schema.ImageBuildPlan.DockerBuild.AttrsByPlatform
+	nil,                           // 18: foundation.schema.ImageBuildPlan.DockerBuild.AttrsByPlatform.AttrsEntry
+	(*BinaryConfig_EnvEntry)(nil), // 19: foundation.schema.BinaryConfig.EnvEntry
+	(*Label)(nil),                 // 20: foundation.schema.Label
+	(*PackageRef)(nil),            // 21: foundation.schema.PackageRef
+	(*Resolvable)(nil),            // 22: foundation.schema.Resolvable
 }
 var file_schema_binary_proto_depIdxs = []int32{
 	3,  // 0: foundation.schema.Binary.config:type_name -> foundation.schema.BinaryConfig
 	2,  // 1: foundation.schema.Binary.build_plan:type_name -> foundation.schema.LayeredImageBuildPlan
-	17, // 2: foundation.schema.Binary.labels:type_name -> foundation.schema.Label
+	20, // 2: foundation.schema.Binary.labels:type_name -> foundation.schema.Label
 	5,  // 3: foundation.schema.ImageBuildPlan.go_build:type_name -> foundation.schema.ImageBuildPlan.GoBuild
 	11, // 4: foundation.schema.ImageBuildPlan.docker_build:type_name -> foundation.schema.ImageBuildPlan.DockerBuild
 	4,  // 5: foundation.schema.ImageBuildPlan.llb_plan:type_name -> foundation.schema.ImageBuildPlan.LLBPlan
-	18, // 6: foundation.schema.ImageBuildPlan.binary:type_name -> foundation.schema.PackageRef
+	21, // 6: foundation.schema.ImageBuildPlan.binary:type_name -> foundation.schema.PackageRef
 	7,  // 7: foundation.schema.ImageBuildPlan.static_files_server:type_name -> foundation.schema.ImageBuildPlan.StaticFilesServer
 	6,  // 8: foundation.schema.ImageBuildPlan.alpine_build:type_name -> foundation.schema.ImageBuildPlan.AlpineBuild
 	8,  // 9: foundation.schema.ImageBuildPlan.files_from:type_name -> foundation.schema.ImageBuildPlan.FilesFrom
 	10, // 10: foundation.schema.ImageBuildPlan.make_fs_image:type_name -> foundation.schema.ImageBuildPlan.MakeFilesystemImage
 	12, // 11: foundation.schema.ImageBuildPlan.melange_build:type_name -> foundation.schema.ImageBuildPlan.MelangeBuild
 	1,  // 12: foundation.schema.LayeredImageBuildPlan.layer_build_plan:type_name -> foundation.schema.ImageBuildPlan
-	16, // 13: foundation.schema.BinaryConfig.env:type_name -> foundation.schema.BinaryConfig.EnvEntry
+	19, // 13: foundation.schema.BinaryConfig.env:type_name -> foundation.schema.BinaryConfig.EnvEntry
 	0,  // 14: foundation.schema.ImageBuildPlan.LLBPlan.output_of:type_name -> foundation.schema.Binary
-	1,  // 15: foundation.schema.ImageBuildPlan.FilesFrom.from:type_name -> foundation.schema.ImageBuildPlan
-	1,  // 16: foundation.schema.ImageBuildPlan.MakeSquashFS.from:type_name -> foundation.schema.ImageBuildPlan
-	1,  // 17: foundation.schema.ImageBuildPlan.MakeFilesystemImage.from:type_name -> foundation.schema.ImageBuildPlan
-	14, // 18: foundation.schema.ImageBuildPlan.DockerBuild.attrs_by_platform:type_name -> foundation.schema.ImageBuildPlan.DockerBuild.AttrsByPlatform
-	13, // 19: foundation.schema.ImageBuildPlan.DockerBuild.attrs:type_name -> foundation.schema.ImageBuildPlan.DockerBuild.AttrsEntry
-	15, // 20: foundation.schema.ImageBuildPlan.DockerBuild.AttrsByPlatform.attrs:type_name -> foundation.schema.ImageBuildPlan.DockerBuild.AttrsByPlatform.AttrsEntry
-	19, // 21: foundation.schema.BinaryConfig.EnvEntry.value:type_name -> foundation.schema.Resolvable
-	22, // [22:22] is the sub-list for method output_type
-	22, // [22:22] is the sub-list for method input_type
-	22, // [22:22] is the sub-list for extension type_name
-	22, // [22:22] is the sub-list for extension extendee
-	0,  // [0:22] is the sub-list for field type_name
+	15, // 15: foundation.schema.ImageBuildPlan.StaticFilesServer.cache_control:type_name -> foundation.schema.ImageBuildPlan.StaticFilesServer.CacheControl
+	13, // 16: foundation.schema.ImageBuildPlan.StaticFilesServer.headers:type_name -> foundation.schema.ImageBuildPlan.StaticFilesServer.HeadersEntry
+	14, // 17: foundation.schema.ImageBuildPlan.StaticFilesServer.error_pages:type_name -> foundation.schema.ImageBuildPlan.StaticFilesServer.ErrorPagesEntry
+	1,  // 18: foundation.schema.ImageBuildPlan.FilesFrom.from:type_name -> foundation.schema.ImageBuildPlan
+	1,  // 19: foundation.schema.ImageBuildPlan.MakeSquashFS.from:type_name -> foundation.schema.ImageBuildPlan
+	1,  // 20: foundation.schema.ImageBuildPlan.MakeFilesystemImage.from:type_name -> foundation.schema.ImageBuildPlan
+	17, // 21: foundation.schema.ImageBuildPlan.DockerBuild.attrs_by_platform:type_name -> foundation.schema.ImageBuildPlan.DockerBuild.AttrsByPlatform
+	16, // 22: foundation.schema.ImageBuildPlan.DockerBuild.attrs:type_name -> foundation.schema.ImageBuildPlan.DockerBuild.AttrsEntry
+	18, // 23: foundation.schema.ImageBuildPlan.DockerBuild.AttrsByPlatform.attrs:type_name -> foundation.schema.ImageBuildPlan.DockerBuild.AttrsByPlatform.AttrsEntry
+	22, // 24: foundation.schema.BinaryConfig.EnvEntry.value:type_name -> foundation.schema.Resolvable
+	25, // [25:25] is the sub-list for method output_type
+	25, // [25:25] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
 }
 
 func init() { file_schema_binary_proto_init() }
@@ -1498,7 +1666,19 @@ func file_schema_binary_proto_init() {
 				return nil
 			}
 		}
-		file_schema_binary_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+		file_schema_binary_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImageBuildPlan_StaticFilesServer_CacheControl); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_schema_binary_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ImageBuildPlan_DockerBuild_AttrsByPlatform); i {
 			case 0:
 				return &v.state
@@ -1510,7 +1690,7 @@ func file_schema_binary_proto_init() {
 				return nil
 			}
 		}
-		file_schema_binary_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+		file_schema_binary_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*BinaryConfig_EnvEntry); i {
 			case 0:
 				return &v.state
@@ -1529,7 +1709,7 @@ func file_schema_binary_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_schema_binary_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   17,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
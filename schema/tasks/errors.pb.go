@@ -120,6 +120,54 @@ func (x *ErrorDetail_OriginalErrors) GetStatus() []*status.Status {
 	return nil
 }
 
+// Attached to status protos when the error carries a stable code (see `ns explain-error`).
+type ErrorDetail_Code struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+}
+
+func (x *ErrorDetail_Code) Reset() {
+	*x = ErrorDetail_Code{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_schema_tasks_errors_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ErrorDetail_Code) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorDetail_Code) ProtoMessage() {}
+
+func (x *ErrorDetail_Code) ProtoReflect() protoreflect.Message {
+	mi := &file_schema_tasks_errors_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorDetail_Code.ProtoReflect.Descriptor instead.
+func (*ErrorDetail_Code) Descriptor() ([]byte, []int) {
+	return file_schema_tasks_errors_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ErrorDetail_Code) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
 var File_schema_tasks_errors_proto protoreflect.FileDescriptor
 
 var file_schema_tasks_errors_proto_rawDesc = []byte{
@@ -135,11 +183,13 @@ var file_schema_tasks_errors_proto_rawDesc = []byte{
 	0x6c, 0x5f, 0x4f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73,
 	0x12, 0x2a, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
 	0x32, 0x12, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x74,
-	0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x42, 0x2b, 0x5a, 0x29,
-	0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x6c, 0x61, 0x62, 0x73, 0x2e, 0x64, 0x65,
-	0x76, 0x2f, 0x66, 0x6f, 0x75, 0x6e, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x73, 0x63, 0x68,
-	0x65, 0x6d, 0x61, 0x2f, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x33,
+	0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x26, 0x0a, 0x10,
+	0x45, 0x72, 0x72, 0x6f, 0x72, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x5f, 0x43, 0x6f, 0x64, 0x65,
+	0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x63, 0x6f, 0x64, 0x65, 0x42, 0x2b, 0x5a, 0x29, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63,
+	0x65, 0x6c, 0x61, 0x62, 0x73, 0x2e, 0x64, 0x65, 0x76, 0x2f, 0x66, 0x6f, 0x75, 0x6e, 0x64, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x2f, 0x74, 0x61, 0x73, 0x6b,
+	0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -154,14 +204,15 @@ func file_schema_tasks_errors_proto_rawDescGZIP() []byte {
 	return file_schema_tasks_errors_proto_rawDescData
 }
 
-var file_schema_tasks_errors_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_schema_tasks_errors_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_schema_tasks_errors_proto_goTypes = []interface{}{
 	(*ErrorDetail_ActionID)(nil),       // 0: foundation.schema.tasks.ErrorDetail_ActionID
 	(*ErrorDetail_OriginalErrors)(nil), // 1: foundation.schema.tasks.ErrorDetail_OriginalErrors
-	(*status.Status)(nil),              // 2: google.rpc.Status
+	(*ErrorDetail_Code)(nil),           // 2: foundation.schema.tasks.ErrorDetail_Code
+	(*status.Status)(nil),              // 3: google.rpc.Status
 }
 var file_schema_tasks_errors_proto_depIdxs = []int32{
-	2, // 0: foundation.schema.tasks.ErrorDetail_OriginalErrors.status:type_name -> google.rpc.Status
+	3, // 0: foundation.schema.tasks.ErrorDetail_OriginalErrors.status:type_name -> google.rpc.Status
 	1, // [1:1] is the sub-list for method output_type
 	1, // [1:1] is the sub-list for method input_type
 	1, // [1:1] is the sub-list for extension type_name
@@ -199,6 +250,18 @@ func file_schema_tasks_errors_proto_init() {
 				return nil
 			}
 		}
+		file_schema_tasks_errors_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ErrorDetail_Code); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -206,7 +269,7 @@ func file_schema_tasks_errors_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_schema_tasks_errors_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -310,6 +310,17 @@ _#ConfigureBase: {
 		alpine_build?: {package?: [...string]}
 		files?: [...string]
 		snapshot_files?: [...string]
+		static_files_server?: {
+			dir:                   string
+			port:                  int
+			base_path?:            string
+			disable_spa_fallback?: bool
+			cache_control?: [...{path_prefix: string, value: string}]
+			precompressed?: [..."gzip" | "br"]
+			headers?: [string]:     string
+			error_pages?: [string]: string
+			image?: string
+		}
 	}
 
 	config?: {
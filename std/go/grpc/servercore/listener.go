@@ -36,6 +36,7 @@ import (
 	gogrpc "namespacelabs.dev/foundation/std/go/grpc"
 	"namespacelabs.dev/foundation/std/go/http/middleware"
 	"namespacelabs.dev/foundation/std/grpc/requestid"
+	"namespacelabs.dev/foundation/std/runtime/constants"
 )
 
 type HTTPOptions struct {
@@ -227,6 +228,11 @@ func Listen(ctx context.Context, opts ListenOpts, registerServices func(Server))
 	debugMux := mux.NewRouter()
 	core.RegisterDebugEndpoints(debugMux)
 
+	// Unauthenticated, so only exposed to tests.
+	if core.EnvPurpose() == schema.Environment_TESTING {
+		debugMux.Handle(constants.LameduckPath, lameduckHandler(gogrpc.BeginLameduck))
+	}
+
 	cancelCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 	eg, egCtx := errgroup.WithContext(cancelCtx)
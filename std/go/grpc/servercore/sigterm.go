@@ -6,6 +6,7 @@ package servercore
 
 import (
 	"context"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -42,6 +43,26 @@ func runShutdownPhases(waitForReadinessPropagation func(), beginLameduck func()
 	}
 }
 
+// lameduckHandler begins the lameduck phase on demand, without terminating
+// the server, so that tests can observe how clients react to it.
+func lameduckHandler(beginLameduck func() map[string]func()) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(rw, "expected POST", http.StatusMethodNotAllowed)
+			return
+		}
+
+		core.ZLog.Info().Msg("lameduck requested")
+
+		for name, f := range beginLameduck() {
+			core.ZLog.Info().Str("name", name).Msg("running lameduck func")
+			f()
+		}
+
+		rw.WriteHeader(http.StatusOK)
+	})
+}
+
 func handleGracefulShutdown(ctx context.Context, finishShutdown func()) {
 	sigint := make(chan os.Signal, 1)
 	signal.Notify(sigint, os.Interrupt, syscall.SIGTERM)
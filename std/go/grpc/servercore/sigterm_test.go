@@ -5,6 +5,8 @@
 package servercore
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"sync"
 	"testing"
@@ -131,3 +133,27 @@ func sameSet(a, b []string) bool {
 	}
 	return true
 }
+
+func TestLameduckHandler(t *testing.T) {
+	var began int
+	ran := map[string]bool{}
+
+	handler := lameduckHandler(func() map[string]func() {
+		began++
+		return map[string]func(){
+			"lame.a": func() { ran["lame.a"] = true },
+		}
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/lameduck", nil))
+	if rec.Code != http.StatusMethodNotAllowed || began != 0 {
+		t.Fatalf("expected GET to be rejected, got %d (began %d times)", rec.Code, began)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/lameduck", nil))
+	if rec.Code != http.StatusOK || began != 1 || !ran["lame.a"] {
+		t.Fatalf("expected lameduck to begin, got %d (began %d times, ran %v)", rec.Code, began, ran)
+	}
+}
@@ -6,8 +6,12 @@ package plugandplay
 
 import (
 	_ "namespacelabs.dev/foundation/internal/artifacts/registry" // For type.googleapis.com/foundation.build.registry.Registry
+	githubnotifier "namespacelabs.dev/foundation/internal/notifiers/github"
+	slacknotifier "namespacelabs.dev/foundation/internal/notifiers/slack"
+	"namespacelabs.dev/foundation/internal/notifiers/webhook"
 	"namespacelabs.dev/foundation/internal/parsing/devhost"
 	_ "namespacelabs.dev/foundation/internal/planning/deploy" // For foundation.framework.deploy.Deployment
+	_ "namespacelabs.dev/foundation/internal/policy"          // For foundation.framework.policy.Policy
 	"namespacelabs.dev/foundation/internal/providers/aws/ecr"
 	"namespacelabs.dev/foundation/internal/providers/aws/eks"
 	"namespacelabs.dev/foundation/internal/providers/gcp/gke"
@@ -38,4 +42,8 @@ func RegisterProviders() {
 	vault.Register()
 
 	kubernetes.Register()
+
+	webhook.Register()
+	githubnotifier.Register()
+	slacknotifier.Register()
 }
@@ -0,0 +1,9 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package constants
+
+// In testing environments, POSTing to this path of a server's internal HTTP
+// endpoint begins its lameduck phase, without terminating it.
+const LameduckPath = "/debug/lameduck"
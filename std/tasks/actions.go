@@ -64,6 +64,7 @@ type EventData struct {
 	Level          int
 	HasPrivateData bool
 	Indefinite     bool
+	LeaseWait      time.Duration // How long the action was held back by the throttler.
 	Err            error
 }
 
@@ -305,8 +306,12 @@ func (ev *ActionEvent) RunWithOpts(ctx context.Context, opts RunOpts) error {
 		}
 
 		// Classify the wait for lease time as "wait time".
-		var err error
-		releaseLease, err = throttlerFromContext(ctx).AcquireLease(ctx, map[WellKnown]string{WkAction: ev.data.Name})
+		leaseStart := time.Now()
+		release, waited, err := throttlerFromContext(ctx).AcquireLease(ctx, map[WellKnown]string{WkAction: ev.data.Name})
+		if waited {
+			ra.Data.LeaseWait = time.Since(leaseStart)
+		}
+		releaseLease = release
 		return err
 	})
 	if err != nil {
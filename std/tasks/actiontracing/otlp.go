@@ -0,0 +1,163 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package actiontracing
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc/credentials"
+	"namespacelabs.dev/foundation/internal/fnerrors"
+)
+
+const (
+	ProtocolGRPC = "grpc"
+	ProtocolHTTP = "http/protobuf"
+)
+
+type OTLPConfig struct {
+	// Either host:port, or a URL (e.g. https://collector:4318/v1/traces).
+	Endpoint string
+	// Either "grpc" (the default) or "http/protobuf".
+	Protocol string
+	Headers  map[string]string
+	Insecure bool
+	// Optional PEM-encoded files used to verify the collector, and to
+	// authenticate with it.
+	CACertFile     string
+	ClientCertFile string
+	ClientKeyFile  string
+	// The fraction of traces that are recorded, between 0 and 1.
+	SamplingRatio float64
+}
+
+func SetupOTLPTracing(ctx context.Context, conf OTLPConfig) (context.Context, func(), error) {
+	exp, err := CreateOTLPExporter(ctx, conf)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var opts []tracesdk.TracerProviderOption
+	if conf.SamplingRatio < 1 {
+		opts = append(opts, tracesdk.WithSampler(tracesdk.ParentBased(tracesdk.TraceIDRatioBased(conf.SamplingRatio))))
+	}
+
+	spanCtx, cleanup := SetupTracing(ctx, CreateTracerForExporter(exp, opts...))
+	return spanCtx, cleanup, nil
+}
+
+func CreateOTLPExporter(ctx context.Context, conf OTLPConfig) (*otlptrace.Exporter, error) {
+	var tlsConf *tls.Config
+	if !conf.Insecure {
+		var err error
+		tlsConf, err = makeTLSConfig(conf)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	isURL := strings.Contains(conf.Endpoint, "://")
+
+	switch conf.Protocol {
+	case "", ProtocolGRPC:
+		var opts []otlptracegrpc.Option
+		if isURL {
+			opts = append(opts, otlptracegrpc.WithEndpointURL(conf.Endpoint))
+		} else {
+			opts = append(opts, otlptracegrpc.WithEndpoint(conf.Endpoint))
+		}
+
+		if len(conf.Headers) > 0 {
+			opts = append(opts, otlptracegrpc.WithHeaders(conf.Headers))
+		}
+
+		if conf.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		} else {
+			opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(tlsConf)))
+		}
+
+		return otlptrace.New(ctx, otlptracegrpc.NewClient(opts...))
+
+	case ProtocolHTTP:
+		var opts []otlptracehttp.Option
+		if isURL {
+			opts = append(opts, otlptracehttp.WithEndpointURL(conf.Endpoint))
+		} else {
+			opts = append(opts, otlptracehttp.WithEndpoint(conf.Endpoint))
+		}
+
+		if len(conf.Headers) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(conf.Headers))
+		}
+
+		if conf.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		} else {
+			opts = append(opts, otlptracehttp.WithTLSClientConfig(tlsConf))
+		}
+
+		return otlptrace.New(ctx, otlptracehttp.NewClient(opts...))
+
+	default:
+		return nil, fnerrors.BadInputError("unsupported otlp protocol %q (expected %q or %q)", conf.Protocol, ProtocolGRPC, ProtocolHTTP)
+	}
+}
+
+func makeTLSConfig(conf OTLPConfig) (*tls.Config, error) {
+	tlsConf := &tls.Config{}
+
+	if conf.CACertFile != "" {
+		pem, err := os.ReadFile(conf.CACertFile)
+		if err != nil {
+			return nil, fnerrors.BadInputError("failed to read otlp ca certificate: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fnerrors.BadInputError("%s: no certificates found", conf.CACertFile)
+		}
+
+		tlsConf.RootCAs = pool
+	}
+
+	if conf.ClientCertFile != "" || conf.ClientKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(conf.ClientCertFile, conf.ClientKeyFile)
+		if err != nil {
+			return nil, fnerrors.BadInputError("failed to load otlp client certificate: %w", err)
+		}
+
+		tlsConf.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConf, nil
+}
+
+// ParseHeaders parses a comma-separated list of key=value pairs, in the same
+// format as OTEL_EXPORTER_OTLP_HEADERS.
+func ParseHeaders(value string) (map[string]string, error) {
+	headers := map[string]string{}
+	for _, kv := range strings.Split(value, ",") {
+		kv = strings.TrimSpace(kv)
+		if kv == "" {
+			continue
+		}
+
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			return nil, fnerrors.BadInputError("invalid otlp header %q, expected key=value", kv)
+		}
+
+		headers[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return headers, nil
+}
@@ -7,6 +7,8 @@ package actiontracing
 import (
 	"context"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/otel"
@@ -14,6 +16,7 @@ import (
 	"go.opentelemetry.io/otel/exporters/jaeger"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	tracesdk "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
@@ -21,8 +24,15 @@ import (
 	"google.golang.org/grpc/credentials"
 )
 
-var Tracer trace.Tracer
+var (
+	Tracer trace.Tracer
 
+	// Set when tracing is enabled.
+	tracerProvider trace.TracerProvider
+)
+
+// Deprecated: the Jaeger exporter is no longer maintained upstream; Jaeger
+// accepts OTLP natively, prefer SetupOTLPTracing.
 func SetupJaegerTracing(ctx context.Context, jaegerEndpoint string) (context.Context, func()) {
 	tp, err := createTracer(jaegerEndpoint)
 	if err != nil {
@@ -36,8 +46,10 @@ func SetupTracing(ctx context.Context, tp *tracesdk.TracerProvider) (context.Con
 	// Register our TracerProvider as the global so any imported
 	// instrumentation in the future will default to using it.
 	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
 
 	Tracer = tp.Tracer("foundation")
+	tracerProvider = tp
 
 	spanCtx, span := Tracer.Start(ctx, "ns (cli invocation)")
 
@@ -59,7 +71,7 @@ func createTracer(url string) (*tracesdk.TracerProvider, error) {
 	return CreateTracerForExporter(exp), nil
 }
 
-func CreateTracerForExporter(exp tracesdk.SpanExporter) *tracesdk.TracerProvider {
+func CreateTracerForExporter(exp tracesdk.SpanExporter, opts ...tracesdk.TracerProviderOption) *tracesdk.TracerProvider {
 	attrs := []attribute.KeyValue{
 		semconv.ServiceName("foundation"),
 	}
@@ -68,10 +80,49 @@ func CreateTracerForExporter(exp tracesdk.SpanExporter) *tracesdk.TracerProvider
 		attrs = append(attrs, semconv.DeploymentEnvironmentName(env))
 	}
 
-	return tracesdk.NewTracerProvider(
+	return tracesdk.NewTracerProvider(append([]tracesdk.TracerProviderOption{
 		tracesdk.WithBatcher(exp, tracesdk.WithMaxExportBatchSize(1)),
 		tracesdk.WithResource(resource.NewWithAttributes(semconv.SchemaURL, attrs...)),
-	)
+	}, opts...)...)
+}
+
+// TracerProvider returns the tracer provider that was configured with
+// SetupTracing, or nil if tracing is not enabled.
+func TracerProvider() trace.TracerProvider {
+	return tracerProvider
+}
+
+// The environment variables which propagate the trace context to child
+// processes, following OpenTelemetry's conventions.
+var PropagatedEnv = []string{"TRACEPARENT", "TRACESTATE"}
+
+// Environ returns the environment variables that propagate the trace context
+// of ctx to a child process.
+func Environ(ctx context.Context) []string {
+	var env []string
+	for name, value := range EnvironMap(ctx) {
+		env = append(env, name+"="+value)
+	}
+	slices.Sort(env)
+	return env
+}
+
+// EnvironMap is like Environ, keyed by variable name.
+func EnvironMap(ctx context.Context) map[string]string {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return nil
+	}
+
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+
+	env := map[string]string{}
+	for _, name := range PropagatedEnv {
+		if v := carrier.Get(strings.ToLower(name)); v != "" {
+			env[name] = v
+		}
+	}
+	return env
 }
 
 func CreateHoneycombExporter(ctx context.Context, key string) (*otlptrace.Exporter, error) {
@@ -0,0 +1,371 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+// Package chrometrace records the action tree of an invocation, and writes it
+// out in Chrome's trace event format, which can be opened with Perfetto
+// (https://ui.perfetto.dev) or chrome://tracing.
+package chrometrace
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"namespacelabs.dev/foundation/internal/fnerrors"
+	"namespacelabs.dev/foundation/std/tasks"
+	"namespacelabs.dev/foundation/std/tasks/idtypes"
+)
+
+const (
+	catWaiting   = "waiting"
+	catThrottled = "throttled"
+	catCached    = "cached"
+	catAction    = "action"
+)
+
+var _ tasks.ActionSink = &Sink{}
+
+// Sink forwards all events to its parent, while keeping a copy of every
+// completed action.
+type Sink struct {
+	parent tasks.ActionSink
+
+	mu       sync.Mutex
+	actions  []tasks.EventData
+	instants []tasks.EventData
+}
+
+func NewSink(parent tasks.ActionSink) *Sink {
+	return &Sink{parent: parent}
+}
+
+func (s *Sink) Waiting(ra *tasks.RunningAction) {
+	if s.parent != nil {
+		s.parent.Waiting(ra)
+	}
+}
+
+func (s *Sink) Started(ra *tasks.RunningAction) {
+	if s.parent != nil {
+		s.parent.Started(ra)
+	}
+}
+
+func (s *Sink) Done(ra *tasks.RunningAction) {
+	if s.parent != nil {
+		s.parent.Done(ra)
+	}
+
+	s.mu.Lock()
+	s.actions = append(s.actions, copyData(ra.Data))
+	s.mu.Unlock()
+}
+
+func (s *Sink) Instant(ev *tasks.EventData) {
+	if s.parent != nil {
+		s.parent.Instant(ev)
+	}
+
+	s.mu.Lock()
+	s.instants = append(s.instants, copyData(*ev))
+	s.mu.Unlock()
+}
+
+func (s *Sink) AttachmentsUpdated(id tasks.ActionID, data *tasks.ResultData) {
+	if s.parent != nil {
+		s.parent.AttachmentsUpdated(id, data)
+	}
+}
+
+func (s *Sink) Output(name, contentType string, outputType idtypes.CatOutputType) io.Writer {
+	if s.parent != nil {
+		return s.parent.Output(name, contentType, outputType)
+	}
+	return nil
+}
+
+func (s *Sink) Unwrap() tasks.ActionSink {
+	return s.parent
+}
+
+// WriteFile serializes all of the actions that were recorded so far into path.
+func (s *Sink) WriteFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fnerrors.Newf("failed to create trace file: %w", err)
+	}
+
+	if err := s.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func (s *Sink) Write(w io.Writer) error {
+	s.mu.Lock()
+	actions := s.actions
+	instants := s.instants
+	s.mu.Unlock()
+
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(makeTrace(actions, instants)); err != nil {
+		return fnerrors.InternalError("failed to serialize trace: %w", err)
+	}
+
+	return nil
+}
+
+type traceFile struct {
+	TraceEvents     []traceEvent `json:"traceEvents"`
+	DisplayTimeUnit string       `json:"displayTimeUnit"`
+}
+
+type traceEvent struct {
+	Name      string         `json:"name"`
+	Category  string         `json:"cat,omitempty"`
+	Phase     string         `json:"ph"`
+	Timestamp int64          `json:"ts"` // In microseconds.
+	Duration  int64          `json:"dur,omitempty"`
+	PID       int            `json:"pid"`
+	TID       int            `json:"tid"`
+	Scope     string         `json:"s,omitempty"`
+	Args      map[string]any `json:"args,omitempty"`
+}
+
+type span struct {
+	name       string
+	category   string
+	start, end time.Time
+	args       map[string]any
+	lane       int
+}
+
+func makeTrace(actions, instants []tasks.EventData) traceFile {
+	var spans []*span
+	for _, data := range actions {
+		spans = append(spans, actionSpans(data)...)
+	}
+
+	assignLanes(spans)
+
+	var base time.Time
+	for _, s := range spans {
+		if base.IsZero() || s.start.Before(base) {
+			base = s.start
+		}
+	}
+	for _, ev := range instants {
+		if base.IsZero() || ev.Created.Before(base) {
+			base = ev.Created
+		}
+	}
+
+	tf := traceFile{DisplayTimeUnit: "ms"}
+	for _, s := range spans {
+		tf.TraceEvents = append(tf.TraceEvents, traceEvent{
+			Name:      s.name,
+			Category:  s.category,
+			Phase:     "X",
+			Timestamp: s.start.Sub(base).Microseconds(),
+			Duration:  s.end.Sub(s.start).Microseconds(),
+			PID:       1,
+			TID:       s.lane + 1,
+			Args:      s.args,
+		})
+	}
+
+	for _, ev := range instants {
+		tf.TraceEvents = append(tf.TraceEvents, traceEvent{
+			Name:      label(ev),
+			Category:  ev.Category,
+			Phase:     "i",
+			Timestamp: ev.Created.Sub(base).Microseconds(),
+			PID:       1,
+			Scope:     "g",
+			Args:      makeArgs(ev),
+		})
+	}
+
+	return tf
+}
+
+// actionSpans returns the span that represents the execution of the action,
+// and if the action was held back before starting, spans for the time it was
+// waiting, and the portion of that which was spent waiting for a throttle
+// lease.
+func actionSpans(data tasks.EventData) []*span {
+	if data.Completed.IsZero() {
+		return nil
+	}
+
+	started := data.Started
+	if started.IsZero() || started.Before(data.Created) {
+		started = data.Created
+	}
+
+	var spans []*span
+
+	if started.After(data.Created) {
+		spans = append(spans, &span{
+			name:     "waiting: " + label(data),
+			category: catWaiting,
+			start:    data.Created,
+			end:      started,
+			args:     map[string]any{"action_id": data.ActionID.String()},
+		})
+
+		if data.LeaseWait > 0 {
+			leaseStart := started.Add(-data.LeaseWait)
+			if leaseStart.Before(data.Created) {
+				leaseStart = data.Created
+			}
+
+			spans = append(spans, &span{
+				name:     "throttled: " + label(data),
+				category: catThrottled,
+				start:    leaseStart,
+				end:      started,
+				args:     map[string]any{"action_id": data.ActionID.String()},
+			})
+		}
+	}
+
+	category := data.Category
+	if category == "" {
+		category = catAction
+	}
+
+	if isCached(data) {
+		category = catCached
+	}
+
+	end := data.Completed
+	if end.Before(started) {
+		end = started
+	}
+
+	spans = append(spans, &span{
+		name:     label(data),
+		category: category,
+		start:    started,
+		end:      end,
+		args:     makeArgs(data),
+	})
+
+	return spans
+}
+
+// assignLanes places each span in a lane (a trace "thread"), such that spans
+// that share a lane are either disjoint or fully nested; which is what trace
+// viewers require of complete events in the same thread.
+func assignLanes(spans []*span) {
+	sort.SliceStable(spans, func(i, j int) bool {
+		if !spans[i].start.Equal(spans[j].start) {
+			return spans[i].start.Before(spans[j].start)
+		}
+		// Longer spans first, so that they become the enclosing span.
+		return spans[i].end.After(spans[j].end)
+	})
+
+	// For each lane, the end times of the currently open spans.
+	var lanes [][]time.Time
+
+	for _, s := range spans {
+		placed := false
+		for k, stack := range lanes {
+			// Spans are sorted by start time, so anything that ended before
+			// this span starts will not be relevant again.
+			for len(stack) > 0 && !stack[len(stack)-1].After(s.start) {
+				stack = stack[:len(stack)-1]
+			}
+
+			lanes[k] = stack
+
+			if len(stack) == 0 || !s.end.After(stack[len(stack)-1]) {
+				lanes[k] = append(stack, s.end)
+				s.lane = k
+				placed = true
+				break
+			}
+		}
+
+		if !placed {
+			lanes = append(lanes, []time.Time{s.end})
+			s.lane = len(lanes) - 1
+		}
+	}
+}
+
+func label(data tasks.EventData) string {
+	if data.HumanReadable != "" {
+		return data.HumanReadable
+	}
+
+	if data.Category != "" {
+		return data.Category + "::" + data.Name
+	}
+
+	return data.Name
+}
+
+func isCached(data tasks.EventData) bool {
+	for _, arg := range data.Arguments {
+		if arg.Name == "cached" && arg.Msg == true {
+			return true
+		}
+	}
+	return false
+}
+
+func makeArgs(data tasks.EventData) map[string]any {
+	args := map[string]any{
+		"action_id": data.ActionID.String(),
+		"name":      data.Name,
+	}
+
+	if data.ParentID != "" {
+		args["parent_id"] = data.ParentID.String()
+	}
+
+	if data.AnchorID != "" {
+		args["anchor_id"] = data.AnchorID.String()
+	}
+
+	if data.Scope.Len() > 0 {
+		args["scope"] = data.Scope.PackageNamesAsString()
+	}
+
+	if data.LeaseWait > 0 {
+		args["throttled_ms"] = data.LeaseWait.Milliseconds()
+	}
+
+	if data.Err != nil {
+		args["error"] = data.Err.Error()
+	}
+
+	if data.HasPrivateData {
+		return args
+	}
+
+	for _, arg := range data.Arguments {
+		if _, err := json.Marshal(arg.Msg); err == nil {
+			args[arg.Name] = arg.Msg
+		}
+	}
+
+	return args
+}
+
+// Our data model implies that the caller always owns data; and sinks should
+// perform copies.
+func copyData(data tasks.EventData) tasks.EventData {
+	copy := data
+	copy.Arguments = append([]tasks.ActionArgument(nil), data.Arguments...)
+	return copy
+}
@@ -0,0 +1,80 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package chrometrace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"namespacelabs.dev/foundation/std/tasks"
+)
+
+func TestAssignLanes(t *testing.T) {
+	base := time.Unix(1000, 0)
+	at := func(sec int) time.Time { return base.Add(time.Duration(sec) * time.Second) }
+
+	parent := &span{name: "parent", start: at(0), end: at(10)}
+	child := &span{name: "child", start: at(1), end: at(5)}
+	overlapping := &span{name: "overlapping", start: at(4), end: at(12)}
+	after := &span{name: "after", start: at(12), end: at(13)}
+
+	assignLanes([]*span{after, overlapping, child, parent})
+
+	if parent.lane != 0 || child.lane != 0 {
+		t.Errorf("expected parent and child to share a lane, got %d and %d", parent.lane, child.lane)
+	}
+
+	if overlapping.lane != 1 {
+		t.Errorf("expected overlapping span to get its own lane, got %d", overlapping.lane)
+	}
+
+	if after.lane != 0 {
+		t.Errorf("expected lane to be reused, got %d", after.lane)
+	}
+}
+
+func TestWriteTrace(t *testing.T) {
+	sink := NewSink(tasks.NullSink())
+	ctx := tasks.WithSink(context.Background(), sink)
+
+	if err := tasks.Action("test.action").Arg("key", "value").Run(ctx, func(ctx context.Context) error {
+		return tasks.Action("test.child").Run(ctx, func(context.Context) error { return nil })
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := sink.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var tf traceFile
+	if err := json.Unmarshal(buf.Bytes(), &tf); err != nil {
+		t.Fatal(err)
+	}
+
+	names := map[string]traceEvent{}
+	for _, ev := range tf.TraceEvents {
+		if ev.Category == catAction {
+			names[ev.Name] = ev
+		}
+	}
+
+	action, ok := names["test.action"]
+	if !ok {
+		t.Fatalf("missing test.action in %v", tf.TraceEvents)
+	}
+
+	if action.Args["key"] != "value" {
+		t.Errorf("expected argument to be recorded, got %v", action.Args)
+	}
+
+	if _, ok := names["test.child"]; !ok {
+		t.Errorf("missing test.child in %v", tf.TraceEvents)
+	}
+}
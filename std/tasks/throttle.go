@@ -108,14 +108,17 @@ func newThrottleState(debug io.Writer, confs []*ThrottleConfiguration) *throttle
 	return ts
 }
 
-func (ts *throttleState) AcquireLease(ctx context.Context, wellKnown map[WellKnown]string) (func(), error) {
+// AcquireLease blocks until there's capacity for an action with the specified
+// labels. Also returns whether the caller had to wait for capacity.
+func (ts *throttleState) AcquireLease(ctx context.Context, wellKnown map[WellKnown]string) (func(), bool, error) {
 	if ts == nil {
-		return nil, nil
+		return nil, false, nil
 	}
 
 	ts.mu.Lock()
 	defer ts.mu.Unlock()
 
+	var waited bool
 	for {
 		var needsCap bool
 		var incs, decs []func()
@@ -150,7 +153,7 @@ func (ts *throttleState) AcquireLease(ctx context.Context, wellKnown map[WellKno
 		}
 
 		if err := ctx.Err(); err != nil {
-			return nil, err
+			return nil, waited, err
 		}
 
 		if !needsCap {
@@ -165,9 +168,10 @@ func (ts *throttleState) AcquireLease(ctx context.Context, wellKnown map[WellKno
 				}
 				ts.cond.Broadcast()
 				ts.mu.Unlock()
-			}, nil
+			}, waited, nil
 		}
 
+		waited = true
 		ts.cond.Wait()
 	}
 }
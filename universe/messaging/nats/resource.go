@@ -0,0 +1,122 @@
+// Copyright 2022 Namespace Labs Inc; All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package nats
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"namespacelabs.dev/foundation/framework/resources"
+	natspb "namespacelabs.dev/foundation/library/messaging/nats"
+)
+
+// Connect to a NATS Cluster resource. The caller is responsible for closing
+// the returned connection.
+func ConnectToResource(ctx context.Context, res *resources.Parsed, resourceRef string, opts ...nats.Option) (*nats.Conn, error) {
+	cluster := &natspb.ClusterInstance{}
+	if err := res.Unmarshal(resourceRef, cluster); err != nil {
+		return nil, err
+	}
+
+	return connect(ctx, cluster.Url, cluster.AuthToken, opts)
+}
+
+// Connect to a NATS JetStream Stream resource. Closing the returned connection
+// releases the stream.
+func ConnectToStreamResource(ctx context.Context, res *resources.Parsed, resourceRef string, opts ...nats.Option) (jetstream.Stream, *nats.Conn, error) {
+	instance := &natspb.StreamInstance{}
+	if err := res.Unmarshal(resourceRef, instance); err != nil {
+		return nil, nil, err
+	}
+
+	nc, err := connect(ctx, instance.Url, instance.AuthToken, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	js, err := jetstream.New(nc)
+	if err != nil {
+		nc.Close()
+		return nil, nil, err
+	}
+
+	stream, err := js.Stream(ctx, instance.Name)
+	if err != nil {
+		nc.Close()
+		return nil, nil, err
+	}
+
+	return stream, nc, nil
+}
+
+// Connect to a NATS JetStream Consumer resource. Closing the returned
+// connection releases the consumer.
+func ConnectToConsumerResource(ctx context.Context, res *resources.Parsed, resourceRef string, opts ...nats.Option) (jetstream.Consumer, *nats.Conn, error) {
+	instance := &natspb.ConsumerInstance{}
+	if err := res.Unmarshal(resourceRef, instance); err != nil {
+		return nil, nil, err
+	}
+
+	nc, err := connect(ctx, instance.Url, instance.AuthToken, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	js, err := jetstream.New(nc)
+	if err != nil {
+		nc.Close()
+		return nil, nil, err
+	}
+
+	consumer, err := js.Consumer(ctx, instance.Stream, instance.Name)
+	if err != nil {
+		nc.Close()
+		return nil, nil, err
+	}
+
+	return consumer, nc, nil
+}
+
+func connect(ctx context.Context, url, token string, opts []nats.Option) (*nats.Conn, error) {
+	dialer := &ctxDialer{ctx: ctx}
+
+	options := append([]nats.Option{nats.Token(token)}, opts...)
+	// Last, so that the dialer observes the final timeout, and defers to a
+	// dialer set by the caller.
+	options = append(options, func(o *nats.Options) error {
+		if o.CustomDialer == nil {
+			dialer.dialer.Timeout = o.Timeout
+			o.CustomDialer = dialer
+		}
+		return nil
+	})
+
+	nc, err := nats.Connect(url, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer.connected.Store(true)
+	return nc, nil
+}
+
+// ctxDialer dials with ctx until the first connection is established.
+// Reconnections outlive ctx.
+type ctxDialer struct {
+	ctx       context.Context
+	dialer    net.Dialer
+	connected atomic.Bool
+}
+
+func (d *ctxDialer) Dial(network, address string) (net.Conn, error) {
+	if d.connected.Load() {
+		return d.dialer.Dial(network, address)
+	}
+
+	return d.dialer.DialContext(d.ctx, network, address)
+}